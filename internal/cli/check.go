@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -31,8 +32,22 @@ func runCheck(cmd *cobra.Command, args []string) error {
 
 	// Initialize services
 	filesystem := services.NewFilesystemService()
-	github := services.NewGitHubService()
-	environment := services.NewEnvironmentService(filesystem)
+	config, err := loadConfig(filesystem)
+	if err != nil {
+		return err
+	}
+	github, err := newGitHubService(config)
+	if err != nil {
+		return err
+	}
+	git, err := newGitService(config)
+	if err != nil {
+		return err
+	}
+	environment, err := newEnvironmentService(config, filesystem, git)
+	if err != nil {
+		return err
+	}
 
 	// Detect environment
 	env, err := environment.DetectEnvironment()
@@ -44,7 +59,7 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	showSystemInfo(env)
 
 	// Check internet connectivity
-	checkInternetConnectivity(github)
+	checkInternetConnectivity(github, env.Connectivity)
 
 	// Check required tools
 	checkRequiredTools(env)
@@ -68,22 +83,30 @@ func showSystemInfo(env *models.Environment) {
 	fmt.Println()
 }
 
-func checkInternetConnectivity(github *services.GitHubService) {
-	fmt.Printf("🌐 Internet Connectivity\n")
+func checkInternetConnectivity(github *services.GitHubService, report *models.ConnectivityReport) {
+	fmt.Printf("🌐 Template Source Connectivity\n")
 
-	err := github.CheckConnectivity()
-	if err != nil {
-		fmt.Printf("   ❌ No internet connection\n")
-		fmt.Printf("      %s\n", err.Error())
-	} else {
-		fmt.Printf("   ✅ Internet connection available\n")
+	for _, check := range report.Checks {
+		if check.OK {
+			fmt.Printf("   ✅ %s (%d, %s)\n", check.URL, check.Status, check.Duration.Round(time.Millisecond))
+		} else {
+			fmt.Printf("   ❌ %s: %s failure\n", check.URL, check.Failure)
+			fmt.Printf("      %s\n", check.Error)
+		}
+	}
 
+	if report.OK() {
 		// Get rate limit info
 		limit, remaining, resetTime, err := github.GetRateLimitInfo()
 		if err == nil {
 			fmt.Printf("      GitHub API: %d/%d requests remaining (resets at %s)\n",
 				remaining, limit, resetTime.Format("15:04:05"))
 		}
+		if source := github.TokenSource(); source != "" {
+			fmt.Printf("      Authenticated with %s\n", source)
+		} else {
+			fmt.Printf("      Anonymous: set GITHUB_TOKEN or GH_TOKEN, or run 'gh auth login', for 5000 requests/hour\n")
+		}
 	}
 	fmt.Println()
 }
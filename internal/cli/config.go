@@ -0,0 +1,411 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/euforicio/spec-kit/internal/models"
+	"github.com/euforicio/spec-kit/internal/services"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change project and user configuration",
+	Long: `View and change the configuration stored in .specify/config.yaml.
+
+The project file is found by walking up from the current directory. When it
+does not exist yet, 'specify config set' creates it at the repository root.
+With --global, commands operate on the user config at
+$XDG_CONFIG_HOME/specify/config.yaml (~/.config/specify/config.yaml) instead.
+
+Settings are resolved in this order, highest first:
+  1. Command-line flags (e.g. --ai)
+  2. SPECIFY_* environment variables (e.g. SPECIFY_HTTP_TIMEOUT=60s)
+  3. Project config (.specify/config.yaml)
+  4. User config ($XDG_CONFIG_HOME/specify/config.yaml)
+  5. Built-in defaults
+
+Keys:
+  specs_dir             Feature root directory (default: specs)
+  numbering.width       Zero-padded digits in feature numbers (default: 3)
+  numbering.prefix      Text before the feature number, e.g. FEAT-
+  numbering.reserve     Reserve new numbers by pushing a ref (default: false)
+  numbering.remote      Remote consulted for numbering and the default branch (default: origin)
+  branch.pattern        Branch naming pattern using {num}, {ticket}, {slug} and
+                        {date} (default: {num}-{slug})
+  branch.max_words      Words kept from the description in {slug} (default: 3)
+  branch.stopwords      Comma-separated words dropped from {slug}; "none" keeps all
+  branch.date_format    Go time layout for {date} (default: 20060102)
+  templates.spec        Spec template path, relative to the project root
+  templates.plan        Plan template path, relative to the project root
+  templates.agent_file  Agent context file template path
+  templates.trusted_keys Comma-separated minisign public keys trusted to sign
+                        template bundles; user config or environment only
+  agents                Comma-separated agents updated by 'feature context'
+  context.history       Features listed under Recent Changes (default: 3)
+  worktree.dir          Directory for 'feature create --worktree' (default: ../<repo>-worktrees)
+  git.backend           Git implementation: auto, native or exec (default: auto)
+  commit.auto           Commit spec artifacts after create, plan and context (default: false)
+  commit.author         Author of automatic commits, "Name <email>" (default: git user)
+  commit.trailers       Trailers for automatic commits, one per line, e.g. "Refs: PROJ-1"
+  lint.rules.<rule>     Severity of a 'feature lint' rule: error, warning or off
+                        (default: warning)
+  ai                    Default AI assistant for 'specify init'
+  cache_dir             Template cache directory (default: ~/.spec-kit/templates);
+                        user config or environment only
+  github.owner          Template repository owner (default: euforicio)
+  github.repo           Template repository name (default: spec-kit)
+  github.api_url        GitHub API URL; https://<host>/api/v3 for GitHub Enterprise
+                        (default: https://api.github.com); user config or environment only
+  http.timeout          HTTP request timeout (default: 30s)
+  http.download_timeout Timeout of each template download attempt (default: 10m)
+  http.proxy            Proxy URL for all requests (default: HTTPS_PROXY/HTTP_PROXY;
+                        NO_PROXY applies either way); user config or environment only
+  http.ca_bundle        PEM file of CA certificates trusted besides the system roots;
+                        user config or environment only
+  http.mirror           Mirror URL replacing https://<github host> in release
+                        asset downloads, e.g. https://artifacts.example.com/github;
+                        user config or environment only
+
+Each key can be overridden with an environment variable named after it:
+SPECIFY_ followed by the key in upper case with '.' and '-' replaced by '_'.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a configuration key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration key",
+	Long: `Set a configuration key and save the configuration file.
+
+The value is validated before the file is written. An empty value resets
+the key to its default.
+
+Examples:
+  specify config set numbering.width 4
+  specify config set agents claude,codex
+  specify config set lint.rules.needs-clarification error
+  specify config set branch.pattern ""
+  specify config set commit.trailers $'Refs: PROJ-1\nReviewed-by: Doe, Jane <jane@example.com>'
+  specify config set --global ai claude`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration and where each value comes from",
+	RunE:  runConfigShow,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configShowCmd)
+
+	configCmd.PersistentFlags().Bool("global", false, "Use the user config instead of the project config")
+	configShowCmd.Flags().Bool("json", false, "Output results in JSON format")
+}
+
+// loadConfig returns the effective configuration for the working directory
+func loadConfig(filesystem services.FilesystemServiceInterface) (*models.ProjectConfig, error) {
+	cwd, err := filesystem.GetWorkingDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	config, err := services.NewConfigService(filesystem).Load(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return config, nil
+}
+
+// newGitHubService creates a GitHub service for the configured template
+// repository and API server, authenticated with GITHUB_TOKEN, GH_TOKEN or the
+// gh CLI login
+func newGitHubService(config *models.ProjectConfig) (*services.GitHubService, error) {
+	github, err := services.NewGitHubServiceWithConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	github.SetUserAgent("specify-cli/" + version)
+	return github, nil
+}
+
+// gitHubRemote is the remote whose URL names the GitHub repository
+const gitHubRemote = "origin"
+
+// gitHubRepository resolves the GitHub repository from --repo or the origin
+// remote
+func gitHubRepository(cmd *cobra.Command, config *models.ProjectConfig) (*models.GitHubRepository, error) {
+	repo, err := cmd.Flags().GetString("repo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 'repo' flag: %w", err)
+	}
+
+	if repo == "" {
+		git, err := newGitService(config)
+		if err != nil {
+			return nil, err
+		}
+		if repo, err = git.GetConfig("remote." + gitHubRemote + ".url"); err != nil {
+			return nil, err
+		}
+		if repo == "" {
+			return nil, fmt.Errorf("no %s remote to take the GitHub repository from, use --repo owner/name", gitHubRemote)
+		}
+	}
+
+	return models.ParseGitHubRepository(repo)
+}
+
+// newEnvironmentService creates an environment service that probes the
+// configured template source through the configured proxy and CA bundle
+func newEnvironmentService(config *models.ProjectConfig, filesystem *services.FilesystemService, git services.GitServiceInterface) (*services.EnvironmentService, error) {
+	client, err := services.NewHTTPClient(config, services.ConnectivityTimeout)
+	if err != nil {
+		return nil, err
+	}
+	environment := services.NewEnvironmentService(filesystem, git)
+	environment.SetConnectivityProber(services.NewConnectivityProber(client, services.ConnectivityTargets(config)))
+	return environment, nil
+}
+
+// newGitService creates the git service selected by git.backend
+func newGitService(config *models.ProjectConfig) (services.GitServiceInterface, error) {
+	return services.NewGitServiceForBackend(config.GetGitBackend())
+}
+
+// newTemplateService creates a template service using the configured cache
+// directory and trusted signing keys
+func newTemplateService(config *models.ProjectConfig, github *services.GitHubService, filesystem *services.FilesystemService) *services.TemplateService {
+	template := services.NewTemplateServiceWithCacheRoot(github, filesystem, config.CacheDir)
+	template.SetTrustedKeys(config.Templates.TrustedKeys)
+	return template
+}
+
+// configFile is a configuration file edited by 'config get' and 'config set'
+type configFile struct {
+	config *models.ProjectConfig
+	save   func(*models.ProjectConfig) (string, error)
+}
+
+// openConfigFile loads the project config file, or the user config with --global
+func openConfigFile(cmd *cobra.Command) (*configFile, error) {
+	global, err := cmd.Flags().GetBool("global")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global flag: %w", err)
+	}
+
+	filesystem := services.NewFilesystemService()
+	configService := services.NewConfigService(filesystem)
+
+	if global {
+		config, err := configService.LoadUserConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load user config: %w", err)
+		}
+		return &configFile{config: config, save: configService.SaveUserConfig}, nil
+	}
+
+	cwd, err := filesystem.GetWorkingDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	config, err := configService.LoadProjectConfig(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	save := func(config *models.ProjectConfig) (string, error) {
+		// New config files go to the repository root, or the working directory outside git
+		root := config.Root
+		if root == "" {
+			git, gitErr := newGitService(config)
+			if gitErr != nil {
+				return "", gitErr
+			}
+			if root, err = git.GetRepoRoot(); err != nil {
+				root = cwd
+			}
+		}
+		return configService.SaveProjectConfig(root, config)
+	}
+
+	return &configFile{config: config, save: save}, nil
+}
+
+// withKeyHint lists the available keys when a key is unknown
+func withKeyHint(err error) error {
+	if errors.Is(err, models.ErrConfigUnknownKey) {
+		return fmt.Errorf("%w (available keys: %s)", err, strings.Join(models.ListConfigKeys(), ", "))
+	}
+	return err
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	global, err := cmd.Flags().GetBool("global")
+	if err != nil {
+		return fmt.Errorf("failed to get global flag: %w", err)
+	}
+
+	// Without --global, report the effective value including env overrides
+	var config *models.ProjectConfig
+	if global {
+		file, err := openConfigFile(cmd)
+		if err != nil {
+			return err
+		}
+		config = file.config
+	} else if config, err = loadConfig(services.NewFilesystemService()); err != nil {
+		return err
+	}
+
+	value, err := config.Get(args[0])
+	if err != nil {
+		return withKeyHint(err)
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	file, err := openConfigFile(cmd)
+	if err != nil {
+		return err
+	}
+
+	if global, _ := cmd.Flags().GetBool("global"); !global && models.IsUserOnlyConfigKey(args[0]) {
+		return fmt.Errorf("%w: %s can only be set in the user config; use --global", models.ErrConfigInvalid, args[0])
+	}
+	if err := file.config.Set(args[0], args[1]); err != nil {
+		return withKeyHint(err)
+	}
+
+	path, err := file.save(file.config)
+	if err != nil {
+		return err
+	}
+
+	value, _ := file.config.Get(args[0])
+	fmt.Printf("✅ %s = %s (%s)\n", args[0], value, path)
+
+	if env := models.ConfigEnvVar(args[0]); os.Getenv(env) != "" {
+		fmt.Printf("⚠️  %s is set and overrides this value\n", env)
+	}
+	return nil
+}
+
+// configValue is an effective configuration value and the layer it comes from
+type configValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source"` // default, user, project or env
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get json flag: %w", err)
+	}
+	global, err := cmd.Flags().GetBool("global")
+	if err != nil {
+		return fmt.Errorf("failed to get global flag: %w", err)
+	}
+
+	filesystem := services.NewFilesystemService()
+	configService := services.NewConfigService(filesystem)
+
+	cwd, err := filesystem.GetWorkingDirectory()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	userPath, err := configService.UserConfigPath()
+	if err != nil {
+		return err
+	}
+	projectPath, err := configService.FindProjectConfig(cwd)
+	if err != nil {
+		return err
+	}
+
+	effective, err := configService.Load(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	user, err := configService.LoadUserConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load user config: %w", err)
+	}
+	project, err := configService.LoadProjectConfig(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	values := make(map[string]configValue)
+	for _, key := range models.ListConfigKeys() {
+		if global {
+			value, _ := user.Get(key)
+			values[key] = configValue{Value: value, Source: configSource(key, user, nil)}
+			continue
+		}
+		value, _ := effective.Get(key)
+		values[key] = configValue{Value: value, Source: configSource(key, user, project)}
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(map[string]any{
+			"user_config":    userPath,
+			"project_config": projectPath,
+			"values":         values,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	fmt.Printf("# user config:    %s\n", userPath)
+	if projectPath == "" {
+		projectPath = "(none)"
+	}
+	fmt.Printf("# project config: %s\n", projectPath)
+	for _, key := range models.ListConfigKeys() {
+		// Multi-line values such as commit.trailers stay on one line
+		value := strings.ReplaceAll(values[key].Value, "\n", `\n`)
+		fmt.Printf("%s = %s  (%s)\n", key, value, values[key].Source)
+	}
+
+	return nil
+}
+
+// configSource reports which layer supplies the effective value of a key
+func configSource(key string, user, project *models.ProjectConfig) string {
+	if project != nil {
+		if os.Getenv(models.ConfigEnvVar(key)) != "" {
+			return "env"
+		}
+		if value, _ := project.GetRaw(key); value != "" {
+			return "project"
+		}
+	}
+	if value, _ := user.GetRaw(key); value != "" {
+		return "user"
+	}
+	return "default"
+}
@@ -3,11 +3,14 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/euforicio/spec-kit/internal/models"
 	"github.com/euforicio/spec-kit/internal/services"
+	"github.com/euforicio/spec-kit/internal/ui"
 )
 
 var featureCmd = &cobra.Command{
@@ -20,20 +23,48 @@ following the spec-driven development methodology.`,
 }
 
 var featureCreateCmd = &cobra.Command{
-	Use:   "create <description>",
+	Use:   "create [description]",
 	Short: "Create a new feature branch and directory structure",
 	Long: `Create a new feature with numbered branch, directory structure, and template.
 
 This command will:
-1. Find the next available feature number
-2. Create a new branch named with the number and description
+1. Find the next available feature number, past every feature in specs/, in
+   local and remote-tracking branches and in specs/ on the default branch
+2. Create a new branch named after the configured branch.pattern
 3. Create the feature directory structure in specs/
 4. Copy the spec template if available
 
+The branch starts from --from, or from the default branch (origin/HEAD, main or
+master). Uncommitted changes abort the command unless --stash is given, and an
+existing branch of the same name is an error.
+
+The branch pattern supports {num}, {ticket}, {slug} and {date}; patterns that
+use {ticket} require --ticket. Stopwords are dropped from the slug.
+
+Remote-tracking branches are only as fresh as the last 'git fetch'. With
+--reserve (or numbering.reserve: true) the number is also reserved by pushing
+refs/specify/reserved/<num> to numbering.remote, so two clones can never take
+the same number.
+
+With --from-issue owner/repo#123 the spec is seeded from a GitHub issue: its
+title names the branch (unless a description is given) and becomes the spec
+title and Input, its body the primary user story, and the issue link and labels
+are recorded below Input. Patterns using {ticket} get GH-123 unless --ticket is
+given. Private repositories need a token (GITHUB_TOKEN, GH_TOKEN or
+'gh auth login').
+
+With --worktree the branch is created in a new git worktree under worktree.dir
+(default: ../<repo>-worktrees) and the spec is seeded there, leaving the current
+checkout untouched. Remove it later with 'specify feature worktree remove'.
+
 Examples:
   specify feature create "user authentication system"
-  specify feature create "add payment processing"`,
-	Args: cobra.MinimumNArgs(1),
+  specify feature create "add payment processing"
+  specify feature create --ticket PROJ-1234 "export invoices as PDF"
+  specify feature create --from-issue acme/widgets#123
+  specify feature create --reserve "audit log"
+  specify feature create --worktree "billing export"
+  specify feature create --from release/2.0 --stash "hotfix rounding"`,
 	RunE: runFeatureCreate,
 }
 
@@ -48,7 +79,9 @@ This command will:
 3. Copy the plan template if available
 4. Return paths for LLM use
 
-Must be run from a feature branch (format: 001-feature-name).`,
+Runs against the feature of the current branch, or the one selected with
+--feature (or SPECIFY_FEATURE): a number (4 or 004), slug, ticket, directory
+name or path, e.g. --feature 004 or --feature specs/004-user-login.`,
 	RunE: runFeaturePlan,
 }
 
@@ -63,28 +96,53 @@ This command will:
 3. Verify the implementation plan exists
 4. List available design documents
 
-Must be run from a feature branch with existing plan.md.`,
+Runs against the feature of the current branch, or the one selected with
+--feature (or SPECIFY_FEATURE). The feature must have a plan.md.`,
 	RunE: runFeatureCheck,
 }
 
+var featureLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check a feature's spec, plan and tasks for common problems",
+	Long: `Check spec.md, plan.md and tasks.md of a feature against the lint rules:
+
+  needs-clarification  unresolved [NEEDS CLARIFICATION] markers
+  placeholder-text     template placeholders such as [FEATURE NAME] left in place
+  required-sections    mandatory spec or plan sections are missing
+  task-format          tasks.md entries without a T### task ID
+
+Each rule reports at the severity set with lint.rules.<rule> in the project
+config: error, warning (the default) or off. The command fails when any
+finding is an error.
+
+Runs against the feature of the current branch, or the one selected with
+--feature (or SPECIFY_FEATURE).`,
+	RunE: runFeatureLint,
+}
+
 var featureContextCmd = &cobra.Command{
 	Use:   "context [agent]",
 	Short: "Update agent context files based on feature plan",
 	Long: `Update AI agent context files based on the current feature plan.
 
 Supported agents: claude, gemini, copilot, codex
-If no agent is specified, updates all existing agent context files.
+If no agent is specified, updates all existing agent context files
+(CLAUDE.md, GEMINI.md, .github/copilot-instructions.md and AGENTS.md).
+If none exist yet, the file for the detected AI assistant is created.
 
 This command will:
 1. Read the current feature plan
-2. Extract technology information
+2. Extract technology information into specs/tech-profile.json
 3. Update the appropriate agent context files
-4. Preserve manual additions in context files
+4. List the most recent features under Recent Changes (see --history)
+5. Preserve manual additions in context files
 
 Examples:
   specify feature context           # Update all existing files
   specify feature context claude   # Update only CLAUDE.md
-  specify feature context gemini   # Update only GEMINI.md`,
+  specify feature context gemini   # Update only GEMINI.md
+  specify feature context codex    # Update only AGENTS.md
+  specify feature context --feature 004`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFeatureContext,
 }
@@ -102,35 +160,218 @@ This command outputs:
 - Implementation plan file
 - Tasks file
 
-Must be run from a valid feature branch.`,
+Runs against the feature of the current branch, or the one selected with
+--feature (or SPECIFY_FEATURE), so it also works on main, in detached HEAD
+checkouts and in trunk-based workflows.`,
 	RunE: runFeaturePaths,
 }
 
+var featureRenumberCmd = &cobra.Command{
+	Use:   "renumber",
+	Short: "Give the current feature a new number to resolve a collision",
+	Long: `Give the current feature a new number, renaming its branch and spec directory.
+
+Without --to, the feature is renumbered only when another feature (a spec
+directory, a local or remote-tracking branch, or a spec directory on the default
+branch) already uses its number; it then takes the next free number. References
+to the old name in the feature's markdown files are updated.
+
+The old branch is not deleted from the remote; push the new branch and remove
+the old one when you are ready.
+
+Examples:
+  specify feature renumber
+  specify feature renumber --to 12
+  specify feature renumber --reserve`,
+	Args: cobra.NoArgs,
+	RunE: runFeatureRenumber,
+}
+
+var featureWorktreeCmd = &cobra.Command{
+	Use:   "worktree",
+	Short: "Manage feature worktrees created with 'feature create --worktree'",
+}
+
+var featureWorktreeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List worktrees checked out on feature branches",
+	Args:  cobra.NoArgs,
+	RunE:  runFeatureWorktreeList,
+}
+
+var featureWorktreeRemoveCmd = &cobra.Command{
+	Use:   "remove <feature|path>",
+	Short: "Remove a feature worktree",
+	Long: `Remove the worktree of a feature, selected by path, branch, number or slug.
+
+Worktrees with uncommitted changes are only removed with --force. The feature
+branch is kept unless --delete-branch is given.
+
+Examples:
+  specify feature worktree remove 004
+  specify feature worktree remove ../my-repo-worktrees/004-user-login
+  specify feature worktree remove --delete-branch user-login`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFeatureWorktreeRemove,
+}
+
+var featureRenameCmd = &cobra.Command{
+	Use:   "rename <new description>",
+	Short: "Rename a feature's branch and spec directory together",
+	Long: `Rename the current feature, or the one selected with --feature.
+
+The slug is rebuilt from the new description while the number, ticket and date
+are kept. The branch and the spec directory are renamed together, references to
+the old name in the feature's markdown files are updated, and the spec title is
+set to the new description.
+
+Examples:
+  specify feature rename "passwordless sign in"
+  specify feature rename --feature 004 "audit trail"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runFeatureRename,
+}
+
+var featureArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Move a finished feature to specs/_archive",
+	Long: `Move the spec directory of the current feature, or the one selected with
+--feature, to specs/_archive/ and record the branch, its head commit, the time
+and an optional reason in archive.json.
+
+Archived features keep their numbers, so new features never reuse them. With
+--delete-branch the feature branch is deleted as well, but only when it is merged.
+
+Examples:
+  specify feature archive --feature 004 --reason "shipped in 2.3"
+  specify feature archive --feature 004 --delete-branch`,
+	Args: cobra.NoArgs,
+	RunE: runFeatureArchive,
+}
+
+var featureDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a feature's branch and spec directory",
+	Long: `Delete the current feature, or the one selected with --feature: its spec
+directory and its branch, merged or not. When the feature branch is checked out,
+the default branch is checked out first.
+
+The command asks for confirmation unless --yes is given.
+
+Examples:
+  specify feature delete --feature 004
+  specify feature delete --keep-branch --yes`,
+	Args: cobra.NoArgs,
+	RunE: runFeatureDelete,
+}
+
 func init() {
 	// Add feature subcommands
 	featureCmd.AddCommand(featureCreateCmd)
 	featureCmd.AddCommand(featurePlanCmd)
 	featureCmd.AddCommand(featureCheckCmd)
+	featureCmd.AddCommand(featureLintCmd)
 	featureCmd.AddCommand(featureContextCmd)
 	featureCmd.AddCommand(featurePathsCmd)
+	featureCmd.AddCommand(featureRenumberCmd)
+	featureCmd.AddCommand(featureWorktreeCmd)
+	featureCmd.AddCommand(featureRenameCmd)
+	featureCmd.AddCommand(featureArchiveCmd)
+	featureCmd.AddCommand(featureDeleteCmd)
+	featureWorktreeCmd.AddCommand(featureWorktreeListCmd)
+	featureWorktreeCmd.AddCommand(featureWorktreeRemoveCmd)
 
 	// Add flags
 	featureCreateCmd.Flags().Bool("json", false, "Output results in JSON format")
+	featureCreateCmd.Flags().String("ticket", "", "Issue key for branch patterns using {ticket}, e.g. PROJ-1234")
+	featureCreateCmd.Flags().Bool("reserve", false, "Reserve the feature number by pushing a ref (default: numbering.reserve)")
+	featureCreateCmd.Flags().String("from", "", "Ref to create the branch from (default: the default branch)")
+	featureCreateCmd.Flags().Bool("stash", false, "Stash uncommitted changes before switching branches")
+	featureCreateCmd.Flags().Bool("worktree", false, "Create the branch in a new git worktree instead of switching to it")
+	featureCreateCmd.Flags().String("from-issue", "", "Seed the spec from a GitHub issue, e.g. owner/repo#123")
 	featurePlanCmd.Flags().Bool("json", false, "Output results in JSON format")
 	featureCheckCmd.Flags().Bool("json", false, "Output results in JSON format")
+	featureLintCmd.Flags().Bool("json", false, "Output results in JSON format")
+	featureRenumberCmd.Flags().Int("to", 0, "New feature number (default: the next free number)")
+	featureRenumberCmd.Flags().Bool("reserve", false, "Reserve the new number by pushing a ref (default: numbering.reserve)")
+	featureRenumberCmd.Flags().Bool("json", false, "Output results in JSON format")
+	for _, cmd := range []*cobra.Command{featurePlanCmd, featureCheckCmd, featureLintCmd, featureContextCmd, featurePathsCmd,
+		featureRenameCmd, featureArchiveCmd, featureDeleteCmd} {
+		cmd.Flags().String("feature", "", "Feature number, slug or directory to use instead of the current branch (env: "+services.FeatureEnvVar+")")
+	}
+	featureWorktreeListCmd.Flags().Bool("json", false, "Output results in JSON format")
+	featureWorktreeRemoveCmd.Flags().Bool("force", false, "Remove the worktree even with uncommitted changes")
+	featureWorktreeRemoveCmd.Flags().Bool("delete-branch", false, "Also delete the feature branch")
+	featureRenameCmd.Flags().Bool("json", false, "Output results in JSON format")
+	featureArchiveCmd.Flags().String("reason", "", "Why the feature is archived, recorded in archive.json")
+	featureArchiveCmd.Flags().Bool("delete-branch", false, "Delete the feature branch if it is merged")
+	featureArchiveCmd.Flags().Bool("json", false, "Output results in JSON format")
+	featureDeleteCmd.Flags().Bool("keep-branch", false, "Delete only the spec directory")
+	featureDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	for _, cmd := range []*cobra.Command{featureCreateCmd, featurePlanCmd, featureContextCmd} {
+		cmd.Flags().Bool("commit", false, "Commit the generated spec artifacts, and nothing else (default: commit.auto)")
+	}
+	featureContextCmd.Flags().Int("history", 0, fmt.Sprintf("Number of recent features listed under Recent Changes (default: context.history or %d)", models.DefaultChangeHistory))
 }
 
 func runFeatureCreate(cmd *cobra.Command, args []string) error {
 	description := strings.Join(args, " ")
 
-	filesystem := services.NewFilesystemService()
-	git := services.NewGitService()
-	feature := services.NewFeatureService(filesystem, git)
+	feature, err := newFeatureService()
+	if err != nil {
+		return err
+	}
+
+	if err := setAutoCommit(cmd, feature); err != nil {
+		return err
+	}
+
+	ticket, err := cmd.Flags().GetString("ticket")
+	if err != nil {
+		return fmt.Errorf("failed to get 'ticket' flag: %w", err)
+	}
+
+	reserve, err := reserveNumbers(cmd, feature.Config())
+	if err != nil {
+		return err
+	}
+
+	worktree, err := cmd.Flags().GetBool("worktree")
+	if err != nil {
+		return fmt.Errorf("failed to get 'worktree' flag: %w", err)
+	}
+	from, err := cmd.Flags().GetString("from")
+	if err != nil {
+		return fmt.Errorf("failed to get 'from' flag: %w", err)
+	}
+	stash, err := cmd.Flags().GetBool("stash")
+	if err != nil {
+		return fmt.Errorf("failed to get 'stash' flag: %w", err)
+	}
+
+	issue, found, err := featureIssue(cmd, feature.Config())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(description) == "" && !found {
+		return fmt.Errorf("a feature description or --from-issue is required")
+	}
 
-	result, err := feature.CreateFeature(description)
+	result, err := feature.CreateFeature(services.FeatureCreateOptions{
+		Description: description,
+		Ticket:      ticket,
+		Reserve:     reserve,
+		Worktree:    worktree,
+		From:        from,
+		Stash:       stash,
+		Issue:       issue,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create feature: %w", err)
 	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(cmd.ErrOrStderr(), "⚠️  %s\n", warning)
+	}
 
 	jsonOutput, err := cmd.Flags().GetBool("json")
 	if err != nil {
@@ -144,15 +385,61 @@ func runFeatureCreate(cmd *cobra.Command, args []string) error {
 		fmt.Printf("BRANCH_NAME: %s\n", result.BranchName)
 		fmt.Printf("SPEC_FILE: %s\n", result.SpecFile)
 		fmt.Printf("FEATURE_NUM: %s\n", result.FeatureNum)
+		if result.Reserved != "" {
+			fmt.Printf("RESERVED: %s\n", result.Reserved)
+		}
+		if result.Worktree != "" {
+			fmt.Printf("WORKTREE: %s\n", result.Worktree)
+		}
+		if result.Stashed {
+			fmt.Println("STASHED: uncommitted changes (restore with 'git stash pop')")
+		}
+		if result.Issue != "" {
+			fmt.Printf("ISSUE: %s\n", result.Issue)
+		}
+		if result.Commit != "" {
+			fmt.Printf("COMMIT: %s\n", result.Commit)
+		}
 	}
 
 	return nil
 }
 
+// featureIssue fetches the issue given with --from-issue; found is false when
+// the flag is not set
+func featureIssue(cmd *cobra.Command, config *models.ProjectConfig) (issue *models.Issue, found bool, err error) {
+	reference, err := cmd.Flags().GetString("from-issue")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get 'from-issue' flag: %w", err)
+	}
+	if reference == "" {
+		return nil, false, nil
+	}
+
+	repo, number, err := models.ParseIssueReference(reference)
+	if err != nil {
+		return nil, false, err
+	}
+
+	github, err := newGitHubService(config)
+	if err != nil {
+		return nil, false, err
+	}
+	if issue, err = github.GetIssue(*repo, number); err != nil {
+		return nil, false, fmt.Errorf("failed to fetch issue: %w", err)
+	}
+	return issue, true, nil
+}
+
 func runFeaturePlan(cmd *cobra.Command, args []string) error {
-	filesystem := services.NewFilesystemService()
-	git := services.NewGitService()
-	feature := services.NewFeatureService(filesystem, git)
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := setAutoCommit(cmd, feature); err != nil {
+		return err
+	}
 
 	result, err := feature.SetupPlan()
 	if err != nil {
@@ -172,15 +459,19 @@ func runFeaturePlan(cmd *cobra.Command, args []string) error {
 		fmt.Printf("IMPL_PLAN: %s\n", result.ImplPlan)
 		fmt.Printf("SPECS_DIR: %s\n", result.SpecsDir)
 		fmt.Printf("BRANCH: %s\n", result.Branch)
+		if result.Commit != "" {
+			fmt.Printf("COMMIT: %s\n", result.Commit)
+		}
 	}
 
 	return nil
 }
 
 func runFeatureCheck(cmd *cobra.Command, args []string) error {
-	filesystem := services.NewFilesystemService()
-	git := services.NewGitService()
-	feature := services.NewFeatureService(filesystem, git)
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
 
 	result, err := feature.CheckPrerequisites()
 	if err != nil {
@@ -206,22 +497,76 @@ func runFeatureCheck(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runFeatureLint(cmd *cobra.Command, args []string) error {
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	result, err := feature.LintFeature()
+	if err != nil {
+		return fmt.Errorf("failed to lint feature: %w", err)
+	}
+
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+	if jsonOutput {
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(result); err != nil {
+			return fmt.Errorf("failed to write json output: %w", err)
+		}
+	} else {
+		for _, finding := range result.Findings {
+			location := finding.File
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+			}
+			fmt.Printf("%s: %s: %s [%s]\n", location, finding.Severity, finding.Message, finding.Rule)
+		}
+		if len(result.Findings) == 0 {
+			fmt.Printf("✅ No lint findings in %s\n", result.FeatureDir)
+		}
+	}
+
+	if result.Errors > 0 {
+		return fmt.Errorf("%d lint error(s) in %s", result.Errors, result.FeatureDir)
+	}
+	return nil
+}
+
 func runFeatureContext(cmd *cobra.Command, args []string) error {
 	agentType := ""
 	if len(args) > 0 {
 		agentType = args[0]
 	}
 
-	filesystem := services.NewFilesystemService()
-	git := services.NewGitService()
-	feature := services.NewFeatureService(filesystem, git)
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	historySize := feature.Config().GetContextHistory()
+	if cmd.Flags().Changed("history") {
+		historySize, err = cmd.Flags().GetInt("history")
+		if err != nil {
+			return fmt.Errorf("failed to get history flag: %w", err)
+		}
+		if historySize < 1 {
+			return fmt.Errorf("--history must be at least 1, got %d", historySize)
+		}
+	}
 
 	// Validate agent type using service
 	if err := feature.ValidateAgentType(agentType); err != nil {
 		return err
 	}
 
-	result, err := feature.UpdateContext(agentType)
+	if err := setAutoCommit(cmd, feature); err != nil {
+		return err
+	}
+
+	result, err := feature.UpdateContext(agentType, historySize)
 	if err != nil {
 		return fmt.Errorf("failed to update context: %w", err)
 	}
@@ -229,7 +574,11 @@ func runFeatureContext(cmd *cobra.Command, args []string) error {
 	fmt.Printf("=== Updating agent context files for feature %s ===\n", result.Branch)
 
 	for _, update := range result.Updates {
-		fmt.Printf("✅ %s context file updated successfully\n", update.Agent)
+		action := "updated"
+		if update.Created {
+			action = "created"
+		}
+		fmt.Printf("✅ %s context file %s successfully (%s)\n", update.Agent, action, update.File)
 	}
 
 	if len(result.Summary) > 0 {
@@ -239,13 +588,18 @@ func runFeatureContext(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if result.Commit != "" {
+		fmt.Printf("\n📝 Committed context files (%s)\n", result.Commit)
+	}
+
 	return nil
 }
 
 func runFeaturePaths(cmd *cobra.Command, args []string) error {
-	filesystem := services.NewFilesystemService()
-	git := services.NewGitService()
-	feature := services.NewFeatureService(filesystem, git)
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
 
 	result, err := feature.GetPaths()
 	if err != nil {
@@ -261,3 +615,322 @@ func runFeaturePaths(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runFeatureRenumber(cmd *cobra.Command, args []string) error {
+	feature, err := newFeatureService()
+	if err != nil {
+		return err
+	}
+
+	number, err := cmd.Flags().GetInt("to")
+	if err != nil {
+		return fmt.Errorf("failed to get 'to' flag: %w", err)
+	}
+	if cmd.Flags().Changed("to") && number < 1 {
+		return fmt.Errorf("--to must be at least 1, got %d", number)
+	}
+
+	reserve, err := reserveNumbers(cmd, feature.Config())
+	if err != nil {
+		return err
+	}
+
+	result, err := feature.RenumberFeature(services.FeatureRenumberOptions{
+		Number:  number,
+		Reserve: reserve,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to renumber feature: %w", err)
+	}
+
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+	if jsonOutput {
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(result); err != nil {
+			return fmt.Errorf("failed to write json output: %w", err)
+		}
+		return nil
+	}
+
+	if !result.Renumbered {
+		fmt.Printf("✅ Feature number %s is not used by any other feature; nothing to do\n", result.OldNumber)
+		return nil
+	}
+
+	if len(result.Collisions) > 0 {
+		fmt.Printf("⚠️  %s was also used by: %s\n", result.OldNumber, strings.Join(result.Collisions, ", "))
+	}
+	fmt.Printf("✅ Renumbered %s → %s\n", result.OldBranch, result.NewBranch)
+	if result.FeatureDir != "" {
+		fmt.Printf("FEATURE_DIR: %s\n", result.FeatureDir)
+	}
+	for _, file := range result.UpdatedFiles {
+		fmt.Printf("  updated %s\n", file)
+	}
+	if result.Reservation != "" {
+		fmt.Printf("RESERVED: %s\n", result.Reservation)
+	}
+
+	return nil
+}
+
+func runFeatureWorktreeList(cmd *cobra.Command, args []string) error {
+	feature, err := newFeatureService()
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := feature.ListWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+	if jsonOutput {
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(worktrees); err != nil {
+			return fmt.Errorf("failed to write json output: %w", err)
+		}
+		return nil
+	}
+
+	if len(worktrees) == 0 {
+		fmt.Println("No feature worktrees")
+		return nil
+	}
+
+	for _, worktree := range worktrees {
+		spec := "spec.md"
+		if !worktree.HasSpec {
+			spec = "no spec.md"
+		}
+		fmt.Printf("%s  %s  (%s)\n", worktree.Branch, worktree.Path, spec)
+	}
+
+	return nil
+}
+
+func runFeatureWorktreeRemove(cmd *cobra.Command, args []string) error {
+	feature, err := newFeatureService()
+	if err != nil {
+		return err
+	}
+
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return fmt.Errorf("failed to get 'force' flag: %w", err)
+	}
+	deleteBranch, err := cmd.Flags().GetBool("delete-branch")
+	if err != nil {
+		return fmt.Errorf("failed to get 'delete-branch' flag: %w", err)
+	}
+
+	worktree, err := feature.RemoveWorktree(args[0], services.FeatureWorktreeRemoveOptions{
+		Force:        force,
+		DeleteBranch: deleteBranch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove worktree: %w", err)
+	}
+
+	fmt.Printf("✅ Removed worktree %s\n", worktree.Path)
+	if deleteBranch {
+		fmt.Printf("✅ Deleted branch %s\n", worktree.Branch)
+	}
+
+	return nil
+}
+
+func runFeatureRename(cmd *cobra.Command, args []string) error {
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	result, err := feature.RenameFeature(strings.Join(args, " "))
+	if err != nil {
+		return fmt.Errorf("failed to rename feature: %w", err)
+	}
+
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+	if jsonOutput {
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(result); err != nil {
+			return fmt.Errorf("failed to write json output: %w", err)
+		}
+		return nil
+	}
+
+	if result.OldBranch != result.NewBranch {
+		fmt.Printf("✅ Renamed branch %s → %s\n", result.OldBranch, result.NewBranch)
+	}
+	if result.DirMoved {
+		fmt.Printf("✅ Renamed %s → %s\n", result.OldDir, result.NewDir)
+	}
+	for _, file := range result.UpdatedFiles {
+		fmt.Printf("  updated %s\n", file)
+	}
+
+	return nil
+}
+
+func runFeatureArchive(cmd *cobra.Command, args []string) error {
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	reason, err := cmd.Flags().GetString("reason")
+	if err != nil {
+		return fmt.Errorf("failed to get 'reason' flag: %w", err)
+	}
+	deleteBranch, err := cmd.Flags().GetBool("delete-branch")
+	if err != nil {
+		return fmt.Errorf("failed to get 'delete-branch' flag: %w", err)
+	}
+
+	result, err := feature.ArchiveFeature(services.FeatureArchiveOptions{
+		Reason:       reason,
+		DeleteBranch: deleteBranch,
+	})
+	if err != nil {
+		if result != nil {
+			fmt.Printf("✅ Archived %s to %s\n", result.Archive.Feature, result.ArchiveDir)
+		}
+		return fmt.Errorf("failed to archive feature: %w", err)
+	}
+
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+	if jsonOutput {
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(result); err != nil {
+			return fmt.Errorf("failed to write json output: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Printf("✅ Archived %s to %s\n", result.Archive.Feature, result.ArchiveDir)
+	if result.BranchDeleted {
+		fmt.Printf("✅ Deleted branch %s\n", result.Archive.Branch)
+	}
+
+	return nil
+}
+
+func runFeatureDelete(cmd *cobra.Command, args []string) error {
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	keepBranch, err := cmd.Flags().GetBool("keep-branch")
+	if err != nil {
+		return fmt.Errorf("failed to get 'keep-branch' flag: %w", err)
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return fmt.Errorf("failed to get 'yes' flag: %w", err)
+	}
+
+	// Show exactly what is going to be removed before asking
+	paths, err := feature.GetPaths()
+	if err != nil {
+		return fmt.Errorf("failed to find feature: %w", err)
+	}
+	if !yes {
+		target := paths.FeatureDir
+		if !keepBranch {
+			target = fmt.Sprintf("branch %s and %s", paths.Branch, paths.FeatureDir)
+		}
+		if !ui.Confirm(fmt.Sprintf("Delete %s? This cannot be undone.", target)) {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	result, err := feature.DeleteFeature(services.FeatureDeleteOptions{KeepBranch: keepBranch})
+	if err != nil {
+		return fmt.Errorf("failed to delete feature: %w", err)
+	}
+
+	if result.SwitchedTo != "" {
+		fmt.Printf("Switched to %s\n", result.SwitchedTo)
+	}
+	fmt.Printf("✅ Deleted %s\n", result.FeatureDir)
+	if result.BranchDeleted {
+		fmt.Printf("✅ Deleted branch %s\n", result.Branch)
+	}
+
+	return nil
+}
+
+// reserveNumbers resolves whether feature numbers are reserved on the remote:
+// the --reserve flag when given, otherwise numbering.reserve
+func reserveNumbers(cmd *cobra.Command, config *models.ProjectConfig) (bool, error) {
+	if !cmd.Flags().Changed("reserve") {
+		return config.ReserveNumbers(), nil
+	}
+	reserve, err := cmd.Flags().GetBool("reserve")
+	if err != nil {
+		return false, fmt.Errorf("failed to get 'reserve' flag: %w", err)
+	}
+	return reserve, nil
+}
+
+// setAutoCommit applies --commit, which overrides commit.auto
+func setAutoCommit(cmd *cobra.Command, feature *services.FeatureService) error {
+	if !cmd.Flags().Changed("commit") {
+		return nil
+	}
+	commit, err := cmd.Flags().GetBool("commit")
+	if err != nil {
+		return fmt.Errorf("failed to get 'commit' flag: %w", err)
+	}
+	feature.SetAutoCommit(commit)
+	return nil
+}
+
+// newSelectedFeatureService creates a feature service for the feature chosen
+// with --feature or SPECIFY_FEATURE, falling back to the current branch
+func newSelectedFeatureService(cmd *cobra.Command) (*services.FeatureService, error) {
+	feature, err := newFeatureService()
+	if err != nil {
+		return nil, err
+	}
+
+	selector := os.Getenv(services.FeatureEnvVar)
+	if cmd.Flags().Changed("feature") {
+		if selector, err = cmd.Flags().GetString("feature"); err != nil {
+			return nil, fmt.Errorf("failed to get 'feature' flag: %w", err)
+		}
+	}
+	feature.SelectFeature(selector)
+
+	return feature, nil
+}
+
+// newFeatureService creates a feature service using the effective configuration
+// for the working directory
+func newFeatureService() (*services.FeatureService, error) {
+	filesystem := services.NewFilesystemService()
+
+	config, err := loadConfig(filesystem)
+	if err != nil {
+		return nil, err
+	}
+
+	git, err := newGitService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.NewFeatureService(filesystem, git, config), nil
+}
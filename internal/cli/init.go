@@ -42,11 +42,12 @@ var (
 	noGit            bool
 	here             bool
 	force            bool
+	allowUnsigned    bool
 )
 
 func init() {
 	initCmd.Flags().
-		StringVar(&aiAssistant, "ai", "", "AI assistant to use: claude, gemini, copilot, or codex")
+		StringVar(&aiAssistant, "ai", "", "AI assistant to use: claude, gemini, copilot, or codex (default: config ai)")
 	initCmd.Flags().
 		BoolVar(&ignoreAgentTools, "ignore-agent-tools", false, "Skip checks for AI agent tools like Claude Code")
 	initCmd.Flags().BoolVar(&noGit, "no-git", false, "Skip git repository initialization")
@@ -54,6 +55,8 @@ func init() {
 		BoolVar(&here, "here", false, "Initialize project in the current directory instead of creating a new one")
 	initCmd.Flags().
 		BoolVar(&force, "force", false, "Force initialization even if directory is not empty")
+	initCmd.Flags().
+		BoolVar(&allowUnsigned, "allow-unsigned", false, "Accept a template bundle that is not signed by a trusted key")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -76,15 +79,34 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	// Initialize services
 	filesystem := services.NewFilesystemService()
-	github := services.NewGitHubService()
-	template := services.NewTemplateService(github, filesystem)
-	environment := services.NewEnvironmentService(filesystem)
+	config, err := loadConfig(filesystem)
+	if err != nil {
+		return err
+	}
+	github, err := newGitHubService(config)
+	if err != nil {
+		return err
+	}
+	template := newTemplateService(config, github, filesystem)
+	template.AllowUnsigned(allowUnsigned)
+	git, err := newGitService(config)
+	if err != nil {
+		return err
+	}
+	environment, err := newEnvironmentService(config, filesystem, git)
+	if err != nil {
+		return err
+	}
+	if template.HasCachedTemplates() {
+		// Templates are extracted from the local cache; no download is needed
+		environment.SkipConnectivity("templates are cached")
+	}
 	project := services.NewProjectService(environment, template, filesystem)
 
 	// Create project options
 	options := services.ProjectInitOptions{
 		Name:             projectName,
-		AIAssistant:      aiAssistant,
+		AIAssistant:      resolveAIAssistant(cmd, config),
 		IsHere:           here,
 		NoGit:            noGit,
 		IgnoreAgentTools: ignoreAgentTools,
@@ -216,6 +238,15 @@ func runInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// resolveAIAssistant returns --ai when given, otherwise the configured default
+// (SPECIFY_AI, project config, then user config)
+func resolveAIAssistant(cmd *cobra.Command, config *models.ProjectConfig) string {
+	if cmd.Flags().Changed("ai") {
+		return aiAssistant
+	}
+	return config.AI
+}
+
 func selectAIAssistant() (string, error) {
 	fmt.Println("Select your AI assistant:")
 	fmt.Println("1. Claude Code")
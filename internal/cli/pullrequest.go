@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/euforicio/spec-kit/internal/services"
+)
+
+var featurePRCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Compose a pull request from the feature's spec, plan and tasks",
+	Long: `Compose a pull request title and body for the current feature, or the one
+selected with --feature. The body summarizes the spec, lists its functional
+requirements, the technical decisions from plan.md and the task completion in
+tasks.md.
+
+By default the pull request is printed. --output writes the body to a file, for
+use with 'gh pr create --body-file', and --open creates the pull request with the
+GitHub API. Opening needs a token (GITHUB_TOKEN, GH_TOKEN or 'gh auth login')
+and the feature branch pushed; the repository is taken from the origin remote
+unless --repo is given.
+
+Examples:
+  specify feature pr
+  specify feature pr --output pr.md
+  specify feature pr --open --draft --base release/2.3`,
+	Args: cobra.NoArgs,
+	RunE: runFeaturePR,
+}
+
+func init() {
+	featureCmd.AddCommand(featurePRCmd)
+
+	featurePRCmd.Flags().String("feature", "", "Feature number, slug or directory to use instead of the current branch (env: "+services.FeatureEnvVar+")")
+	featurePRCmd.Flags().String("base", "", "Branch to merge into (default: the default branch)")
+	featurePRCmd.Flags().Bool("draft", false, "Open the pull request as a draft")
+	featurePRCmd.Flags().StringP("output", "o", "", "Write the pull request body to a file")
+	featurePRCmd.Flags().Bool("open", false, "Create the pull request on GitHub")
+	featurePRCmd.Flags().String("repo", "", "GitHub repository as owner/name (default: from the origin remote)")
+	featurePRCmd.Flags().Bool("json", false, "Output results in JSON format")
+}
+
+func runFeaturePR(cmd *cobra.Command, args []string) error {
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	base, err := cmd.Flags().GetString("base")
+	if err != nil {
+		return fmt.Errorf("failed to get 'base' flag: %w", err)
+	}
+	draft, err := cmd.Flags().GetBool("draft")
+	if err != nil {
+		return fmt.Errorf("failed to get 'draft' flag: %w", err)
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return fmt.Errorf("failed to get 'output' flag: %w", err)
+	}
+	open, err := cmd.Flags().GetBool("open")
+	if err != nil {
+		return fmt.Errorf("failed to get 'open' flag: %w", err)
+	}
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+
+	pr, err := feature.ComposePullRequest(services.PullRequestOptions{Base: base, Draft: draft})
+	if err != nil {
+		return fmt.Errorf("failed to compose pull request: %w", err)
+	}
+
+	if output != "" {
+		if err := services.NewFilesystemService().WriteFile(output, pr.Body); err != nil {
+			return fmt.Errorf("failed to write pull request body: %w", err)
+		}
+	}
+
+	if open {
+		repo, err := gitHubRepository(cmd, feature.Config())
+		if err != nil {
+			return err
+		}
+
+		github, err := newGitHubService(feature.Config())
+		if err != nil {
+			return err
+		}
+		if pr, err = github.CreatePullRequest(*repo, pr); err != nil {
+			return fmt.Errorf("failed to open pull request: %w", err)
+		}
+	}
+
+	switch {
+	case jsonOutput:
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(pr); err != nil {
+			return fmt.Errorf("failed to write json output: %w", err)
+		}
+	case open:
+		fmt.Printf("✅ Opened pull request #%d: %s\n", pr.Number, pr.URL)
+	case output != "":
+		fmt.Printf("TITLE: %s\n", pr.Title)
+		fmt.Printf("BODY_FILE: %s\n", output)
+	default:
+		fmt.Printf("%s\n\n%s", pr.Title, pr.Body)
+	}
+
+	return nil
+}
@@ -44,7 +44,9 @@ func init() {
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(featureCmd)
+	rootCmd.AddCommand(tasksCmd)
 	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(configCmd)
 }
 
 func showVersion() {
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/euforicio/spec-kit/internal/services"
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "Sync a feature's tasks.md with GitHub issues",
+}
+
+var tasksExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Create or update one GitHub issue per task",
+	Long: `Create or update one GitHub issue per task in tasks.md of the current feature,
+or the one selected with --feature.
+
+Each issue carries the specify-task label, a label for its phase (e.g.
+"phase: Setup") and links to the issues of the tasks it depends on, read from
+the Dependencies section. A marker with the feature and task ID in the issue
+body finds the issue again, so exporting twice only updates what changed. Done
+tasks close their issue.
+
+Exporting needs a token in GITHUB_TOKEN or GH_TOKEN, or a 'gh auth login'. The
+repository is taken from the origin remote unless --repo is given.
+
+Examples:
+  specify tasks export --github
+  specify tasks export --github --feature 004 --repo acme/widgets`,
+	Args: cobra.NoArgs,
+	RunE: runTasksExport,
+}
+
+var tasksImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Check off tasks whose GitHub issue is closed",
+	Long: `Check off the tasks in tasks.md whose issue, created with 'specify tasks
+export --github', has been closed. Reopened issues do not uncheck tasks.
+
+Examples:
+  specify tasks import
+  specify tasks import --feature 004 --repo acme/widgets`,
+	Args: cobra.NoArgs,
+	RunE: runTasksImport,
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksExportCmd)
+	tasksCmd.AddCommand(tasksImportCmd)
+
+	tasksExportCmd.Flags().Bool("github", false, "Export the tasks as GitHub issues")
+	for _, cmd := range []*cobra.Command{tasksExportCmd, tasksImportCmd} {
+		cmd.Flags().String("feature", "", "Feature number, slug or directory to use instead of the current branch (env: "+services.FeatureEnvVar+")")
+		cmd.Flags().String("repo", "", "GitHub repository as owner/name (default: from the origin remote)")
+		cmd.Flags().Bool("json", false, "Output results in JSON format")
+	}
+}
+
+func runTasksExport(cmd *cobra.Command, args []string) error {
+	toGitHub, err := cmd.Flags().GetBool("github")
+	if err != nil {
+		return fmt.Errorf("failed to get 'github' flag: %w", err)
+	}
+	if !toGitHub {
+		return fmt.Errorf("choose where to export the tasks, e.g. --github")
+	}
+
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	repo, err := gitHubRepository(cmd, feature.Config())
+	if err != nil {
+		return err
+	}
+	github, err := newGitHubService(feature.Config())
+	if err != nil {
+		return err
+	}
+
+	result, err := feature.ExportTasks(github, *repo)
+	if err != nil {
+		return fmt.Errorf("failed to export tasks: %w", err)
+	}
+
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+	if jsonOutput {
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(result); err != nil {
+			return fmt.Errorf("failed to write json output: %w", err)
+		}
+		return nil
+	}
+
+	for _, issue := range result.Issues {
+		fmt.Printf("%s #%d %s (%s, %s)\n", issue.Task, issue.Number, issue.URL, issue.State, issue.Action)
+	}
+	return nil
+}
+
+func runTasksImport(cmd *cobra.Command, args []string) error {
+	feature, err := newSelectedFeatureService(cmd)
+	if err != nil {
+		return err
+	}
+
+	repo, err := gitHubRepository(cmd, feature.Config())
+	if err != nil {
+		return err
+	}
+	github, err := newGitHubService(feature.Config())
+	if err != nil {
+		return err
+	}
+
+	result, err := feature.ImportTasks(github, *repo)
+	if err != nil {
+		return fmt.Errorf("failed to import tasks: %w", err)
+	}
+
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+	if jsonOutput {
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(result); err != nil {
+			return fmt.Errorf("failed to write json output: %w", err)
+		}
+		return nil
+	}
+
+	if len(result.Checked) == 0 {
+		fmt.Println("No closed task issues to import")
+		return nil
+	}
+	for _, task := range result.Checked {
+		fmt.Printf("✅ %s checked off\n", task)
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/euforicio/spec-kit/internal/models"
+	"github.com/euforicio/spec-kit/internal/services"
+)
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached template versions",
+	Long: `List the active cached template version and versions archived when a sync
+replaced them, with their file count, size and last sync time.`,
+	Args: cobra.NoArgs,
+	RunE: runTemplatesList,
+}
+
+var templatesVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cached templates against the cache manifest",
+	Long: `Check the SHA256 of every cached template against the cache manifest and
+report each file that is missing or modified. Files the manifest does not list
+are reported too but do not fail verification.
+
+Exits with an error when a file does not match; 'specify templates repair'
+restores the cache.`,
+	Args: cobra.NoArgs,
+	RunE: runTemplatesVerify,
+}
+
+var templatesRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Restore damaged cached templates and remove stale files",
+	Long: `Restore cached templates that are missing or do not match the cache manifest,
+and remove files the manifest does not list.
+
+Damaged files are copied from an archived version with the same content where
+possible, otherwise only those files are read from the release bundle of the
+cached version. The repaired cache is built next to the old one and swapped in,
+so a failed repair leaves the cache as it was; 'specify templates sync --force'
+then downloads the whole bundle again.`,
+	Args: cobra.NoArgs,
+	RunE: runTemplatesRepair,
+}
+
+var templatesPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove archived template versions and interrupted downloads",
+	Long: `Remove template versions archived by earlier syncs, keeping the --keep most
+recently synced, and interrupted template downloads. The active version is
+never pruned.
+
+Examples:
+  specify templates prune
+  specify templates prune --keep 1`,
+	Args: cobra.NoArgs,
+	RunE: runTemplatesPrune,
+}
+
+var templatesClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the whole template cache",
+	Long: `Remove the template cache, archived versions and interrupted downloads. The
+next 'specify init' or 'specify templates sync' downloads the templates again.`,
+	Args: cobra.NoArgs,
+	RunE: runTemplatesClear,
+}
+
+var templatesPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the template cache directory",
+	Args:  cobra.NoArgs,
+	RunE:  runTemplatesPath,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesVerifyCmd)
+	templatesCmd.AddCommand(templatesRepairCmd)
+	templatesCmd.AddCommand(templatesPruneCmd)
+	templatesCmd.AddCommand(templatesClearCmd)
+	templatesCmd.AddCommand(templatesPathCmd)
+
+	templatesPruneCmd.Flags().Int("keep", 0, "Number of archived versions to keep")
+	for _, cmd := range []*cobra.Command{templatesListCmd, templatesVerifyCmd, templatesRepairCmd, templatesPruneCmd, templatesClearCmd, templatesPathCmd} {
+		cmd.Flags().Bool("json", false, "Output results in JSON format")
+	}
+}
+
+// newCacheTemplateService creates the template service used to manage the cache
+func newCacheTemplateService() (*services.TemplateService, error) {
+	filesystem := services.NewFilesystemService()
+	config, err := loadConfig(filesystem)
+	if err != nil {
+		return nil, err
+	}
+	github, err := newGitHubService(config)
+	if err != nil {
+		return nil, err
+	}
+	return newTemplateService(config, github, filesystem), nil
+}
+
+// writeTemplatesJSON writes result as JSON and reports whether --json was given
+func writeTemplatesJSON(cmd *cobra.Command, result any) (bool, error) {
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return false, fmt.Errorf("failed to get 'json' flag: %w", err)
+	}
+	if !jsonOutput {
+		return false, nil
+	}
+	if err := json.NewEncoder(cmd.OutOrStdout()).Encode(result); err != nil {
+		return true, fmt.Errorf("failed to write json output: %w", err)
+	}
+	return true, nil
+}
+
+func runTemplatesList(cmd *cobra.Command, args []string) error {
+	template, err := newCacheTemplateService()
+	if err != nil {
+		return err
+	}
+
+	versions, err := template.ListCache()
+	if err != nil {
+		return fmt.Errorf("failed to list template cache: %w", err)
+	}
+	if done, err := writeTemplatesJSON(cmd, versions); done || err != nil {
+		return err
+	}
+
+	if len(versions) == 0 {
+		fmt.Println("No cached templates; run 'specify templates sync'")
+		return nil
+	}
+	for _, version := range versions {
+		marker := "  "
+		if version.Active {
+			marker = "* "
+		}
+		fmt.Printf("%s%-12s %4d files %10s  synced %s  %s\n", marker, version.Version, version.Files,
+			formatBytes(version.Size), version.LastSync.Local().Format("2006-01-02 15:04"), version.Path)
+	}
+	return nil
+}
+
+func runTemplatesVerify(cmd *cobra.Command, args []string) error {
+	template, err := newCacheTemplateService()
+	if err != nil {
+		return err
+	}
+
+	verification, err := template.VerifyCache()
+	if err != nil {
+		return err
+	}
+	if done, err := writeTemplatesJSON(cmd, verification); done || err != nil {
+		if err == nil && !verification.OK() {
+			return fmt.Errorf("%w: %d of %d files do not match the manifest",
+				models.ErrTemplateCorrupted, len(verification.Problems), verification.Checked)
+		}
+		return err
+	}
+
+	for _, problem := range verification.Problems {
+		fmt.Printf("❌ %s: %s\n", problem.Path, problem.Problem)
+	}
+	for _, path := range verification.Extra {
+		fmt.Printf("⚠️  %s: not in the manifest\n", path)
+	}
+	if !verification.OK() {
+		return fmt.Errorf("%w: %d of %d files do not match the manifest; run 'specify templates repair'",
+			models.ErrTemplateCorrupted, len(verification.Problems), verification.Checked)
+	}
+
+	fmt.Printf("✅ %d files of version %s match the manifest (%s)\n", verification.Checked, verification.Version, verification.Root)
+	return nil
+}
+
+func runTemplatesRepair(cmd *cobra.Command, args []string) error {
+	template, err := newCacheTemplateService()
+	if err != nil {
+		return err
+	}
+
+	repair, err := template.RepairCache()
+	if err != nil {
+		return fmt.Errorf("failed to repair template cache: %w", err)
+	}
+	if done, err := writeTemplatesJSON(cmd, repair); done || err != nil {
+		return err
+	}
+
+	if !repair.Changed() {
+		fmt.Printf("✅ Template cache %s is intact\n", repair.Version)
+		return nil
+	}
+	for _, path := range repair.Restored {
+		fmt.Printf("♻️  %s: restored from an archived version\n", path)
+	}
+	for _, path := range repair.Fetched {
+		fmt.Printf("⬇️  %s: fetched from the release bundle\n", path)
+	}
+	for _, path := range repair.Removed {
+		fmt.Printf("🗑️  %s: not in the manifest\n", path)
+	}
+	fmt.Printf("✅ Repaired template cache %s (%s)\n", repair.Version, repair.Root)
+	return nil
+}
+
+func runTemplatesPrune(cmd *cobra.Command, args []string) error {
+	keep, err := cmd.Flags().GetInt("keep")
+	if err != nil {
+		return fmt.Errorf("failed to get 'keep' flag: %w", err)
+	}
+	if keep < 0 {
+		return fmt.Errorf("--keep must not be negative, got %d", keep)
+	}
+
+	template, err := newCacheTemplateService()
+	if err != nil {
+		return err
+	}
+
+	removal, err := template.PruneCache(keep)
+	if err != nil {
+		return fmt.Errorf("failed to prune template cache: %w", err)
+	}
+	return printCacheRemoval(cmd, removal, "Nothing to prune")
+}
+
+func runTemplatesClear(cmd *cobra.Command, args []string) error {
+	template, err := newCacheTemplateService()
+	if err != nil {
+		return err
+	}
+
+	removal, err := template.ClearCache()
+	if err != nil {
+		return fmt.Errorf("failed to clear template cache: %w", err)
+	}
+	return printCacheRemoval(cmd, removal, "Template cache is already empty")
+}
+
+// printCacheRemoval reports what prune or clear removed
+func printCacheRemoval(cmd *cobra.Command, removal *models.CacheRemoval, nothing string) error {
+	if done, err := writeTemplatesJSON(cmd, removal); done || err != nil {
+		return err
+	}
+
+	if len(removal.Removed) == 0 {
+		fmt.Println(nothing)
+		return nil
+	}
+	for _, path := range removal.Removed {
+		fmt.Printf("🗑️  %s\n", path)
+	}
+	fmt.Printf("✅ Freed %s\n", formatBytes(removal.Freed))
+	return nil
+}
+
+func runTemplatesPath(cmd *cobra.Command, args []string) error {
+	template, err := newCacheTemplateService()
+	if err != nil {
+		return err
+	}
+
+	cacheRoot, err := template.ResolveRoot()
+	if err != nil {
+		return err
+	}
+	versionsRoot, err := template.VersionsRoot()
+	if err != nil {
+		return err
+	}
+
+	paths := map[string]string{"path": cacheRoot, "versions": versionsRoot}
+	if done, err := writeTemplatesJSON(cmd, paths); done || err != nil {
+		return err
+	}
+
+	fmt.Println(cacheRoot)
+	return nil
+}
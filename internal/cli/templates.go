@@ -2,7 +2,6 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -51,8 +50,9 @@ The cache structure will be:
 }
 
 var (
-	forceSync   bool
-	verboseSync bool
+	forceSync         bool
+	verboseSync       bool
+	allowUnsignedSync bool
 )
 
 func init() {
@@ -62,6 +62,7 @@ func init() {
 	// Add flags to sync command
 	syncCmd.Flags().BoolVar(&forceSync, "force", false, "Force sync even if cache is up to date")
 	syncCmd.Flags().BoolVar(&verboseSync, "verbose", false, "Show detailed output during sync")
+	syncCmd.Flags().BoolVar(&allowUnsignedSync, "allow-unsigned", false, "Accept a template bundle that is not signed by a trusted key")
 }
 
 func runTemplatesSync(cmd *cobra.Command, args []string) error {
@@ -69,8 +70,16 @@ func runTemplatesSync(cmd *cobra.Command, args []string) error {
 
 	// Initialize services
 	filesystem := services.NewFilesystemService()
-	github := services.NewGitHubService()
-	template := services.NewTemplateService(github, filesystem)
+	config, err := loadConfig(filesystem)
+	if err != nil {
+		return err
+	}
+	github, err := newGitHubService(config)
+	if err != nil {
+		return err
+	}
+	template := newTemplateService(config, github, filesystem)
+	template.AllowUnsigned(allowUnsignedSync)
 
 	// Create temporary directory for downloads
 	tempDir, err := filesystem.CreateTempDirectory("specify-sync-")
@@ -84,7 +93,7 @@ func runTemplatesSync(cmd *cobra.Command, args []string) error {
 	}
 
 	// Download cache template ZIP from GitHub
-	if err := downloadCacheTemplate(github, filesystem, tempDir, verboseSync); err != nil {
+	if err := downloadCacheTemplate(github, template, tempDir, verboseSync); err != nil {
 		return fmt.Errorf("failed to download cache template: %w", err)
 	}
 
@@ -115,7 +124,7 @@ func runTemplatesSync(cmd *cobra.Command, args []string) error {
 
 	// Extract and sync files from downloaded ZIP (includes pre-built manifest)
 	sourceDir := filepath.Join(tempDir, "extracted")
-	if err := syncFromDownloadedFiles(filesystem, template, tempDir, sourceDir, cacheRoot, verboseSync); err != nil {
+	if err := syncFromDownloadedFiles(filesystem, template, tempDir, sourceDir, verboseSync); err != nil {
 		return fmt.Errorf("failed to sync from downloaded files: %w", err)
 	}
 
@@ -131,8 +140,9 @@ func runTemplatesSync(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// downloadCacheTemplate downloads the cache template ZIP from GitHub
-func downloadCacheTemplate(github *services.GitHubService, filesystem *services.FilesystemService, tempDir string, verbose bool) error {
+// downloadCacheTemplate downloads the cache template ZIP from GitHub and
+// verifies its signature
+func downloadCacheTemplate(github *services.GitHubService, template *services.TemplateService, tempDir string, verbose bool) error {
 	if verbose {
 		fmt.Println("🌐 Downloading cache template from GitHub...")
 	}
@@ -160,21 +170,37 @@ func downloadCacheTemplate(github *services.GitHubService, filesystem *services.
 		fmt.Printf("📦 Found cache template: %s (%s)\n", cacheAsset.Name, formatBytes(cacheAsset.Size))
 	}
 
-	// Download the asset
+	// Download the asset, resuming an interrupted download and verifying its checksum
 	zipPath := filepath.Join(tempDir, cacheAsset.Name)
-	if err := downloadAssetToFile(github, cacheAsset, zipPath); err != nil {
+	verified, err := github.DownloadReleaseAsset(release, cacheAsset, zipPath, nil)
+	if err != nil {
 		return fmt.Errorf("failed to download cache template: %w", err)
 	}
 
 	if verbose {
 		fmt.Printf("✅ Downloaded cache template to %s\n", zipPath)
+		if verified {
+			fmt.Println("🔒 SHA256 checksum verified")
+		} else {
+			fmt.Printf("⚠️  Release %s publishes no checksums; download not verified\n", release.TagName)
+		}
+	}
+
+	signature, signed, err := template.VerifyBundle(release, cacheAsset, zipPath)
+	if err != nil {
+		return err
+	}
+	if !signed {
+		fmt.Printf("⚠️  Cache template of release %s accepted without a verified signature (--allow-unsigned)\n", release.TagName)
+	} else if verbose {
+		fmt.Printf("🔏 Signature verified with key %s: %s\n", signature.KeyID, signature.TrustedComment)
 	}
 
 	return nil
 }
 
 // syncFromDownloadedFiles extracts and syncs files from the downloaded ZIP
-func syncFromDownloadedFiles(filesystem *services.FilesystemService, template *services.TemplateService, tempDir, sourceDir, cacheRoot string, verbose bool) error {
+func syncFromDownloadedFiles(filesystem *services.FilesystemService, template *services.TemplateService, tempDir, sourceDir string, verbose bool) error {
 	// Find the ZIP file
 	zipPath := filepath.Join(tempDir, "spec-kit-cache-template.zip")
 
@@ -187,8 +213,9 @@ func syncFromDownloadedFiles(filesystem *services.FilesystemService, template *s
 		fmt.Printf("📁 Extracted cache template to %s\n", sourceDir)
 	}
 
-	// Copy the entire extracted structure to cache (including pre-built manifest)
-	if err := filesystem.MergeDirectories(sourceDir, cacheRoot); err != nil {
+	// Copy the entire extracted structure to cache (including pre-built manifest),
+	// archiving a different cached version
+	if err := template.InstallCache(sourceDir); err != nil {
 		return fmt.Errorf("failed to copy extracted files to cache: %w", err)
 	}
 
@@ -212,18 +239,3 @@ func formatBytes(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
-
-// downloadAssetToFile downloads a GitHub asset to a file
-func downloadAssetToFile(github *services.GitHubService, asset *services.GitHubAsset, filePath string) error {
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
-
-	if err := github.DownloadAsset(asset, file); err != nil {
-		return fmt.Errorf("failed to download asset: %w", err)
-	}
-
-	return nil
-}
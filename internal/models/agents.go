@@ -5,10 +5,10 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
-	"regexp"
 	"slices"
 	"strings"
 
+	"github.com/euforicio/spec-kit/internal/regions"
 	"github.com/euforicio/spec-kit/internal/template"
 )
 
@@ -21,6 +21,24 @@ var AIAssistantDisplayNames = map[string]string{
 	"codex":   "OpenAI Codex",
 }
 
+// AgentsMDFile is the agent-agnostic context file shared by every agent.
+const AgentsMDFile = "AGENTS.md"
+
+// AgentContextFiles maps agent identifiers to the context file, relative to the
+// repository root, that holds the agent's project guidelines.
+var AgentContextFiles = map[string]string{
+	"claude":  "CLAUDE.md",
+	"gemini":  "GEMINI.md",
+	"copilot": filepath.Join(".github", "copilot-instructions.md"),
+	"codex":   AgentsMDFile,
+}
+
+// GetAgentContextFile returns the context file path for an agent relative to the
+// repository root, or an empty string for unknown agents.
+func GetAgentContextFile(agent string) string {
+	return AgentContextFiles[agent]
+}
+
 // ListAgents returns the supported AI assistant identifiers (sorted).
 func ListAgents() []string {
 	keys := slices.Collect(maps.Keys(AIAssistantDisplayNames))
@@ -34,8 +52,27 @@ func IsValidAgent(agent string) bool {
 	return ok
 }
 
-// Precompiled regex for <specify>...</specify> sections to avoid recompilation
-var specifySectionRe = regexp.MustCompile(`(?s)<specify>.*?</specify>`)
+// Managed region names used in agent context files
+const (
+	RegionSpecify   = "specify"
+	RegionTech      = "tech"
+	RegionStructure = "structure"
+	RegionCommands  = "commands"
+	RegionStyle     = "style"
+	RegionChanges   = "changes"
+)
+
+// Legacy <specify>...</specify> markers written by earlier versions
+const (
+	legacySpecifyOpen  = "<specify>"
+	legacySpecifyClose = "</specify>"
+)
+
+// agentsTemplatePath is the unified AGENTS.md template location
+const agentsTemplatePath = "templates/content/agents-template.md"
+
+// defaultSpecifySection is used when the AGENTS.md template is not available
+const defaultSpecifySection = "## Specify Commands\n\nThe following slash commands are available in this spec-driven development environment.\nFor detailed usage and examples of any command, see the corresponding documentation file in `.%s/commands/<command>.md`.\n\n### Built-in Commands\n\n**`/specify`** - Creates a new feature specification and branch  \nStart the spec-driven development lifecycle by creating a specification from your feature description.\n\n**`/plan`** - Creates an implementation plan from a feature specification  \nSecond phase: convert specification into implementation plan with research, design docs, and contracts.\n\n**`/tasks`** - Breaks down the implementation plan into executable tasks  \nThird phase: generate numbered, ordered tasks for implementation following TDD methodology.\n\n### Command Flow\n1. `/specify <description>` → Creates spec.md and feature branch\n2. `/plan` → Creates plan.md, research.md, contracts/, data-model.md, quickstart.md  \n3. `/tasks` → Creates tasks.md with numbered implementation tasks\n\n### Documentation Structure\n- Each command has detailed documentation at `.%s/commands/<command>.md`\n- Additional commands can be added by creating corresponding documentation files\n- Command documentation includes usage examples, parameters, and expected outputs\n"
 
 // Global template processor instance for operations
 var templateProcessor = template.NewProcessor()
@@ -52,77 +89,115 @@ func processTemplate(content string, data template.Data) (string, error) {
 func CreateOrUpdateAgentsMD(
 	aiAssistant, projectRoot string,
 ) (filePath string, created bool, err error) {
-	if projectRoot == "" {
-		return "", false, fmt.Errorf("invalid project root directory: path cannot be empty")
+	if err := validateProjectRoot(projectRoot); err != nil {
+		return "", false, err
 	}
 
-	// Check if project root exists
-	if _, statErr := os.Stat(projectRoot); os.IsNotExist(statErr) {
-		return "", false, fmt.Errorf("invalid project root directory: %s", projectRoot)
+	agentsPath := filepath.Join(projectRoot, AgentsMDFile)
+	created, err = WriteManagedFile(agentsPath, getAgentsMDTemplate(aiAssistant), []regions.Region{
+		{Name: RegionSpecify, Body: getSpecifySectionContent(aiAssistant)},
+	})
+	if err != nil {
+		return "", false, err
 	}
 
-	agentsPath := filepath.Join(projectRoot, "AGENTS.md")
+	return agentsPath, created, nil
+}
 
-	// Check if file exists
-	if _, statErr := os.Stat(agentsPath); os.IsNotExist(statErr) {
-		// File doesn't exist, create it
-		content := getAgentsMDTemplate(aiAssistant)
-		err = os.WriteFile(agentsPath, []byte(content), 0o644)
+// WriteManagedFile creates path with initial content when it does not exist,
+// otherwise rewrites the given managed regions in place. Manual content outside
+// the regions is preserved, and a legacy <specify> block is migrated first.
+func WriteManagedFile(path, initial string, updates []regions.Region) (created bool, err error) {
+	name := filepath.Base(path)
+
+	content := initial
+	existing, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		created = true
+	case err != nil:
+		return false, fmt.Errorf("failed to read existing %s: %w", name, err)
+	default:
+		content, err = migrateLegacySection(string(existing))
 		if err != nil {
-			return "", false, fmt.Errorf("failed to write AGENTS.md: %w", err)
+			return false, fmt.Errorf("malformed delimited section in %s: %w", name, err)
 		}
-		return agentsPath, true, nil
 	}
 
-	// File exists, read and update it
-	content, err := os.ReadFile(agentsPath)
+	doc, err := regions.Parse(content)
 	if err != nil {
-		return "", false, fmt.Errorf("failed to read existing AGENTS.md: %w", err)
+		return false, fmt.Errorf("malformed managed section in %s: %w", name, err)
 	}
 
-	// Get the specify section content for this agent
-	specifySection := getSpecifySectionContent(aiAssistant)
+	for _, update := range updates {
+		if err := doc.Set(update.Name, update.Body); err != nil {
+			return false, fmt.Errorf("failed to update %s section in %s: %w", update.Name, name, err)
+		}
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return false, fmt.Errorf("failed to create directory for %s: %w", name, err)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(doc.String()), 0o644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", name, err)
+	}
 
-	// Check for malformed sections
-	openCount := strings.Count(string(content), "<specify>")
-	closeCount := strings.Count(string(content), "</specify>")
+	return created, nil
+}
+
+// migrateLegacySection converts a <specify>...</specify> block into the
+// equivalent managed region
+func migrateLegacySection(content string) (string, error) {
+	openCount := strings.Count(content, legacySpecifyOpen)
+	closeCount := strings.Count(content, legacySpecifyClose)
 
 	if openCount > 1 || closeCount > 1 {
-		return "", false, fmt.Errorf(
-			"malformed delimited section: multiple delimited sections found",
-		)
+		return "", fmt.Errorf("multiple delimited sections found")
 	}
 
 	if openCount != closeCount {
-		return "", false, fmt.Errorf(
-			"malformed delimited section: mismatched opening and closing tags",
-		)
+		return "", fmt.Errorf("mismatched opening and closing tags")
 	}
 
-	var updatedContent string
-	if openCount == 1 {
-		// Replace existing section
-		updatedContent = specifySectionRe.ReplaceAllString(string(content), specifySection)
-	} else {
-		// Add new section at the end
-		updatedContent = string(content) + "\n" + specifySection + "\n"
+	if openCount == 0 {
+		return content, nil
 	}
 
-	// Write updated content
-	err = os.WriteFile(agentsPath, []byte(updatedContent), 0o644)
-	if err != nil {
-		return "", false, fmt.Errorf("failed to write updated AGENTS.md: %w", err)
+	start := strings.Index(content, legacySpecifyOpen)
+	end := strings.Index(content, legacySpecifyClose)
+	if end < start {
+		return "", fmt.Errorf("closing tag appears before opening tag")
+	}
+
+	before := content[:start]
+	if before != "" && !strings.HasSuffix(before, "\n") {
+		before += "\n"
+	}
+	body := strings.Trim(content[start+len(legacySpecifyOpen):end], "\n")
+	after := strings.TrimPrefix(content[end+len(legacySpecifyClose):], "\n")
+
+	return before + regions.Wrap(RegionSpecify, body) + after, nil
+}
+
+// validateProjectRoot checks that the project root is set and exists
+func validateProjectRoot(projectRoot string) error {
+	if projectRoot == "" {
+		return fmt.Errorf("invalid project root directory: path cannot be empty")
 	}
 
-	return agentsPath, false, nil
+	if _, statErr := os.Stat(projectRoot); os.IsNotExist(statErr) {
+		return fmt.Errorf("invalid project root directory: %s", projectRoot)
+	}
+
+	return nil
 }
 
 // getAgentsMDTemplate returns the complete template content for AGENTS.md from unified template file
 func getAgentsMDTemplate(aiAssistant string) string {
-	// Use new template location in content/ directory
-	templatePath := "templates/content/agents-template.md"
-
-	if content, err := os.ReadFile(templatePath); err == nil {
+	if content, err := os.ReadFile(agentsTemplatePath); err == nil {
 		// Process template with Go template engine
 		data := template.Data{
 			AIAssistant: aiAssistant,
@@ -133,100 +208,48 @@ func getAgentsMDTemplate(aiAssistant string) string {
 	}
 
 	// If no template file found, return default content with agent-specific paths
-	return fmt.Sprintf(
-		"# Agent Instructions\n\nThis file contains instructions for AI agents working with the spec-kit project.\n\n<specify>\n## Specify Commands\n\nThe following slash commands are available in this spec-driven development environment.\nFor detailed usage and examples of any command, see the corresponding documentation file in `.%s/commands/<command>.md`.\n\n### Built-in Commands\n\n**`/specify`** - Creates a new feature specification and branch  \nStart the spec-driven development lifecycle by creating a specification from your feature description.\n\n**`/plan`** - Creates an implementation plan from a feature specification  \nSecond phase: convert specification into implementation plan with research, design docs, and contracts.\n\n**`/tasks`** - Breaks down the implementation plan into executable tasks  \nThird phase: generate numbered, ordered tasks for implementation following TDD methodology.\n\n### Command Flow\n1. `/specify <description>` → Creates spec.md and feature branch\n2. `/plan` → Creates plan.md, research.md, contracts/, data-model.md, quickstart.md  \n3. `/tasks` → Creates tasks.md with numbered implementation tasks\n\n### Documentation Structure\n- Each command has detailed documentation at `.%s/commands/<command>.md`\n- Additional commands can be added by creating corresponding documentation files\n- Command documentation includes usage examples, parameters, and expected outputs\n</specify>",
-		aiAssistant,
-		aiAssistant,
-	)
+	return "# Agent Instructions\n\nThis file contains instructions for AI agents working with the spec-kit project.\n\n" +
+		regions.Wrap(RegionSpecify, fmt.Sprintf(defaultSpecifySection, aiAssistant, aiAssistant))
 }
 
-// getSpecifySectionContent returns the content for the <specify> delimited section from template file
+// getSpecifySectionContent returns the body of the specify managed region from template file
 func getSpecifySectionContent(aiAssistant string) string {
-	// Use new template location in content/ directory
-	templatePath := "templates/content/agents-template.md"
-
-	if content, err := os.ReadFile(templatePath); err == nil {
-		// Extract the <specify>...</specify> section from template content
-		if match := specifySectionRe.FindString(string(content)); match != "" {
-			// Process template with Go template engine
-			data := template.Data{
-				AIAssistant: aiAssistant,
-			}
-			if processedMatch, err := processTemplate(match, data); err == nil {
-				return processedMatch
+	if content, err := os.ReadFile(agentsTemplatePath); err == nil {
+		// Extract the specify region from template content
+		if doc, err := regions.Parse(string(content)); err == nil {
+			if body, ok := doc.Get(RegionSpecify); ok {
+				// Process template with Go template engine
+				data := template.Data{
+					AIAssistant: aiAssistant,
+				}
+				if processedBody, err := processTemplate(body, data); err == nil {
+					return processedBody
+				}
 			}
 		}
 	}
 
-	// If no template file found or no delimited section found, return default section with agent-specific paths
-	return fmt.Sprintf(
-		"<specify>\n## Specify Commands\n\nThe following slash commands are available in this spec-driven development environment.\nFor detailed usage and examples of any command, see the corresponding documentation file in `.%s/commands/<command>.md`.\n\n### Built-in Commands\n\n**`/specify`** - Creates a new feature specification and branch  \nStart the spec-driven development lifecycle by creating a specification from your feature description.\n\n**`/plan`** - Creates an implementation plan from a feature specification  \nSecond phase: convert specification into implementation plan with research, design docs, and contracts.\n\n**`/tasks`** - Breaks down the implementation plan into executable tasks  \nThird phase: generate numbered, ordered tasks for implementation following TDD methodology.\n\n### Command Flow\n1. `/specify <description>` → Creates spec.md and feature branch\n2. `/plan` → Creates plan.md, research.md, contracts/, data-model.md, quickstart.md  \n3. `/tasks` → Creates tasks.md with numbered implementation tasks\n\n### Documentation Structure\n- Each command has detailed documentation at `.%s/commands/<command>.md`\n- Additional commands can be added by creating corresponding documentation files\n- Command documentation includes usage examples, parameters, and expected outputs\n</specify>",
-		aiAssistant,
-		aiAssistant,
-	)
+	// If no template file found or no managed region found, return default section with agent-specific paths
+	return fmt.Sprintf(defaultSpecifySection, aiAssistant, aiAssistant)
 }
 
 // CreateOrUpdateClaudeMD creates or updates CLAUDE.md file with reference to AGENTS.md
 func CreateOrUpdateClaudeMD(projectRoot string) (filePath string, created bool, err error) {
-	if projectRoot == "" {
-		return "", false, fmt.Errorf("invalid project root directory: path cannot be empty")
-	}
-
-	// Check if project root exists
-	if _, statErr := os.Stat(projectRoot); os.IsNotExist(statErr) {
-		return "", false, fmt.Errorf("invalid project root directory: %s", projectRoot)
+	if err := validateProjectRoot(projectRoot); err != nil {
+		return "", false, err
 	}
 
 	claudePath := filepath.Join(projectRoot, "CLAUDE.md")
-	specifySection := "<specify>you MUST follow the RULES in AGENTS.md</specify>"
-
-	// Check if file exists
-	if _, statErr := os.Stat(claudePath); os.IsNotExist(statErr) {
-		// File doesn't exist, create it
-		content := "# Claude Instructions\n\nThis file contains specific instructions for Claude Code.\n\n" + specifySection + "\n"
-		err = os.WriteFile(claudePath, []byte(content), 0o644)
-		if err != nil {
-			return "", false, fmt.Errorf("failed to write CLAUDE.md: %w", err)
-		}
-		return claudePath, true, nil
-	}
-
-	// File exists, read and update it
-	content, err := os.ReadFile(claudePath)
-	if err != nil {
-		return "", false, fmt.Errorf("failed to read existing CLAUDE.md: %w", err)
-	}
-
-	// Check for malformed sections
-	openCount := strings.Count(string(content), "<specify>")
-	closeCount := strings.Count(string(content), "</specify>")
-
-	if openCount > 1 || closeCount > 1 {
-		return "", false, fmt.Errorf(
-			"malformed delimited section: multiple delimited sections found",
-		)
-	}
-
-	if openCount != closeCount {
-		return "", false, fmt.Errorf(
-			"malformed delimited section: mismatched opening and closing tags",
-		)
-	}
-
-	var updatedContent string
-	if openCount == 1 {
-		// Replace existing section
-		updatedContent = specifySectionRe.ReplaceAllString(string(content), specifySection)
-	} else {
-		// Add new section at the end
-		updatedContent = string(content) + "\n" + specifySection + "\n"
-	}
+	rules := "you MUST follow the RULES in AGENTS.md"
+	initial := "# Claude Instructions\n\nThis file contains specific instructions for Claude Code.\n\n" +
+		regions.Wrap(RegionSpecify, rules)
 
-	// Write updated content
-	err = os.WriteFile(claudePath, []byte(updatedContent), 0o644)
+	created, err = WriteManagedFile(claudePath, initial, []regions.Region{
+		{Name: RegionSpecify, Body: rules},
+	})
 	if err != nil {
-		return "", false, fmt.Errorf("failed to write updated CLAUDE.md: %w", err)
+		return "", false, err
 	}
 
-	return claudePath, false, nil
+	return claudePath, created, nil
 }
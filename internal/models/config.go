@@ -0,0 +1,841 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/naming"
+)
+
+// Project configuration location, relative to the project root
+const (
+	ConfigDirName  = ".specify"
+	ConfigFileName = "config.yaml"
+)
+
+// Default project configuration values
+const (
+	DefaultSpecsDir        = "specs"
+	DefaultNumberingWidth  = naming.DefaultWidth
+	DefaultBranchPattern   = naming.DefaultPattern
+	DefaultLintRuleSetting = LintSeverityWarning
+	DefaultCacheDir        = "~/.spec-kit/templates"
+	DefaultGitHubOwner     = "euforicio"
+	DefaultGitHubRepo      = "spec-kit"
+	DefaultGitHubAPIURL    = "https://api.github.com"
+	DefaultHTTPTimeout     = 30 * time.Second
+	DefaultDownloadTimeout = 10 * time.Minute
+	DefaultNumberingRemote = "origin"
+	DefaultGitBackend      = GitBackendAuto
+)
+
+// Git backends selectable with git.backend
+const (
+	GitBackendAuto   = "auto"   // exec when git is on PATH, native otherwise
+	GitBackendNative = "native" // pure-Go implementation, no git executable needed
+	GitBackendExec   = "exec"   // the git executable for everything
+)
+
+// ReservationRefPrefix namespaces the refs that reserve feature numbers on the
+// remote, e.g. refs/specify/reserved/004
+const ReservationRefPrefix = "refs/specify/reserved/"
+
+// Lint rule severities
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+	LintSeverityOff     = "off"
+)
+
+// LintRules describes the spec lint rules that can be configured.
+var LintRules = map[string]string{
+	"needs-clarification": "spec contains unresolved [NEEDS CLARIFICATION] markers",
+	"placeholder-text":    "template placeholders such as [FEATURE NAME] were left in place",
+	"required-sections":   "mandatory spec or plan sections are missing",
+	"task-format":         "tasks.md entries do not follow the T### task ID format",
+}
+
+// Precompiled regexes for value validation
+var (
+	numberPrefixRegex = regexp.MustCompile(`^[A-Za-z0-9._-]*$`) // safe in branch names
+	githubNameRegex   = regexp.MustCompile(`^[A-Za-z0-9_.-]*$`)
+	trailerRegex      = regexp.MustCompile(`^[A-Za-z0-9-]+: \S`) // git interpret-trailers format
+)
+
+// ProjectConfig is the configuration stored in .specify/config.yaml. The user
+// config ($XDG_CONFIG_HOME/specify/config.yaml) uses the same schema; project
+// values override user values, and SPECIFY_* environment variables override both.
+type ProjectConfig struct {
+	AI       string       `yaml:"ai,omitempty" json:"ai"`               // default --ai for init
+	CacheDir string       `yaml:"cache_dir,omitempty" json:"cache_dir"` // template cache location
+	GitHub   GitHubConfig `yaml:"github,omitempty" json:"github"`
+	HTTP     HTTPConfig   `yaml:"http,omitempty" json:"http"`
+
+	SpecsDir  string           `yaml:"specs_dir,omitempty" json:"specs_dir"`
+	Numbering NumberingConfig  `yaml:"numbering,omitempty" json:"numbering"`
+	Branch    BranchConfig     `yaml:"branch,omitempty" json:"branch"`
+	Templates TemplateConfig   `yaml:"templates,omitempty" json:"templates"`
+	Agents    []string         `yaml:"agents,omitempty" json:"agents"`
+	Context   ContextConfig    `yaml:"context,omitempty" json:"context"`
+	Lint      LintConfig       `yaml:"lint,omitempty" json:"lint"`
+	Worktree  WorktreeConfig   `yaml:"worktree,omitempty" json:"worktree"`
+	Git       GitBackendConfig `yaml:"git,omitempty" json:"git"`
+	Commit    CommitConfig     `yaml:"commit,omitempty" json:"commit"`
+
+	// Root is the directory containing .specify; relative paths resolve against it
+	Root string `yaml:"-" json:"-"`
+}
+
+// GitHubConfig selects the repository templates are downloaded from and the
+// API server, which is a GitHub Enterprise server's https://<host>/api/v3.
+type GitHubConfig struct {
+	Owner  string `yaml:"owner,omitempty" json:"owner"`
+	Repo   string `yaml:"repo,omitempty" json:"repo"`
+	APIURL string `yaml:"api_url,omitempty" json:"api_url"`
+}
+
+// HTTPConfig controls outgoing HTTP requests.
+type HTTPConfig struct {
+	Timeout         time.Duration `yaml:"timeout,omitempty" json:"timeout"`
+	DownloadTimeout time.Duration `yaml:"download_timeout,omitempty" json:"download_timeout"` // per attempt, for template assets
+	Proxy           string        `yaml:"proxy,omitempty" json:"proxy"`                       // overrides HTTPS_PROXY and HTTP_PROXY; NO_PROXY still applies
+	CABundle        string        `yaml:"ca_bundle,omitempty" json:"ca_bundle"`               // PEM certificates trusted in addition to the system roots
+	Mirror          string        `yaml:"mirror,omitempty" json:"mirror"`                     // replaces https://<github host> in release asset URLs
+}
+
+// NumberingConfig controls how feature numbers are rendered and allocated.
+type NumberingConfig struct {
+	Width   int    `yaml:"width,omitempty" json:"width"`     // zero-padded digits
+	Prefix  string `yaml:"prefix,omitempty" json:"prefix"`   // text before the digits, e.g. "FEAT-"
+	Reserve *bool  `yaml:"reserve,omitempty" json:"reserve"` // push a ref reserving each new number
+	Remote  string `yaml:"remote,omitempty" json:"remote"`   // remote consulted and used for reservations
+}
+
+// BranchConfig controls feature branch naming.
+type BranchConfig struct {
+	Pattern    string   `yaml:"pattern,omitempty" json:"pattern"`         // e.g. feat/{ticket}-{slug}
+	MaxWords   int      `yaml:"max_words,omitempty" json:"max_words"`     // words kept in {slug}
+	Stopwords  []string `yaml:"stopwords,omitempty" json:"stopwords"`     // replaces the built-in list; [none] disables
+	DateFormat string   `yaml:"date_format,omitempty" json:"date_format"` // Go time layout for {date}
+}
+
+// stopwordsNone disables stopword filtering
+const stopwordsNone = "none"
+
+// TemplateConfig overrides template locations, relative to the project root,
+// and adds public keys trusted to sign template bundles.
+type TemplateConfig struct {
+	Spec        string   `yaml:"spec,omitempty" json:"spec"`
+	Plan        string   `yaml:"plan,omitempty" json:"plan"`
+	AgentFile   string   `yaml:"agent_file,omitempty" json:"agent_file"`
+	TrustedKeys []string `yaml:"trusted_keys,omitempty" json:"trusted_keys"` // minisign public keys, in addition to the pinned ones
+}
+
+// ContextConfig controls agent context file updates.
+type ContextConfig struct {
+	History int `yaml:"history,omitempty" json:"history"` // features listed under Recent Changes
+}
+
+// WorktreeConfig controls where 'feature create --worktree' puts worktrees.
+type WorktreeConfig struct {
+	Dir string `yaml:"dir,omitempty" json:"dir"` // relative to the project root
+}
+
+// GitBackendConfig selects how git repositories are accessed.
+type GitBackendConfig struct {
+	Backend string `yaml:"backend,omitempty" json:"backend"` // auto, native or exec
+}
+
+// CommitConfig controls automatic commits of generated spec artifacts.
+type CommitConfig struct {
+	Auto     *bool    `yaml:"auto,omitempty" json:"auto"`         // commit artifacts after create, plan and context
+	Author   string   `yaml:"author,omitempty" json:"author"`     // "Name <email>"; empty uses the git user
+	Trailers []string `yaml:"trailers,omitempty" json:"trailers"` // appended to messages, e.g. "Refs: PROJ-1"
+}
+
+// LintConfig maps lint rule names to their severity.
+type LintConfig struct {
+	Rules map[string]string `yaml:"rules,omitempty" json:"rules"`
+}
+
+// DefaultProjectConfig returns the configuration used when no file exists
+func DefaultProjectConfig() *ProjectConfig {
+	return &ProjectConfig{}
+}
+
+// GetSpecsDir returns the feature root, relative to the project root
+func (c *ProjectConfig) GetSpecsDir() string {
+	if c.SpecsDir == "" {
+		return DefaultSpecsDir
+	}
+	return c.SpecsDir
+}
+
+// GetNumberingWidth returns the number of zero-padded digits in feature numbers
+func (c *ProjectConfig) GetNumberingWidth() int {
+	if c.Numbering.Width == 0 {
+		return DefaultNumberingWidth
+	}
+	return c.Numbering.Width
+}
+
+// ReserveNumbers reports whether new feature numbers are reserved on the remote
+func (c *ProjectConfig) ReserveNumbers() bool {
+	return c.Numbering.Reserve != nil && *c.Numbering.Reserve
+}
+
+// GetNumberingRemote returns the remote used for feature number reservations
+func (c *ProjectConfig) GetNumberingRemote() string {
+	if c.Numbering.Remote == "" {
+		return DefaultNumberingRemote
+	}
+	return c.Numbering.Remote
+}
+
+// GetWorktreeDir returns the directory feature worktrees are created in,
+// relative to the project root. The default is a sibling <repo>-worktrees
+// directory, which keeps worktrees out of the main checkout.
+func (c *ProjectConfig) GetWorktreeDir(repoName string) string {
+	if c.Worktree.Dir == "" {
+		return filepath.Join("..", repoName+"-worktrees")
+	}
+	return c.Worktree.Dir
+}
+
+// GetGitBackend returns the git backend name
+func (c *ProjectConfig) GetGitBackend() string {
+	if c.Git.Backend == "" {
+		return DefaultGitBackend
+	}
+	return c.Git.Backend
+}
+
+// AutoCommit reports whether generated spec artifacts are committed automatically
+func (c *ProjectConfig) AutoCommit() bool {
+	return c.Commit.Auto != nil && *c.Commit.Auto
+}
+
+// GetCommitAuthor returns the author of automatic commits, or nil to use the
+// git user
+func (c *ProjectConfig) GetCommitAuthor() (*GitSignature, error) {
+	if c.Commit.Author == "" {
+		return nil, nil
+	}
+	return ParseGitSignature(c.Commit.Author)
+}
+
+// GetBranchPattern returns the feature branch naming pattern
+func (c *ProjectConfig) GetBranchPattern() string {
+	if c.Branch.Pattern == "" {
+		return DefaultBranchPattern
+	}
+	return c.Branch.Pattern
+}
+
+// GetContextHistory returns the number of features listed under Recent Changes
+func (c *ProjectConfig) GetContextHistory() int {
+	if c.Context.History == 0 {
+		return DefaultChangeHistory
+	}
+	return c.Context.History
+}
+
+// GetGitHubOwner returns the owner of the template repository
+func (c *ProjectConfig) GetGitHubOwner() string {
+	if c.GitHub.Owner == "" {
+		return DefaultGitHubOwner
+	}
+	return c.GitHub.Owner
+}
+
+// GetGitHubRepo returns the name of the template repository
+func (c *ProjectConfig) GetGitHubRepo() string {
+	if c.GitHub.Repo == "" {
+		return DefaultGitHubRepo
+	}
+	return c.GitHub.Repo
+}
+
+// GetGitHubAPIURL returns the base URL of the GitHub API, without a trailing
+// slash
+func (c *ProjectConfig) GetGitHubAPIURL() string {
+	if c.GitHub.APIURL == "" {
+		return DefaultGitHubAPIURL
+	}
+	return strings.TrimRight(c.GitHub.APIURL, "/")
+}
+
+// GetGitHubHost returns the host of the GitHub server: github.com for the
+// public API, or the GitHub Enterprise host
+func (c *ProjectConfig) GetGitHubHost() string {
+	parsed, err := url.Parse(c.GetGitHubAPIURL())
+	if err != nil || parsed.Host == "api.github.com" {
+		return "github.com"
+	}
+	return parsed.Host
+}
+
+// GetHTTPTimeout returns the timeout for HTTP requests
+func (c *ProjectConfig) GetHTTPTimeout() time.Duration {
+	if c.HTTP.Timeout == 0 {
+		return DefaultHTTPTimeout
+	}
+	return c.HTTP.Timeout
+}
+
+// GetDownloadTimeout returns the timeout for one attempt at downloading a
+// release asset
+func (c *ProjectConfig) GetDownloadTimeout() time.Duration {
+	if c.HTTP.DownloadTimeout == 0 {
+		return DefaultDownloadTimeout
+	}
+	return c.HTTP.DownloadTimeout
+}
+
+// GetCABundle returns the path of the CA bundle with a leading "~/" expanded.
+// It is never resolved against the project root: only the user sets it.
+func (c *ProjectConfig) GetCABundle() string {
+	path := c.HTTP.CABundle
+	if strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, path[2:])
+		}
+	}
+	return path
+}
+
+// GetMirror returns the release asset mirror URL without a trailing slash
+func (c *ProjectConfig) GetMirror() string {
+	return strings.TrimSuffix(c.HTTP.Mirror, "/")
+}
+
+// GetLintSeverity returns the configured severity of a lint rule
+func (c *ProjectConfig) GetLintSeverity(rule string) string {
+	if severity, ok := c.Lint.Rules[rule]; ok {
+		return severity
+	}
+	return DefaultLintRuleSetting
+}
+
+// BranchScheme returns the naming scheme for feature branches
+func (c *ProjectConfig) BranchScheme() (*naming.Scheme, error) {
+	stopwords := c.Branch.Stopwords
+	if len(stopwords) == 1 && stopwords[0] == stopwordsNone {
+		stopwords = []string{}
+	}
+
+	return naming.New(naming.Options{
+		Pattern:    c.Branch.Pattern,
+		Width:      c.Numbering.Width,
+		Prefix:     c.Numbering.Prefix,
+		MaxWords:   c.Branch.MaxWords,
+		Stopwords:  stopwords,
+		DateLayout: c.Branch.DateFormat,
+	})
+}
+
+// Validate checks every configured value
+func (c *ProjectConfig) Validate() error {
+	if c.SpecsDir != "" {
+		if filepath.IsAbs(c.SpecsDir) || strings.HasPrefix(filepath.Clean(c.SpecsDir), "..") {
+			return fmt.Errorf("%w: specs_dir must be a path inside the project, got %q", ErrConfigInvalid, c.SpecsDir)
+		}
+	}
+
+	if c.Numbering.Width < 0 || c.Numbering.Width > 9 {
+		return fmt.Errorf("%w: numbering.width must be between 1 and 9, got %d", ErrConfigInvalid, c.Numbering.Width)
+	}
+
+	if !numberPrefixRegex.MatchString(c.Numbering.Prefix) {
+		return fmt.Errorf("%w: numbering.prefix may only contain letters, digits, '.', '_' or '-', got %q",
+			ErrConfigInvalid, c.Numbering.Prefix)
+	}
+
+	if !githubNameRegex.MatchString(c.Numbering.Remote) {
+		return fmt.Errorf("%w: numbering.remote is not a valid remote name, got %q", ErrConfigInvalid, c.Numbering.Remote)
+	}
+
+	if c.Git.Backend != "" && !slices.Contains([]string{GitBackendAuto, GitBackendNative, GitBackendExec}, c.Git.Backend) {
+		return fmt.Errorf("%w: git.backend must be auto, native or exec, got %q", ErrConfigInvalid, c.Git.Backend)
+	}
+
+	if _, err := c.GetCommitAuthor(); err != nil {
+		return fmt.Errorf("commit.author: %w", err)
+	}
+
+	for _, trailer := range c.Commit.Trailers {
+		if !trailerRegex.MatchString(trailer) {
+			return fmt.Errorf("%w: commit.trailers entries must look like \"Token: value\", got %q", ErrConfigInvalid, trailer)
+		}
+	}
+
+	if _, err := c.BranchScheme(); err != nil {
+		return fmt.Errorf("%w: branch.pattern: %w", ErrConfigInvalid, err)
+	}
+
+	if c.Branch.MaxWords < 0 {
+		return fmt.Errorf("%w: branch.max_words must be at least 1, got %d", ErrConfigInvalid, c.Branch.MaxWords)
+	}
+
+	for _, key := range c.Templates.TrustedKeys {
+		if _, err := ParseMinisignPublicKey(key); err != nil {
+			return fmt.Errorf("%w: templates.trusted_keys: %w", ErrConfigInvalid, err)
+		}
+	}
+
+	if c.AI != "" && !IsValidAgent(c.AI) {
+		return fmt.Errorf("%w: unknown agent %q in ai, must be one of: %s",
+			ErrConfigInvalid, c.AI, strings.Join(ListAgents(), ", "))
+	}
+
+	if !githubNameRegex.MatchString(c.GitHub.Owner) || !githubNameRegex.MatchString(c.GitHub.Repo) {
+		return fmt.Errorf("%w: github.owner and github.repo may only contain letters, digits, '.', '_' or '-'", ErrConfigInvalid)
+	}
+
+	if c.GitHub.APIURL != "" {
+		parsed, err := url.Parse(c.GitHub.APIURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("%w: github.api_url must be an http(s) URL such as https://github.example.com/api/v3, got %q",
+				ErrConfigInvalid, c.GitHub.APIURL)
+		}
+	}
+
+	if c.HTTP.Timeout < 0 {
+		return fmt.Errorf("%w: http.timeout must be positive, got %s", ErrConfigInvalid, c.HTTP.Timeout)
+	}
+
+	if c.HTTP.DownloadTimeout < 0 {
+		return fmt.Errorf("%w: http.download_timeout must be positive, got %s", ErrConfigInvalid, c.HTTP.DownloadTimeout)
+	}
+
+	if c.HTTP.Proxy != "" {
+		parsed, err := url.Parse(c.HTTP.Proxy)
+		if err != nil || !slices.Contains([]string{"http", "https", "socks5"}, parsed.Scheme) || parsed.Host == "" {
+			return fmt.Errorf("%w: http.proxy must be an http, https or socks5 URL such as http://proxy.example.com:3128, got %q",
+				ErrConfigInvalid, c.HTTP.Proxy)
+		}
+	}
+
+	if c.HTTP.Mirror != "" {
+		parsed, err := url.Parse(c.HTTP.Mirror)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("%w: http.mirror must be an http(s) URL such as https://artifacts.example.com/github, got %q",
+				ErrConfigInvalid, c.HTTP.Mirror)
+		}
+	}
+
+	for _, agent := range c.Agents {
+		if !IsValidAgent(agent) {
+			return fmt.Errorf("%w: unknown agent %q in agents, must be one of: %s",
+				ErrConfigInvalid, agent, strings.Join(ListAgents(), ", "))
+		}
+	}
+
+	if c.Context.History < 0 {
+		return fmt.Errorf("%w: context.history must be at least 1, got %d", ErrConfigInvalid, c.Context.History)
+	}
+
+	for rule, severity := range c.Lint.Rules {
+		if _, ok := LintRules[rule]; !ok {
+			return fmt.Errorf("%w: unknown lint rule %q, must be one of: %s",
+				ErrConfigInvalid, rule, strings.Join(ListLintRules(), ", "))
+		}
+		if !slices.Contains([]string{LintSeverityError, LintSeverityWarning, LintSeverityOff}, severity) {
+			return fmt.Errorf("%w: lint.rules.%s must be error, warning or off, got %q", ErrConfigInvalid, rule, severity)
+		}
+	}
+
+	return nil
+}
+
+// ListLintRules returns the configurable lint rule names (sorted)
+func ListLintRules() []string {
+	rules := slices.Collect(maps.Keys(LintRules))
+	slices.Sort(rules)
+	return rules
+}
+
+// configKey binds a dotted configuration key to its field. raw returns an
+// empty string when the key is not set in this configuration.
+type configKey struct {
+	raw      func(c *ProjectConfig) string
+	set      func(c *ProjectConfig, value string) error
+	def      string
+	userOnly bool // a repository must not set it in its project config
+}
+
+// configKeys lists every settable key; lint rules are addressed as lint.rules.<rule>
+var configKeys = map[string]configKey{
+	"specs_dir": {
+		raw: func(c *ProjectConfig) string { return c.SpecsDir },
+		set: func(c *ProjectConfig, v string) error { c.SpecsDir = v; return nil },
+		def: DefaultSpecsDir,
+	},
+	"numbering.width": {
+		raw: func(c *ProjectConfig) string { return formatInt(c.Numbering.Width) },
+		set: func(c *ProjectConfig, v string) error { return setInt(&c.Numbering.Width, "numbering.width", v) },
+		def: strconv.Itoa(DefaultNumberingWidth),
+	},
+	"numbering.prefix": {
+		raw: func(c *ProjectConfig) string { return c.Numbering.Prefix },
+		set: func(c *ProjectConfig, v string) error { c.Numbering.Prefix = v; return nil },
+	},
+	"numbering.reserve": {
+		raw: func(c *ProjectConfig) string { return formatBool(c.Numbering.Reserve) },
+		set: func(c *ProjectConfig, v string) error { return setBool(&c.Numbering.Reserve, "numbering.reserve", v) },
+		def: "false",
+	},
+	"numbering.remote": {
+		raw: func(c *ProjectConfig) string { return c.Numbering.Remote },
+		set: func(c *ProjectConfig, v string) error { c.Numbering.Remote = v; return nil },
+		def: DefaultNumberingRemote,
+	},
+	"branch.pattern": {
+		raw: func(c *ProjectConfig) string { return c.Branch.Pattern },
+		set: func(c *ProjectConfig, v string) error { c.Branch.Pattern = v; return nil },
+		def: DefaultBranchPattern,
+	},
+	"branch.max_words": {
+		raw: func(c *ProjectConfig) string { return formatInt(c.Branch.MaxWords) },
+		set: func(c *ProjectConfig, v string) error { return setInt(&c.Branch.MaxWords, "branch.max_words", v) },
+		def: strconv.Itoa(naming.DefaultMaxWords),
+	},
+	"branch.stopwords": {
+		raw: func(c *ProjectConfig) string { return strings.Join(c.Branch.Stopwords, ",") },
+		set: func(c *ProjectConfig, v string) error { c.Branch.Stopwords = splitList(v); return nil },
+		def: strings.Join(naming.DefaultStopwords, ","),
+	},
+	"branch.date_format": {
+		raw: func(c *ProjectConfig) string { return c.Branch.DateFormat },
+		set: func(c *ProjectConfig, v string) error { c.Branch.DateFormat = v; return nil },
+		def: naming.DefaultDateLayout,
+	},
+	"worktree.dir": {
+		raw: func(c *ProjectConfig) string { return c.Worktree.Dir },
+		set: func(c *ProjectConfig, v string) error { c.Worktree.Dir = v; return nil },
+		def: "../<repo>-worktrees",
+	},
+	"git.backend": {
+		raw: func(c *ProjectConfig) string { return c.Git.Backend },
+		set: func(c *ProjectConfig, v string) error { c.Git.Backend = v; return nil },
+		def: DefaultGitBackend,
+	},
+	"commit.auto": {
+		raw: func(c *ProjectConfig) string { return formatBool(c.Commit.Auto) },
+		set: func(c *ProjectConfig, v string) error { return setBool(&c.Commit.Auto, "commit.auto", v) },
+		def: "false",
+	},
+	"commit.author": {
+		raw: func(c *ProjectConfig) string { return c.Commit.Author },
+		set: func(c *ProjectConfig, v string) error { c.Commit.Author = v; return nil },
+	},
+	"commit.trailers": {
+		raw: func(c *ProjectConfig) string { return strings.Join(c.Commit.Trailers, "\n") },
+		set: func(c *ProjectConfig, v string) error { c.Commit.Trailers = splitLines(v); return nil },
+	},
+	"templates.spec": {
+		raw: func(c *ProjectConfig) string { return c.Templates.Spec },
+		set: func(c *ProjectConfig, v string) error { c.Templates.Spec = v; return nil },
+	},
+	"templates.plan": {
+		raw: func(c *ProjectConfig) string { return c.Templates.Plan },
+		set: func(c *ProjectConfig, v string) error { c.Templates.Plan = v; return nil },
+	},
+	"templates.agent_file": {
+		raw: func(c *ProjectConfig) string { return c.Templates.AgentFile },
+		set: func(c *ProjectConfig, v string) error { c.Templates.AgentFile = v; return nil },
+	},
+	"templates.trusted_keys": {
+		raw:      func(c *ProjectConfig) string { return strings.Join(c.Templates.TrustedKeys, ",") },
+		set:      func(c *ProjectConfig, v string) error { c.Templates.TrustedKeys = splitList(v); return nil },
+		userOnly: true,
+	},
+	"agents": {
+		raw: func(c *ProjectConfig) string { return strings.Join(c.Agents, ",") },
+		set: func(c *ProjectConfig, v string) error { c.Agents = splitList(v); return nil },
+	},
+	"context.history": {
+		raw: func(c *ProjectConfig) string { return formatInt(c.Context.History) },
+		set: func(c *ProjectConfig, v string) error { return setInt(&c.Context.History, "context.history", v) },
+		def: strconv.Itoa(DefaultChangeHistory),
+	},
+	"ai": {
+		raw: func(c *ProjectConfig) string { return c.AI },
+		set: func(c *ProjectConfig, v string) error { c.AI = v; return nil },
+	},
+	"cache_dir": {
+		raw:      func(c *ProjectConfig) string { return c.CacheDir },
+		set:      func(c *ProjectConfig, v string) error { c.CacheDir = v; return nil },
+		def:      DefaultCacheDir,
+		userOnly: true, // cleared and replaced by templates sync and clear
+	},
+	"github.owner": {
+		raw: func(c *ProjectConfig) string { return c.GitHub.Owner },
+		set: func(c *ProjectConfig, v string) error { c.GitHub.Owner = v; return nil },
+		def: DefaultGitHubOwner,
+	},
+	"github.repo": {
+		raw: func(c *ProjectConfig) string { return c.GitHub.Repo },
+		set: func(c *ProjectConfig, v string) error { c.GitHub.Repo = v; return nil },
+		def: DefaultGitHubRepo,
+	},
+	"github.api_url": {
+		raw:      func(c *ProjectConfig) string { return c.GitHub.APIURL },
+		set:      func(c *ProjectConfig, v string) error { c.GitHub.APIURL = v; return nil },
+		def:      DefaultGitHubAPIURL,
+		userOnly: true, // the GitHub token is sent to it
+	},
+	"http.timeout": {
+		raw: func(c *ProjectConfig) string { return formatDuration(c.HTTP.Timeout) },
+		set: func(c *ProjectConfig, v string) error { return setDuration(&c.HTTP.Timeout, "http.timeout", v) },
+		def: DefaultHTTPTimeout.String(),
+	},
+	"http.download_timeout": {
+		raw: func(c *ProjectConfig) string { return formatDuration(c.HTTP.DownloadTimeout) },
+		set: func(c *ProjectConfig, v string) error {
+			return setDuration(&c.HTTP.DownloadTimeout, "http.download_timeout", v)
+		},
+		def: DefaultDownloadTimeout.String(),
+	},
+	"http.proxy": {
+		raw:      func(c *ProjectConfig) string { return c.HTTP.Proxy },
+		set:      func(c *ProjectConfig, v string) error { c.HTTP.Proxy = v; return nil },
+		userOnly: true, // sees all traffic
+	},
+	"http.ca_bundle": {
+		raw:      func(c *ProjectConfig) string { return c.HTTP.CABundle },
+		set:      func(c *ProjectConfig, v string) error { c.HTTP.CABundle = v; return nil },
+		userOnly: true, // could let a proxy impersonate GitHub
+	},
+	"http.mirror": {
+		raw:      func(c *ProjectConfig) string { return c.HTTP.Mirror },
+		set:      func(c *ProjectConfig, v string) error { c.HTTP.Mirror = v; return nil },
+		userOnly: true, // serves the template bundles
+	},
+}
+
+// lintRuleKeyPrefix addresses individual lint rules
+const lintRuleKeyPrefix = "lint.rules."
+
+// ListConfigKeys returns every configuration key (sorted)
+func ListConfigKeys() []string {
+	keys := slices.Collect(maps.Keys(configKeys))
+	for _, rule := range ListLintRules() {
+		keys = append(keys, lintRuleKeyPrefix+rule)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// IsUserOnlyConfigKey reports whether a key may only be set in the user config
+// or the environment. A project config setting it, e.g. a repository trusting
+// its own template signing key, is rejected.
+func IsUserOnlyConfigKey(key string) bool {
+	return configKeys[key].userOnly
+}
+
+// ConfigEnvVar returns the environment variable that overrides a key, e.g.
+// SPECIFY_HTTP_TIMEOUT for http.timeout
+func ConfigEnvVar(key string) string {
+	return "SPECIFY_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Get returns the effective value of a configuration key
+func (c *ProjectConfig) Get(key string) (string, error) {
+	value, err := c.GetRaw(key)
+	if err != nil {
+		return "", err
+	}
+	if value != "" {
+		return value, nil
+	}
+
+	if _, ok := strings.CutPrefix(key, lintRuleKeyPrefix); ok {
+		return DefaultLintRuleSetting, nil
+	}
+	return configKeys[key].def, nil
+}
+
+// GetRaw returns the value of a key as set in this configuration, or an empty
+// string when it is not set
+func (c *ProjectConfig) GetRaw(key string) (string, error) {
+	if rule, ok := strings.CutPrefix(key, lintRuleKeyPrefix); ok {
+		if _, known := LintRules[rule]; !known {
+			return "", fmt.Errorf("%w: %s", ErrConfigUnknownKey, key)
+		}
+		return c.Lint.Rules[rule], nil
+	}
+
+	entry, ok := configKeys[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrConfigUnknownKey, key)
+	}
+	return entry.raw(c), nil
+}
+
+// Set assigns a configuration key and validates the resulting configuration.
+// An empty value resets the key to its default.
+func (c *ProjectConfig) Set(key, value string) error {
+	updated := c.clone()
+	if err := updated.assign(key, value); err != nil {
+		return err
+	}
+
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+
+	*c = *updated
+	return nil
+}
+
+// Overlay copies every key set in the project configuration other over this
+// configuration. It fails when other sets a key only the user may set.
+func (c *ProjectConfig) Overlay(other *ProjectConfig) error {
+	for _, key := range ListConfigKeys() {
+		value, _ := other.GetRaw(key)
+		if value == "" {
+			continue
+		}
+		if IsUserOnlyConfigKey(key) {
+			return fmt.Errorf("%w: %s cannot be set in the project config; set it with 'specify config set --global' or %s",
+				ErrConfigInvalid, key, ConfigEnvVar(key))
+		}
+		if err := c.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assign sets a key without validating the whole configuration
+func (c *ProjectConfig) assign(key, value string) error {
+	if rule, ok := strings.CutPrefix(key, lintRuleKeyPrefix); ok {
+		if _, known := LintRules[rule]; !known {
+			return fmt.Errorf("%w: %s", ErrConfigUnknownKey, key)
+		}
+		if value == "" {
+			delete(c.Lint.Rules, rule)
+			return nil
+		}
+		if c.Lint.Rules == nil {
+			c.Lint.Rules = make(map[string]string)
+		}
+		c.Lint.Rules[rule] = value
+		return nil
+	}
+
+	entry, ok := configKeys[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrConfigUnknownKey, key)
+	}
+	return entry.set(c, value)
+}
+
+// clone returns a deep copy of the configuration
+func (c *ProjectConfig) clone() *ProjectConfig {
+	copied := *c
+	copied.Agents = slices.Clone(c.Agents)
+	copied.Branch.Stopwords = slices.Clone(c.Branch.Stopwords)
+	copied.Lint.Rules = maps.Clone(c.Lint.Rules)
+	copied.Commit.Trailers = slices.Clone(c.Commit.Trailers)
+	copied.Templates.TrustedKeys = slices.Clone(c.Templates.TrustedKeys)
+	return &copied
+}
+
+// setInt parses an integer configuration value; empty resets it to the default
+func setInt(field *int, key, value string) error {
+	if value == "" {
+		*field = 0
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return fmt.Errorf("%w: %s must be a positive integer, got %q", ErrConfigInvalid, key, value)
+	}
+	*field = n
+	return nil
+}
+
+// setDuration parses a duration such as "45s"; empty resets it to the default
+func setDuration(field *time.Duration, key, value string) error {
+	if value == "" {
+		*field = 0
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("%w: %s must be a positive duration such as 30s, got %q", ErrConfigInvalid, key, value)
+	}
+	*field = d
+	return nil
+}
+
+// setBool parses a boolean configuration value; empty resets it to the default
+func setBool(field **bool, key, value string) error {
+	if value == "" {
+		*field = nil
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%w: %s must be true or false, got %q", ErrConfigInvalid, key, value)
+	}
+	*field = &b
+	return nil
+}
+
+// formatBool renders an unset boolean as an empty string
+func formatBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+// formatInt renders an unset (zero) integer as an empty string
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// formatDuration renders an unset (zero) duration as an empty string
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitLines splits a newline-separated value, dropping empty items; used for
+// values such as trailers that may contain commas
+func splitLines(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, "\n") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
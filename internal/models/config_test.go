@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProjectConfigSet(t *testing.T) {
+	f := func(name, key, value, expected string, expectedErr error) {
+		t.Helper()
+
+		config := DefaultProjectConfig()
+		err := config.Set(key, value)
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("%s: got error %v, expected %v", name, err, expectedErr)
+		}
+		if err != nil {
+			return
+		}
+
+		result, err := config.Get(key)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if result != expected {
+			t.Fatalf("%s: got %q, expected %q", name, result, expected)
+		}
+	}
+
+	f("width", "numbering.width", "4", "4", nil)
+	f("width reset", "numbering.width", "", "3", nil)
+	f("width not a number", "numbering.width", "four", "", ErrConfigInvalid)
+	f("width too large", "numbering.width", "12", "", ErrConfigInvalid)
+	f("prefix", "numbering.prefix", "FEAT-", "FEAT-", nil)
+	f("prefix with slash", "numbering.prefix", "a/b", "", ErrConfigInvalid)
+	f("pattern without number", "branch.pattern", "{slug}", "", ErrConfigInvalid)
+	f("ticket pattern", "branch.pattern", "feat/{ticket}-{slug}", "feat/{ticket}-{slug}", nil)
+	f("unknown placeholder", "branch.pattern", "{num}-{user}", "", ErrConfigInvalid)
+	f("stopwords", "branch.stopwords", "a, the", "a,the", nil)
+	f("agents", "agents", "claude, codex", "claude,codex", nil)
+	f("unknown agent", "agents", "claude,vim", "", ErrConfigInvalid)
+	f("specs dir outside project", "specs_dir", "../specs", "", ErrConfigInvalid)
+	f("git backend", "git.backend", "native", "native", nil)
+	f("unknown git backend", "git.backend", "libgit2", "", ErrConfigInvalid)
+	f("commit author", "commit.author", "Spec Bot <bot@example.com>", "Spec Bot <bot@example.com>", nil)
+	f("commit author without name", "commit.author", "bot@example.com", "", ErrConfigInvalid)
+	f("commit trailers", "commit.trailers", "Refs: PROJ-1\n Signed-off-by: A <a@example.com>\n", "Refs: PROJ-1\nSigned-off-by: A <a@example.com>", nil)
+	f("trailer with comma", "commit.trailers", "Reviewed-by: Doe, Jane <j@example.com>", "Reviewed-by: Doe, Jane <j@example.com>", nil)
+	f("malformed trailer", "commit.trailers", "see PROJ-1", "", ErrConfigInvalid)
+	f("enterprise api url", "github.api_url", "https://github.example.com/api/v3/", "https://github.example.com/api/v3/", nil)
+	f("api url without scheme", "github.api_url", "github.example.com/api/v3", "", ErrConfigInvalid)
+	f("trusted key", "templates.trusted_keys", "RWQBAgMEBQYHCAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", "RWQBAgMEBQYHCAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", nil)
+	f("malformed trusted key", "templates.trusted_keys", "RWQnotakey", "", ErrConfigInvalid)
+	f("proxy", "http.proxy", "http://proxy.example.com:3128", "http://proxy.example.com:3128", nil)
+	f("proxy without scheme", "http.proxy", "proxy.example.com:3128", "", ErrConfigInvalid)
+	f("mirror without host", "http.mirror", "https://", "", ErrConfigInvalid)
+	f("lint rule", "lint.rules.task-format", "error", "error", nil)
+	f("lint severity", "lint.rules.task-format", "fatal", "", ErrConfigInvalid)
+	f("unknown lint rule", "lint.rules.spelling", "error", "", ErrConfigUnknownKey)
+	f("unknown key", "colors", "on", "", ErrConfigUnknownKey)
+}
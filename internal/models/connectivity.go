@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reasons a connectivity check fails
+const (
+	ConnectivityDNS        = "dns"        // the host name does not resolve
+	ConnectivityProxy      = "proxy"      // the proxy refused or failed the connection
+	ConnectivityTLS        = "tls"        // the certificate is not trusted or the handshake failed
+	ConnectivityTimeout    = "timeout"    // no answer before the deadline
+	ConnectivityConnection = "connection" // the connection was refused or reset
+	ConnectivityHTTP       = "http"       // the server answered with an error status
+)
+
+// ConnectivityCheck is the result of probing one endpoint of the template source
+type ConnectivityCheck struct {
+	URL      string        `json:"url"`
+	OK       bool          `json:"ok"`
+	Status   int           `json:"status,omitempty"`  // HTTP status, when the server answered
+	Failure  string        `json:"failure,omitempty"` // one of the Connectivity* reasons
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// String describes the check, e.g. "https://api.github.com: dns: no such host"
+func (c ConnectivityCheck) String() string {
+	if c.OK {
+		return fmt.Sprintf("%s: reachable (%d)", c.URL, c.Status)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.URL, c.Failure, c.Error)
+}
+
+// ConnectivityReport is the result of probing the template source
+type ConnectivityReport struct {
+	Checks  []ConnectivityCheck `json:"checks"`
+	Skipped string              `json:"skipped,omitempty"` // why the probe was skipped, e.g. a local source
+}
+
+// OK reports whether every endpoint is reachable or the probe was skipped
+func (r *ConnectivityReport) OK() bool {
+	for _, check := range r.Checks {
+		if !check.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures describes the endpoints that could not be reached
+func (r *ConnectivityReport) Failures() string {
+	var failures []string
+	for _, check := range r.Checks {
+		if !check.OK {
+			failures = append(failures, check.String())
+		}
+	}
+	return strings.Join(failures, "; ")
+}
@@ -13,6 +13,8 @@ type Environment struct {
 	HasInternet bool                  `json:"has_internet"` // Internet connectivity status
 	GitConfig   GitConfig             `json:"git_config"`   // Git configuration details
 	WorkingDir  string                `json:"working_dir"`  // Current working directory
+
+	Connectivity *ConnectivityReport `json:"connectivity,omitempty"` // Per-endpoint results of the connectivity probe
 }
 
 // ToolStatus represents the status of a development tool
@@ -182,16 +184,22 @@ func (e *Environment) GetPlatformDisplayName() string {
 	}
 }
 
+// TemplateSourceAvailable reports whether templates can be obtained: the
+// template source is reachable, or the probe was skipped for a local source
+func (e *Environment) TemplateSourceAvailable() bool {
+	return e.HasInternet || (e.Connectivity != nil && e.Connectivity.Skipped != "")
+}
+
 // CanInitializeProjects returns whether the environment is ready for project initialization
 func (e *Environment) CanInitializeProjects() bool {
-	// At minimum, we need internet connectivity
+	// At minimum, we need the template source
 	// Git is optional but recommended
-	return e.HasInternet
+	return e.TemplateSourceAvailable()
 }
 
 // GetReadinessStatus returns a summary of environment readiness
 func (e *Environment) GetReadinessStatus() string {
-	if !e.HasInternet {
+	if !e.TemplateSourceAvailable() {
 		return "Internet connection required"
 	}
 
@@ -9,6 +9,9 @@ var (
 	ErrTemplateExtractionFailed = errors.New("template extraction failed")
 	ErrTemplateCorrupted        = errors.New("template corrupted")
 	ErrTemplateCacheFailed      = errors.New("template cache failed")
+	ErrChecksumMismatch         = errors.New("checksum verification failed")
+	ErrTemplateUnsigned         = errors.New("template bundle is not signed by a trusted key")
+	ErrSignatureInvalid         = errors.New("template signature verification failed")
 )
 
 // Sentinel errors for project operations
@@ -26,3 +29,29 @@ var (
 	ErrInternetNotAvailable   = errors.New("internet not available")
 	ErrGitConfigMissing       = errors.New("git config missing")
 )
+
+// Sentinel errors for configuration operations
+var (
+	ErrConfigInvalid    = errors.New("invalid configuration")
+	ErrConfigUnknownKey = errors.New("unknown configuration key")
+)
+
+// Sentinel errors for feature operations
+var (
+	ErrFeatureNotFound  = errors.New("feature not found")
+	ErrFeatureAmbiguous = errors.New("feature selector is ambiguous")
+)
+
+// Sentinel errors for git operations
+var (
+	ErrBranchExists     = errors.New("branch already exists")
+	ErrWorkingTreeDirty = errors.New("working tree has uncommitted changes")
+	ErrRefNotFound      = errors.New("ref not found")
+)
+
+// Sentinel errors for GitHub operations
+var (
+	ErrGitHubAuthRequired = errors.New("GitHub authentication required")
+	ErrGitHubRequest      = errors.New("GitHub request failed")
+	ErrGitHubRateLimited  = errors.New("GitHub rate limit exceeded")
+)
@@ -1,12 +1,27 @@
 package models
 
+import "time"
+
+// Feature archive layout, relative to the specs directory
+const (
+	ArchiveDirName      = "_archive"
+	ArchiveMetadataFile = "archive.json"
+)
+
 // ValidAgents are provided by models.ListAgents()/IsValidAgent
 
 // FeatureCreateResult represents the result of creating a new feature
 type FeatureCreateResult struct {
-	BranchName string `json:"branch_name"`
-	SpecFile   string `json:"spec_file"`
-	FeatureNum string `json:"feature_num"`
+	BranchName string   `json:"branch_name"`
+	SpecFile   string   `json:"spec_file"`
+	FeatureNum string   `json:"feature_num"`
+	Reserved   string   `json:"reserved,omitempty"` // reservation ref pushed for the number
+	Worktree   string   `json:"worktree,omitempty"` // worktree the branch is checked out in
+	BaseRef    string   `json:"base_ref,omitempty"` // ref the branch was created from; empty is HEAD
+	Stashed    bool     `json:"stashed,omitempty"`  // uncommitted changes were stashed first
+	Commit     string   `json:"commit,omitempty"`   // automatic commit of the spec
+	Issue      string   `json:"issue,omitempty"`    // URL of the issue the spec was seeded from
+	Warnings   []string `json:"warnings,omitempty"` // problems that did not stop the feature being created
 }
 
 // FeaturePlanResult represents the result of setting up a feature plan
@@ -15,6 +30,7 @@ type FeaturePlanResult struct {
 	ImplPlan    string `json:"impl_plan"`
 	SpecsDir    string `json:"specs_dir"`
 	Branch      string `json:"branch"`
+	Commit      string `json:"commit,omitempty"` // automatic commit of the plan
 }
 
 // FeatureCheckResult represents the result of checking feature prerequisites
@@ -25,7 +41,9 @@ type FeatureCheckResult struct {
 
 // ContextUpdate represents an update to an agent context file
 type ContextUpdate struct {
-	Agent string `json:"agent"`
+	Agent   string `json:"agent"`
+	File    string `json:"file"`
+	Created bool   `json:"created"`
 }
 
 // FeatureContextResult represents the result of updating agent context files
@@ -33,6 +51,7 @@ type FeatureContextResult struct {
 	Branch  string          `json:"branch"`
 	Updates []ContextUpdate `json:"updates"`
 	Summary []string        `json:"summary"`
+	Commit  string          `json:"commit,omitempty"` // automatic commit of the context files
 }
 
 // FeaturePathsResult represents the paths for a feature branch
@@ -44,3 +63,81 @@ type FeaturePathsResult struct {
 	ImplPlan    string `json:"impl_plan"`
 	Tasks       string `json:"tasks"`
 }
+
+// FeatureRenumberResult represents the result of renumbering a feature
+type FeatureRenumberResult struct {
+	OldBranch    string   `json:"old_branch"`
+	NewBranch    string   `json:"new_branch"`
+	OldNumber    string   `json:"old_number"`
+	NewNumber    string   `json:"new_number"`
+	FeatureDir   string   `json:"feature_dir,omitempty"`
+	Collisions   []string `json:"collisions"` // other features using the old number
+	UpdatedFiles []string `json:"updated_files,omitempty"`
+	Reservation  string   `json:"reservation,omitempty"`
+	Renumbered   bool     `json:"renumbered"`
+}
+
+// Worktree is a git worktree of the repository
+type Worktree struct {
+	Path     string `json:"path"`
+	Branch   string `json:"branch,omitempty"`
+	Head     string `json:"head"`
+	Detached bool   `json:"detached,omitempty"`
+}
+
+// FeatureWorktree is a worktree checked out on a feature branch
+type FeatureWorktree struct {
+	Worktree
+	FeatureDir string `json:"feature_dir"`
+	HasSpec    bool   `json:"has_spec"`
+}
+
+// FeatureRenameResult represents the result of renaming a feature
+type FeatureRenameResult struct {
+	OldBranch    string   `json:"old_branch"`
+	NewBranch    string   `json:"new_branch"`
+	OldDir       string   `json:"old_dir"`
+	NewDir       string   `json:"new_dir"`
+	DirMoved     bool     `json:"dir_moved"` // false when the feature had no spec directory yet
+	UpdatedFiles []string `json:"updated_files,omitempty"`
+}
+
+// FeatureArchive is the metadata stored with an archived feature
+type FeatureArchive struct {
+	Feature    string    `json:"feature"`
+	Branch     string    `json:"branch,omitempty"`
+	Commit     string    `json:"commit,omitempty"` // branch head when archived
+	ArchivedAt time.Time `json:"archived_at"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+// FeatureArchiveResult represents the result of archiving a feature
+type FeatureArchiveResult struct {
+	Archive       FeatureArchive `json:"archive"`
+	ArchiveDir    string         `json:"archive_dir"`
+	BranchDeleted bool           `json:"branch_deleted"`
+}
+
+// FeatureDeleteResult represents the result of deleting a feature
+type FeatureDeleteResult struct {
+	Branch        string `json:"branch"`
+	FeatureDir    string `json:"feature_dir"`
+	BranchDeleted bool   `json:"branch_deleted"`
+	SwitchedTo    string `json:"switched_to,omitempty"`
+}
+
+// LintFinding is a problem 'specify feature lint' found in a spec artifact
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`       // LintSeverityError or LintSeverityWarning
+	File     string `json:"file"`           // relative to the feature directory
+	Line     int    `json:"line,omitempty"` // 0 for the whole file
+	Message  string `json:"message"`
+}
+
+// FeatureLintResult represents the result of linting a feature
+type FeatureLintResult struct {
+	FeatureDir string        `json:"feature_dir"`
+	Findings   []LintFinding `json:"findings"`
+	Errors     int           `json:"errors"` // findings with LintSeverityError
+}
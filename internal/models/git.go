@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// GitSignature identifies the author of a commit
+type GitSignature struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// String formats the signature the way git does, e.g. "Jane Doe <jane@example.com>"
+func (s GitSignature) String() string {
+	return s.Name + " <" + s.Email + ">"
+}
+
+// ParseGitSignature parses "Name <email>"
+func ParseGitSignature(value string) (*GitSignature, error) {
+	address, err := mail.ParseAddress(value)
+	if err != nil || strings.TrimSpace(address.Name) == "" {
+		return nil, fmt.Errorf("%w: expected \"Name <email>\", got %q", ErrConfigInvalid, value)
+	}
+	return &GitSignature{Name: address.Name, Email: address.Address}, nil
+}
+
+// GitCommit is a commit returned by a log query
+type GitCommit struct {
+	Hash    string       `json:"hash"`
+	Author  GitSignature `json:"author"`
+	Date    time.Time    `json:"date"`
+	Subject string       `json:"subject"`
+}
+
+// GitFileStatus is the status of one changed file. Staging and Worktree hold
+// the porcelain status codes: ' ' unmodified, 'M' modified, 'A' added,
+// 'D' deleted, 'R' renamed, 'C' copied, 'U' unmerged and '?' untracked.
+type GitFileStatus struct {
+	Path     string `json:"path"`
+	Staging  byte   `json:"staging"`
+	Worktree byte   `json:"worktree"`
+}
+
+// IsUntracked reports whether the file is not known to git
+func (s GitFileStatus) IsUntracked() bool {
+	return s.Worktree == '?'
+}
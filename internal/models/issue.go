@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Issue is a GitHub issue a feature is created from
+type Issue struct {
+	Repository GitHubRepository `json:"repository"`
+	Number     int              `json:"number"`
+	Title      string           `json:"title"`
+	Body       string           `json:"body,omitempty"`
+	Labels     []string         `json:"labels,omitempty"`
+	State      string           `json:"state,omitempty"` // open or closed
+	URL        string           `json:"url"`
+}
+
+// Reference returns owner/name#number
+func (i Issue) Reference() string {
+	return fmt.Sprintf("%s#%d", i.Repository, i.Number)
+}
+
+// ParseIssueReference parses owner/name#123 or an issue URL such as
+// https://github.com/owner/name/issues/123
+func ParseIssueReference(value string) (*GitHubRepository, int, error) {
+	value = strings.TrimSpace(value)
+
+	repo, number, ok := strings.Cut(value, "#")
+	if !ok && strings.Contains(value, "://") {
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid issue URL %q: %w", value, err)
+		}
+		path, issue, found := strings.Cut(strings.Trim(parsed.Path, "/"), "/issues/")
+		repo, number, ok = parsed.Scheme+"://"+parsed.Host+"/"+path, issue, found
+	}
+
+	n, err := strconv.Atoi(number)
+	if !ok || err != nil || n <= 0 {
+		return nil, 0, fmt.Errorf("not a GitHub issue: %q, expected owner/name#123", value)
+	}
+
+	repository, err := ParseGitHubRepository(repo)
+	if err != nil {
+		return nil, 0, fmt.Errorf("not a GitHub issue: %q, expected owner/name#123", value)
+	}
+	return repository, n, nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import "testing"
+
+func TestParseIssueReference(t *testing.T) {
+	f := func(value, expected string) {
+		t.Helper()
+
+		repo, number, err := ParseIssueReference(value)
+		if expected == "" {
+			if err == nil {
+				t.Fatalf("ParseIssueReference(%q) = %s#%d, expected an error", value, repo, number)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("ParseIssueReference(%q): %v", value, err)
+		}
+		if actual := (Issue{Repository: *repo, Number: number}).Reference(); actual != expected {
+			t.Fatalf("ParseIssueReference(%q) = %s, expected %s", value, actual, expected)
+		}
+	}
+
+	f("acme/widgets#123", "acme/widgets#123")
+	f("https://github.com/acme/widgets/issues/7", "acme/widgets#7")
+	f("acme/widgets", "")
+	f("acme/widgets#0", "")
+	f("acme#12", "")
+	f("https://github.com/acme/widgets/pull/7", "")
+}
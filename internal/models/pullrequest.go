@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// PullRequest is a pull request composed from a feature's artifacts
+type PullRequest struct {
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Head   string `json:"head"` // feature branch
+	Base   string `json:"base"` // branch the feature merges into
+	Draft  bool   `json:"draft,omitempty"`
+	Number int    `json:"number,omitempty"` // set once the pull request is opened
+	URL    string `json:"url,omitempty"`    // set once the pull request is opened
+}
+
+// TaskProgress counts the checkbox tasks of a tasks.md file
+type TaskProgress struct {
+	Total int      `json:"total"`
+	Done  int      `json:"done"`
+	Open  []string `json:"open,omitempty"` // tasks not checked off yet
+}
+
+// GitHubRepository identifies a repository on GitHub
+type GitHubRepository struct {
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+}
+
+// String returns owner/name
+func (r GitHubRepository) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// ParseGitHubRepository parses owner/name, or a remote URL such as
+// git@github.com:owner/name.git or https://github.com/owner/name
+func ParseGitHubRepository(value string) (*GitHubRepository, error) {
+	path := strings.TrimSpace(value)
+
+	switch {
+	case strings.Contains(path, "://"):
+		parsed, err := url.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository URL %q: %w", value, err)
+		}
+		path = parsed.Path
+	case strings.Contains(path, "@") && strings.Contains(path, ":"):
+		// scp-like syntax: git@github.com:owner/name.git
+		path = path[strings.Index(path, ":")+1:]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	owner, name, ok := strings.Cut(path, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") ||
+		!githubNameRegex.MatchString(owner) || !githubNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("not a GitHub repository: %q, expected owner/name", value)
+	}
+	return &GitHubRepository{Owner: owner, Name: name}, nil
+}
@@ -0,0 +1,31 @@
+package models
+
+import "testing"
+
+func TestParseGitHubRepository(t *testing.T) {
+	f := func(value, expected string) {
+		t.Helper()
+
+		repo, err := ParseGitHubRepository(value)
+		if expected == "" {
+			if err == nil {
+				t.Fatalf("ParseGitHubRepository(%q) = %s, expected an error", value, repo)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("ParseGitHubRepository(%q): %v", value, err)
+		}
+		if repo.String() != expected {
+			t.Fatalf("ParseGitHubRepository(%q) = %s, expected %s", value, repo, expected)
+		}
+	}
+
+	f("acme/widgets", "acme/widgets")
+	f("git@github.com:acme/widgets.git", "acme/widgets")
+	f("https://github.com/acme/widgets", "acme/widgets")
+	f("https://github.example.com/acme/widgets.git/", "acme/widgets")
+	f("ssh://git@github.com/acme/widgets.git", "acme/widgets")
+	f("widgets", "")
+	f("https://gitlab.com/group/sub/widgets", "")
+}
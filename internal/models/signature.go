@@ -0,0 +1,41 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// minisignAlgorithm identifies Ed25519 keys and legacy signatures in the
+// minisign format; prehashed signatures use "ED".
+const minisignAlgorithm = "Ed"
+
+// MinisignPublicKey is an Ed25519 public key in the minisign format
+type MinisignPublicKey struct {
+	ID  [8]byte
+	Key ed25519.PublicKey
+}
+
+// KeyID returns the key ID in the upper-case hex form minisign prints
+func (k *MinisignPublicKey) KeyID() string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(k.ID[:]))
+}
+
+// ParseMinisignPublicKey parses the base64 line of a minisign public key
+// ("RWQ..."). The contents of a minisign.pub file, with its untrusted comment
+// line, are accepted as well.
+func ParseMinisignPublicKey(value string) (*MinisignPublicKey, error) {
+	lines := strings.Split(strings.TrimSpace(value), "\n")
+	encoded := strings.TrimSpace(lines[len(lines)-1])
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(data) != 2+8+ed25519.PublicKeySize || string(data[:2]) != minisignAlgorithm {
+		return nil, fmt.Errorf("%q is not a minisign Ed25519 public key", encoded)
+	}
+
+	key := &MinisignPublicKey{Key: ed25519.PublicKey(data[10:])}
+	copy(key.ID[:], data[2:10])
+	return key, nil
+}
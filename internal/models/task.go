@@ -0,0 +1,41 @@
+package models
+
+// Task is a numbered checkbox task of a tasks.md file
+type Task struct {
+	ID          string   `json:"id"` // e.g. T001
+	Description string   `json:"description"`
+	Phase       string   `json:"phase,omitempty"`
+	Parallel    bool     `json:"parallel,omitempty"` // marked [P]
+	Done        bool     `json:"done"`
+	DependsOn   []string `json:"depends_on,omitempty"` // IDs of tasks that must be done first
+}
+
+// Actions taken on the issue of a task during an export
+const (
+	TaskIssueCreated   = "created"
+	TaskIssueUpdated   = "updated"
+	TaskIssueUnchanged = "unchanged"
+)
+
+// TaskIssue links a task to the GitHub issue tracking it
+type TaskIssue struct {
+	Task   string `json:"task"`
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	State  string `json:"state"`
+	Action string `json:"action,omitempty"` // created, updated or unchanged
+}
+
+// TaskExportResult represents the result of exporting tasks to GitHub issues
+type TaskExportResult struct {
+	Repository string      `json:"repository"`
+	TasksFile  string      `json:"tasks_file"`
+	Issues     []TaskIssue `json:"issues"`
+}
+
+// TaskImportResult represents the result of importing issue states into tasks.md
+type TaskImportResult struct {
+	Repository string   `json:"repository"`
+	TasksFile  string   `json:"tasks_file"`
+	Checked    []string `json:"checked"` // tasks checked off because their issue is closed
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// TechProfileFile is the tech profile location relative to the specs directory
+const TechProfileFile = "tech-profile.json"
+
+// DefaultChangeHistory is the number of features listed under Recent Changes
+const DefaultChangeHistory = 3
+
+// Language is a programming language with an optional version.
+type Language struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// String returns the language name followed by its version, if known
+func (l Language) String() string {
+	if l.Version == "" {
+		return l.Name
+	}
+	return l.Name + " " + l.Version
+}
+
+// TechStack is the technology information declared in the Technical Context
+// section of a feature plan.
+type TechStack struct {
+	Languages        []Language `json:"languages,omitempty"`
+	Frameworks       []string   `json:"frameworks,omitempty"`
+	Storage          []string   `json:"storage,omitempty"`
+	Testing          []string   `json:"testing,omitempty"`
+	TargetPlatforms  []string   `json:"target_platforms,omitempty"`
+	ProjectType      string     `json:"project_type,omitempty"`
+	PerformanceGoals []string   `json:"performance_goals,omitempty"`
+	Constraints      []string   `json:"constraints,omitempty"`
+}
+
+// IsEmpty reports whether no technology information was declared
+func (s TechStack) IsEmpty() bool {
+	return len(s.Languages) == 0 && len(s.Frameworks) == 0 && len(s.Storage) == 0 &&
+		len(s.Testing) == 0 && len(s.TargetPlatforms) == 0 && s.ProjectType == "" &&
+		len(s.PerformanceGoals) == 0 && len(s.Constraints) == 0
+}
+
+// Merge returns the union of both stacks, keeping the order of first appearance.
+// A language declared with and without a version is only listed once.
+func (s TechStack) Merge(other TechStack) TechStack {
+	merged := TechStack{
+		Languages:        slices.Clone(s.Languages),
+		Frameworks:       mergeUnique(s.Frameworks, other.Frameworks),
+		Storage:          mergeUnique(s.Storage, other.Storage),
+		Testing:          mergeUnique(s.Testing, other.Testing),
+		TargetPlatforms:  mergeUnique(s.TargetPlatforms, other.TargetPlatforms),
+		ProjectType:      s.ProjectType,
+		PerformanceGoals: mergeUnique(s.PerformanceGoals, other.PerformanceGoals),
+		Constraints:      mergeUnique(s.Constraints, other.Constraints),
+	}
+
+	for _, lang := range other.Languages {
+		idx := slices.IndexFunc(merged.Languages, func(l Language) bool {
+			return strings.EqualFold(l.Name, lang.Name)
+		})
+		switch {
+		case idx == -1:
+			merged.Languages = append(merged.Languages, lang)
+		case merged.Languages[idx].Version == "":
+			merged.Languages[idx].Version = lang.Version
+		}
+	}
+
+	if other.ProjectType != "" {
+		merged.ProjectType = other.ProjectType
+	}
+
+	return merged
+}
+
+// Additions returns the technologies in s that are not part of base
+func (s TechStack) Additions(base TechStack) TechStack {
+	additions := TechStack{
+		Frameworks:       subtract(s.Frameworks, base.Frameworks),
+		Storage:          subtract(s.Storage, base.Storage),
+		Testing:          subtract(s.Testing, base.Testing),
+		TargetPlatforms:  subtract(s.TargetPlatforms, base.TargetPlatforms),
+		PerformanceGoals: subtract(s.PerformanceGoals, base.PerformanceGoals),
+		Constraints:      subtract(s.Constraints, base.Constraints),
+	}
+
+	for _, lang := range s.Languages {
+		known := slices.ContainsFunc(base.Languages, func(l Language) bool {
+			return strings.EqualFold(l.Name, lang.Name) && (lang.Version == "" || strings.EqualFold(l.Version, lang.Version))
+		})
+		if !known {
+			additions.Languages = append(additions.Languages, lang)
+		}
+	}
+
+	if !strings.EqualFold(s.ProjectType, base.ProjectType) {
+		additions.ProjectType = s.ProjectType
+	}
+
+	return additions
+}
+
+// FeatureTech records the stack declared by a single feature plan.
+type FeatureTech struct {
+	Feature string    `json:"feature"` // feature directory name
+	Stack   TechStack `json:"stack"`
+}
+
+// ChangeEntry is one feature in the Recent Changes history.
+type ChangeEntry struct {
+	Feature string   `json:"feature"`         // feature directory name
+	Title   string   `json:"title,omitempty"` // spec.md title
+	Added   []string `json:"added,omitempty"` // technologies first introduced by the feature
+}
+
+// String renders the entry as a single Recent Changes line
+func (c ChangeEntry) String() string {
+	line := c.Feature
+	if c.Title != "" {
+		line += ": " + c.Title
+	}
+	if len(c.Added) > 0 {
+		line += " (added " + strings.Join(c.Added, ", ") + ")"
+	}
+	return line
+}
+
+// TechProfile is the project-wide technology profile merged from all feature
+// plans, persisted as specs/tech-profile.json.
+type TechProfile struct {
+	UpdatedAt time.Time     `json:"updated_at"`
+	Stack     TechStack     `json:"stack"`
+	Features  []FeatureTech `json:"features"`
+	Commands  []string      `json:"commands,omitempty"`
+	History   []ChangeEntry `json:"history,omitempty"` // most recent feature first
+}
+
+// NewTechProfile merges the given feature stacks into a profile
+func NewTechProfile(features []FeatureTech) *TechProfile {
+	profile := &TechProfile{
+		UpdatedAt: time.Now().UTC(),
+		Features:  features,
+	}
+	for _, feature := range features {
+		profile.Stack = profile.Stack.Merge(feature.Stack)
+	}
+	return profile
+}
+
+// GetFeature returns the stack recorded for a feature
+func (p *TechProfile) GetFeature(feature string) (TechStack, bool) {
+	for _, f := range p.Features {
+		if f.Feature == feature {
+			return f.Stack, true
+		}
+	}
+	return TechStack{}, false
+}
+
+// subtract returns the items of a that are not in b (case-insensitive)
+func subtract(a, b []string) []string {
+	var result []string
+	for _, item := range a {
+		if !containsFold(b, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// mergeUnique appends items from b that are not in a (case-insensitive)
+func mergeUnique(a, b []string) []string {
+	result := slices.Clone(a)
+	for _, item := range b {
+		if !containsFold(result, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// containsFold reports whether list contains item, ignoring case
+func containsFold(list []string, item string) bool {
+	return slices.ContainsFunc(list, func(s string) bool { return strings.EqualFold(s, item) })
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// CacheVersion is a template version in the cache: the active one templates
+// are extracted from, or one archived when a sync replaced it
+type CacheVersion struct {
+	Version  string    `json:"version"`
+	Path     string    `json:"path"`
+	Active   bool      `json:"active"`
+	Files    int       `json:"files"` // files listed in the manifest
+	Size     int64     `json:"size"`  // bytes on disk
+	LastSync time.Time `json:"last_sync"`
+}
+
+// Problems found by cache verification
+const (
+	CacheFileMissing  = "missing"
+	CacheFileModified = "modified"
+)
+
+// CacheFileProblem is a file that does not match the cache manifest
+type CacheFileProblem struct {
+	Path     string `json:"path"`
+	Problem  string `json:"problem"` // CacheFileMissing or CacheFileModified
+	Expected string `json:"expected"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// CacheVerification is the result of checking the cache against its manifest
+type CacheVerification struct {
+	Root     string             `json:"root"`
+	Version  string             `json:"version"`
+	Checked  int                `json:"checked"`
+	Problems []CacheFileProblem `json:"problems"`
+	Extra    []string           `json:"extra"` // files not in the manifest
+}
+
+// OK reports whether every file matches the manifest
+func (v *CacheVerification) OK() bool {
+	return len(v.Problems) == 0
+}
+
+// CacheRemoval lists what pruning or clearing the cache removed
+type CacheRemoval struct {
+	Removed []string `json:"removed"`
+	Freed   int64    `json:"freed"` // bytes
+}
+
+// CacheRepair lists what repairing the cache changed
+type CacheRepair struct {
+	Root     string   `json:"root"`
+	Version  string   `json:"version"`
+	Restored []string `json:"restored"` // copied from an archived version
+	Fetched  []string `json:"fetched"`  // read from the release bundle
+	Removed  []string `json:"removed"`  // files not in the manifest
+}
+
+// Changed reports whether the repair changed the cache
+func (r *CacheRepair) Changed() bool {
+	return len(r.Restored)+len(r.Fetched)+len(r.Removed) > 0
+}
@@ -0,0 +1,358 @@
+// Package naming formats and parses feature branch names.
+//
+// A scheme is a pattern with placeholders:
+//
+//	{num}     zero-padded feature number with optional prefix, e.g. 004 or FEAT-0004
+//	{ticket}  issue tracker key, e.g. PROJ-1234
+//	{slug}    words from the feature description, e.g. user-login
+//	{date}    creation date in the configured layout, e.g. 20250131
+//
+// Patterns such as "{num}-{slug}", "feat/{ticket}-{slug}" or "{date}-{slug}"
+// are supported. The feature directory under specs/ is named after the part of
+// the branch following the last '/'.
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Placeholders understood in patterns
+const (
+	PlaceholderNum    = "{num}"
+	PlaceholderTicket = "{ticket}"
+	PlaceholderSlug   = "{slug}"
+	PlaceholderDate   = "{date}"
+)
+
+// Defaults used when a scheme option is left empty
+const (
+	DefaultPattern    = PlaceholderNum + "-" + PlaceholderSlug
+	DefaultWidth      = 3
+	DefaultMaxWords   = 3
+	DefaultDateLayout = "20060102"
+)
+
+// DefaultStopwords are dropped from descriptions when building slugs.
+var DefaultStopwords = []string{
+	"a", "an", "the", "to", "for", "of", "in", "on", "at", "by", "with", "from",
+	"and", "or", "is", "are", "be", "i", "we", "want", "need", "should", "this", "that",
+}
+
+// Sentinel errors for scheme operations
+var (
+	ErrInvalidPattern = errors.New("invalid branch pattern")
+	ErrMissingTicket  = errors.New("branch pattern requires a ticket")
+	ErrEmptySlug      = errors.New("description produces an empty branch slug")
+)
+
+// Precompiled regexes for placeholders and values
+var (
+	placeholderRe = regexp.MustCompile(`\{[a-z]+\}`)
+	ticketRe      = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-\d+$`)
+)
+
+// Options configure a scheme; zero values select the defaults.
+type Options struct {
+	Pattern    string
+	Width      int      // digits in {num}
+	Prefix     string   // text before the digits in {num}
+	MaxWords   int      // words kept in {slug}
+	Stopwords  []string // nil uses DefaultStopwords; empty disables filtering
+	DateLayout string   // Go time layout for {date}
+}
+
+// Scheme formats and parses branch names for one pattern.
+type Scheme struct {
+	opts    Options
+	branch  *regexp.Regexp
+	dir     *regexp.Regexp
+	dirPart string // pattern used for directory names
+}
+
+// Values are substituted into a pattern by Format.
+type Values struct {
+	Number int
+	Ticket string
+	Slug   string
+	Date   time.Time
+}
+
+// Name is a parsed branch or directory name.
+type Name struct {
+	Number    int
+	HasNumber bool
+	Ticket    string
+	Slug      string
+	Date      string
+}
+
+// ID returns the identifier of the feature: its number, ticket or date
+func (n Name) ID() string {
+	switch {
+	case n.HasNumber:
+		return strconv.Itoa(n.Number)
+	case n.Ticket != "":
+		return n.Ticket
+	default:
+		return n.Date
+	}
+}
+
+// New validates the pattern and compiles its parser.
+func New(opts Options) (*Scheme, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = DefaultPattern
+	}
+	if opts.Width == 0 {
+		opts.Width = DefaultWidth
+	}
+	if opts.MaxWords == 0 {
+		opts.MaxWords = DefaultMaxWords
+	}
+	if opts.Stopwords == nil {
+		opts.Stopwords = DefaultStopwords
+	}
+	if opts.DateLayout == "" {
+		opts.DateLayout = DefaultDateLayout
+	}
+
+	if err := validatePattern(opts.Pattern); err != nil {
+		return nil, err
+	}
+
+	s := &Scheme{opts: opts}
+	s.dirPart = opts.Pattern[strings.LastIndex(opts.Pattern, "/")+1:]
+
+	var err error
+	if s.branch, err = s.compile(opts.Pattern); err != nil {
+		return nil, err
+	}
+	if s.dir, err = s.compile(s.dirPart); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Pattern returns the pattern of the scheme
+func (s *Scheme) Pattern() string {
+	return s.opts.Pattern
+}
+
+// HasPlaceholder reports whether the pattern uses the given placeholder
+func (s *Scheme) HasPlaceholder(placeholder string) bool {
+	return strings.Contains(s.opts.Pattern, placeholder)
+}
+
+// FormatNumber renders a feature number with the prefix and padding of {num}
+func (s *Scheme) FormatNumber(number int) string {
+	return fmt.Sprintf("%s%0*d", s.opts.Prefix, s.opts.Width, number)
+}
+
+// Slugify turns a description into a slug: lowercase words joined by '-',
+// stopwords removed and at most MaxWords kept
+func (s *Scheme) Slugify(description string) string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+
+	kept := []string{}
+	for _, word := range words {
+		if slices.Contains(s.opts.Stopwords, word) {
+			continue
+		}
+		if len(kept) == s.opts.MaxWords {
+			break
+		}
+		kept = append(kept, word)
+	}
+
+	// A description made only of stopwords still yields a usable slug
+	if len(kept) == 0 && len(words) > 0 {
+		kept = words[:min(len(words), s.opts.MaxWords)]
+	}
+
+	return strings.Join(kept, "-")
+}
+
+// Format renders a branch name from values
+func (s *Scheme) Format(v Values) (string, error) {
+	if s.HasPlaceholder(PlaceholderTicket) {
+		if v.Ticket == "" {
+			return "", fmt.Errorf("%w: %s", ErrMissingTicket, s.opts.Pattern)
+		}
+		if !ticketRe.MatchString(v.Ticket) {
+			return "", fmt.Errorf("%w: %q does not look like KEY-123", ErrMissingTicket, v.Ticket)
+		}
+	}
+	if s.HasPlaceholder(PlaceholderSlug) && v.Slug == "" {
+		return "", ErrEmptySlug
+	}
+
+	replacer := strings.NewReplacer(
+		PlaceholderNum, s.FormatNumber(v.Number),
+		PlaceholderTicket, v.Ticket,
+		PlaceholderSlug, v.Slug,
+		PlaceholderDate, v.Date.Format(s.opts.DateLayout),
+	)
+	return replacer.Replace(s.opts.Pattern), nil
+}
+
+// Parse matches a branch name, or a feature directory name, against the pattern
+func (s *Scheme) Parse(name string) (Name, bool) {
+	if parsed, ok := s.parse(s.branch, name); ok {
+		return parsed, true
+	}
+	return s.parse(s.dir, name)
+}
+
+// Renumber replaces the number in a branch or directory name, keeping the rest
+// of the name. It reports false when name does not match the pattern or the
+// pattern has no {num}.
+func (s *Scheme) Renumber(name string, number int) (string, bool) {
+	return s.replaceGroup(name, "num", fmt.Sprintf("%0*d", s.opts.Width, number))
+}
+
+// Reslug replaces the slug in a branch or directory name, keeping its number,
+// ticket and date. It reports false when name does not match the pattern or the
+// pattern has no {slug}.
+func (s *Scheme) Reslug(name, slug string) (string, bool) {
+	return s.replaceGroup(name, "slug", slug)
+}
+
+// replaceGroup replaces the text matched by a placeholder group in name
+func (s *Scheme) replaceGroup(name, group, value string) (string, bool) {
+	for _, re := range []*regexp.Regexp{s.branch, s.dir} {
+		index := re.SubexpIndex(group)
+		loc := re.FindStringSubmatchIndex(name)
+		if index < 0 || loc == nil {
+			continue
+		}
+		start, end := loc[2*index], loc[2*index+1]
+		return name[:start] + value + name[end:], true
+	}
+	return name, false
+}
+
+// DirName returns the feature directory name for a branch name
+func (s *Scheme) DirName(branch string) string {
+	if strings.Contains(s.opts.Pattern, "/") {
+		return branch[strings.LastIndex(branch, "/")+1:]
+	}
+	return branch
+}
+
+// parse matches name against a compiled pattern
+func (s *Scheme) parse(re *regexp.Regexp, name string) (Name, bool) {
+	match := re.FindStringSubmatch(name)
+	if match == nil {
+		return Name{}, false
+	}
+
+	parsed := Name{}
+	for i, placeholder := range re.SubexpNames()[1:] {
+		value := match[i+1]
+		switch placeholder {
+		case "num":
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return Name{}, false
+			}
+			parsed.Number, parsed.HasNumber = number, true
+		case "ticket":
+			parsed.Ticket = value
+		case "slug":
+			parsed.Slug = value
+		case "date":
+			parsed.Date = value
+		}
+	}
+	return parsed, true
+}
+
+// compile turns a pattern into an anchored regex with named groups
+func (s *Scheme) compile(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	last := 0
+	for _, loc := range placeholderRe.FindAllStringIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		last = loc[1]
+
+		expr := ""
+		switch pattern[loc[0]:loc[1]] {
+		case PlaceholderNum:
+			expr = regexp.QuoteMeta(s.opts.Prefix) + fmt.Sprintf(`(?P<num>\d{%d,})`, s.opts.Width)
+		case PlaceholderTicket:
+			expr = `(?P<ticket>[A-Za-z][A-Za-z0-9]*-\d+)`
+		case PlaceholderSlug:
+			expr = `(?P<slug>[^/]+)`
+		case PlaceholderDate:
+			expr = `(?P<date>` + dateExpr(s.opts.DateLayout) + `)`
+		}
+		b.WriteString(expr)
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPattern, pattern, err)
+	}
+	return re, nil
+}
+
+// dateExpr converts a numeric Go time layout into a regex
+func dateExpr(layout string) string {
+	var b strings.Builder
+	for _, r := range time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(layout) {
+		if r >= '0' && r <= '9' {
+			b.WriteString(`\d`)
+		} else {
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// validatePattern checks placeholders and that the pattern identifies a feature
+func validatePattern(pattern string) error {
+	known := []string{PlaceholderNum, PlaceholderTicket, PlaceholderSlug, PlaceholderDate}
+
+	seen := make(map[string]bool)
+	for _, placeholder := range placeholderRe.FindAllString(pattern, -1) {
+		if !slices.Contains(known, placeholder) {
+			return fmt.Errorf("%w: unknown placeholder %s in %q (use %s)",
+				ErrInvalidPattern, placeholder, pattern, strings.Join(known, ", "))
+		}
+		if seen[placeholder] {
+			return fmt.Errorf("%w: %s appears more than once in %q", ErrInvalidPattern, placeholder, pattern)
+		}
+		seen[placeholder] = true
+	}
+
+	if !seen[PlaceholderNum] && !seen[PlaceholderTicket] && !seen[PlaceholderDate] {
+		return fmt.Errorf("%w: %q must contain {num}, {ticket} or {date}", ErrInvalidPattern, pattern)
+	}
+
+	// Feature directories are named after the last segment, which must identify the feature
+	dirPart := pattern[strings.LastIndex(pattern, "/")+1:]
+	if !strings.Contains(dirPart, PlaceholderNum) && !strings.Contains(dirPart, PlaceholderTicket) &&
+		!strings.Contains(dirPart, PlaceholderDate) {
+		return fmt.Errorf("%w: the part of %q after the last '/' must contain {num}, {ticket} or {date}",
+			ErrInvalidPattern, pattern)
+	}
+
+	if strings.ContainsAny(pattern, " ~^:?*[\\") || strings.Contains(pattern, "..") {
+		return fmt.Errorf("%w: %q contains characters not allowed in git branch names", ErrInvalidPattern, pattern)
+	}
+
+	return nil
+}
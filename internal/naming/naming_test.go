@@ -0,0 +1,169 @@
+package naming
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFormatAndParse(t *testing.T) {
+	date := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	f := func(name string, opts Options, values Values, expected string, expectedDir string) {
+		t.Helper()
+
+		scheme, err := New(opts)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		branch, err := scheme.Format(values)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if branch != expected {
+			t.Fatalf("%s: got %q, expected %q", name, branch, expected)
+		}
+
+		// The parser must recognize both the branch and its directory
+		for _, candidate := range []string{branch, scheme.DirName(branch)} {
+			parsed, ok := scheme.Parse(candidate)
+			if !ok {
+				t.Fatalf("%s: %q was not recognized", name, candidate)
+			}
+			if parsed.Slug != values.Slug || parsed.Ticket != values.Ticket {
+				t.Fatalf("%s: parsed %+v from %q", name, parsed, candidate)
+			}
+			if scheme.HasPlaceholder(PlaceholderNum) && parsed.Number != values.Number {
+				t.Fatalf("%s: parsed number %d, expected %d", name, parsed.Number, values.Number)
+			}
+		}
+
+		if dir := scheme.DirName(branch); dir != expectedDir {
+			t.Fatalf("%s: got directory %q, expected %q", name, dir, expectedDir)
+		}
+	}
+
+	f("default", Options{}, Values{Number: 4, Slug: "user-login"}, "004-user-login", "004-user-login")
+	f("four digits with prefix", Options{Width: 4, Prefix: "F-"}, Values{Number: 12, Slug: "export"},
+		"F-0012-export", "F-0012-export")
+	f("ticket with directory prefix", Options{Pattern: "feat/{ticket}-{slug}"},
+		Values{Ticket: "PROJ-1234", Slug: "invoice-pdf"}, "feat/PROJ-1234-invoice-pdf", "PROJ-1234-invoice-pdf")
+	f("date based", Options{Pattern: "{date}-{num}-{slug}"}, Values{Number: 1, Slug: "search", Date: date},
+		"20250131-001-search", "20250131-001-search")
+	f("custom date layout", Options{Pattern: "{date}/{slug}-{num}", DateLayout: "2006-01"},
+		Values{Number: 7, Slug: "audit", Date: date}, "2025-01/audit-007", "audit-007")
+}
+
+func TestParseRejects(t *testing.T) {
+	f := func(name, pattern, branch string) {
+		t.Helper()
+
+		scheme, err := New(Options{Pattern: pattern})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if parsed, ok := scheme.Parse(branch); ok {
+			t.Fatalf("%s: %q unexpectedly parsed as %+v", name, branch, parsed)
+		}
+	}
+
+	f("main branch", "", "main")
+	f("too few digits", "", "01-login")
+	f("missing slug", "", "001")
+	f("missing ticket", "feat/{ticket}-{slug}", "feat/login")
+	f("wrong prefix", "feat/{ticket}-{slug}", "fix/PROJ-1-login")
+}
+
+func TestSlugify(t *testing.T) {
+	f := func(name string, opts Options, description, expected string) {
+		t.Helper()
+
+		scheme, err := New(opts)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if result := scheme.Slugify(description); result != expected {
+			t.Fatalf("%s: got %q, expected %q", name, result, expected)
+		}
+	}
+
+	f("stopwords removed", Options{}, "Add a page for the user's login history", "add-page-user")
+	f("punctuation collapsed", Options{}, "  OAuth2 -- SSO!! support ", "oauth2-sso-support")
+	f("more words", Options{MaxWords: 5}, "I want to export invoices as PDF files", "export-invoices-as-pdf-files")
+	f("filtering disabled", Options{Stopwords: []string{}}, "the user login", "the-user-login")
+	f("only stopwords", Options{}, "for the", "for-the")
+}
+
+func TestInvalidPatterns(t *testing.T) {
+	f := func(name, pattern string) {
+		t.Helper()
+
+		if _, err := New(Options{Pattern: pattern}); !errors.Is(err, ErrInvalidPattern) {
+			t.Fatalf("%s: got error %v, expected %v", name, err, ErrInvalidPattern)
+		}
+	}
+
+	f("no identifier", "{slug}")
+	f("unknown placeholder", "{num}-{user}")
+	f("duplicate placeholder", "{num}-{num}")
+	f("identifier only in prefix", "{num}/{slug}")
+	f("space", "{num} {slug}")
+}
+
+func TestFormatRequiresTicket(t *testing.T) {
+	scheme, err := New(Options{Pattern: "{ticket}-{slug}"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := scheme.Format(Values{Slug: "login"}); !errors.Is(err, ErrMissingTicket) {
+		t.Fatalf("got error %v, expected %v", err, ErrMissingTicket)
+	}
+	if _, err := scheme.Format(Values{Ticket: "not a ticket", Slug: "login"}); !errors.Is(err, ErrMissingTicket) {
+		t.Fatalf("got error %v, expected %v", err, ErrMissingTicket)
+	}
+}
+
+func TestRenumber(t *testing.T) {
+	f := func(name string, opts Options, input string, number int, expected string, expectedOK bool) {
+		t.Helper()
+
+		scheme, err := New(opts)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		result, ok := scheme.Renumber(input, number)
+		if ok != expectedOK || result != expected {
+			t.Fatalf("%s: got (%q, %v), expected (%q, %v)", name, result, ok, expected, expectedOK)
+		}
+	}
+
+	f("default", Options{}, "004-user-login", 7, "007-user-login", true)
+	f("digits in slug", Options{}, "004-oauth2-004", 12, "012-oauth2-004", true)
+	f("prefix kept", Options{Width: 4, Prefix: "F-"}, "F-0012-export", 13, "F-0013-export", true)
+	f("directory prefix", Options{Pattern: "team/{num}-{slug}"}, "team/003-search", 5, "team/005-search", true)
+	f("directory name", Options{Pattern: "team/{num}-{slug}"}, "003-search", 5, "005-search", true)
+	f("date kept", Options{Pattern: "{date}-{num}-{slug}"}, "20250101-001-search", 2, "20250101-002-search", true)
+	f("no number", Options{Pattern: "{ticket}-{slug}"}, "PROJ-1-login", 2, "PROJ-1-login", false)
+	f("not a feature", Options{}, "main", 2, "main", false)
+}
+
+func TestReslug(t *testing.T) {
+	f := func(name, pattern, input, slug, expected string, expectedOK bool) {
+		t.Helper()
+
+		scheme, err := New(Options{Pattern: pattern})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		result, ok := scheme.Reslug(input, slug)
+		if ok != expectedOK || result != expected {
+			t.Fatalf("%s: got (%q, %v), expected (%q, %v)", name, result, ok, expected, expectedOK)
+		}
+	}
+
+	f("default", "", "004-user-login", "sign-in", "004-sign-in", true)
+	f("ticket kept", "feat/{ticket}-{slug}", "feat/PROJ-1-login", "sso", "feat/PROJ-1-sso", true)
+	f("no slug", "{num}", "004", "login", "004", false)
+}
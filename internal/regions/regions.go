@@ -0,0 +1,350 @@
+// Package regions maintains named, tool-managed blocks inside text files.
+//
+// A managed region is delimited by HTML comment markers so it stays invisible
+// when Markdown is rendered:
+//
+//	<!-- specify:begin tech -->
+//	## Active Technologies
+//	- Go 1.25 + cobra
+//	<!-- specify:end tech -->
+//
+// Everything outside the markers is treated as manual content and is preserved
+// byte-for-byte when regions are rewritten.
+package regions
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Sentinel errors for malformed documents
+var (
+	ErrNestedRegion    = errors.New("nested managed region")
+	ErrUnclosedRegion  = errors.New("unclosed managed region")
+	ErrUnmatchedEnd    = errors.New("end marker without matching begin marker")
+	ErrMismatchedEnd   = errors.New("end marker does not match open region")
+	ErrDuplicateRegion = errors.New("duplicate managed region")
+	ErrInvalidName     = errors.New("invalid region name")
+)
+
+// Precompiled regexes for marker lines and region names
+var (
+	markerRe = regexp.MustCompile(`^\s*<!--\s*specify:(begin|end)\s+(\S+)\s*-->\s*$`)
+	nameRe   = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+)
+
+// Region is a named managed block within a document.
+type Region struct {
+	Name string
+	Body string
+}
+
+// segment is either literal (manual) text or a managed region
+type segment struct {
+	text   string
+	region *Region
+}
+
+// Document is a parsed text containing zero or more managed regions.
+type Document struct {
+	segments []segment
+	noEOL    bool // the document ends with an end marker lacking a newline
+}
+
+// Begin returns the opening marker line for a region.
+func Begin(name string) string {
+	return fmt.Sprintf("<!-- specify:begin %s -->", name)
+}
+
+// End returns the closing marker line for a region.
+func End(name string) string {
+	return fmt.Sprintf("<!-- specify:end %s -->", name)
+}
+
+// Wrap renders a region with its markers, terminated by a newline.
+func Wrap(name, body string) string {
+	return Begin(name) + "\n" + normalizeBody(body) + End(name) + "\n"
+}
+
+// ValidateName reports whether name can be used as a region name.
+func ValidateName(name string) error {
+	if !nameRe.MatchString(name) {
+		return fmt.Errorf("%w: %q (use lowercase letters, digits, '-' or '_')", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// Parse splits content into manual text and managed regions.
+// It fails on nested, unclosed, mismatched or duplicate markers.
+func Parse(content string) (*Document, error) {
+	doc := &Document{}
+	seen := make(map[string]int)
+
+	var literal strings.Builder
+	var body strings.Builder
+	var open *Region
+	openLine := 0
+
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		lineNum := i + 1
+		match := markerRe.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+
+		if match == nil {
+			if open != nil {
+				body.WriteString(line)
+			} else {
+				literal.WriteString(line)
+			}
+			continue
+		}
+
+		kind, name := match[1], match[2]
+		if err := ValidateName(name); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		if kind == "begin" {
+			if open != nil {
+				return nil, fmt.Errorf("%w: %q begins at line %d inside %q opened at line %d",
+					ErrNestedRegion, name, lineNum, open.Name, openLine)
+			}
+			if first, ok := seen[name]; ok {
+				return nil, fmt.Errorf("%w: %q at line %d (first defined at line %d)",
+					ErrDuplicateRegion, name, lineNum, first)
+			}
+			seen[name] = lineNum
+
+			if literal.Len() > 0 {
+				doc.segments = append(doc.segments, segment{text: literal.String()})
+				literal.Reset()
+			}
+			open = &Region{Name: name}
+			openLine = lineNum
+			continue
+		}
+
+		if open == nil {
+			return nil, fmt.Errorf("%w: %q at line %d", ErrUnmatchedEnd, name, lineNum)
+		}
+		if name != open.Name {
+			return nil, fmt.Errorf("%w: found end of %q at line %d, but %q opened at line %d is still open",
+				ErrMismatchedEnd, name, lineNum, open.Name, openLine)
+		}
+
+		open.Body = body.String()
+		body.Reset()
+		doc.segments = append(doc.segments, segment{region: open})
+		open = nil
+
+		// Files ending on an end marker without a newline round-trip unchanged
+		doc.noEOL = !strings.HasSuffix(line, "\n")
+	}
+
+	if open != nil {
+		return nil, fmt.Errorf("%w: %q opened at line %d", ErrUnclosedRegion, open.Name, openLine)
+	}
+
+	if literal.Len() > 0 {
+		doc.segments = append(doc.segments, segment{text: literal.String()})
+	}
+
+	return doc, nil
+}
+
+// Names returns region names in document order.
+func (d *Document) Names() []string {
+	names := []string{}
+	for _, seg := range d.segments {
+		if seg.region != nil {
+			names = append(names, seg.region.Name)
+		}
+	}
+	return names
+}
+
+// Has reports whether the document contains the named region.
+func (d *Document) Has(name string) bool {
+	return d.find(name) != nil
+}
+
+// Get returns the body of the named region.
+func (d *Document) Get(name string) (string, bool) {
+	if region := d.find(name); region != nil {
+		return region.Body, true
+	}
+	return "", false
+}
+
+// Set replaces the body of the named region, appending the region at the end
+// of the document when it does not exist yet.
+func (d *Document) Set(name, body string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	if region := d.find(name); region != nil {
+		region.Body = normalizeBody(body)
+		return nil
+	}
+
+	if sep := d.separator(); sep != "" {
+		d.segments = append(d.segments, segment{text: sep})
+	}
+	d.segments = append(d.segments, segment{region: &Region{Name: name, Body: normalizeBody(body)}})
+	d.noEOL = false
+	return nil
+}
+
+// Remove deletes the named region including its markers.
+func (d *Document) Remove(name string) bool {
+	idx := slices.IndexFunc(d.segments, func(seg segment) bool {
+		return seg.region != nil && seg.region.Name == name
+	})
+	if idx == -1 {
+		return false
+	}
+	d.segments = slices.Delete(d.segments, idx, idx+1)
+	return true
+}
+
+// AdoptSection converts an unmanaged Markdown section into a managed region.
+// The section starts at a line equal to heading and runs until the next heading
+// of the same or higher level (or the end of the document); trailing blank
+// lines stay outside the region. It returns false when no such section exists
+// or the region is already present.
+func (d *Document) AdoptSection(heading, name string) bool {
+	if d.Has(name) || ValidateName(name) != nil {
+		return false
+	}
+
+	level := headingLevel(heading)
+	for i, seg := range d.segments {
+		if seg.region != nil {
+			continue
+		}
+
+		lines := strings.SplitAfter(seg.text, "\n")
+		start := slices.IndexFunc(lines, func(line string) bool {
+			return strings.TrimSpace(line) == heading
+		})
+		if start == -1 {
+			continue
+		}
+
+		end := len(lines)
+		for j := start + 1; j < len(lines); j++ {
+			if l := headingLevel(strings.TrimSpace(lines[j])); l > 0 && l <= level {
+				end = j
+				break
+			}
+		}
+
+		// Leave trailing blank lines as manual spacing
+		bodyEnd := end
+		for bodyEnd > start+1 && strings.TrimSpace(lines[bodyEnd-1]) == "" {
+			bodyEnd--
+		}
+
+		before := strings.Join(lines[:start], "")
+		body := strings.Join(lines[start:bodyEnd], "")
+		after := strings.Join(lines[bodyEnd:], "")
+
+		replacement := []segment{}
+		if before != "" {
+			replacement = append(replacement, segment{text: before})
+		}
+		replacement = append(replacement, segment{region: &Region{Name: name, Body: normalizeBody(body)}})
+		if after != "" {
+			replacement = append(replacement, segment{text: after})
+		}
+
+		d.segments = slices.Replace(d.segments, i, i+1, replacement...)
+		return true
+	}
+
+	return false
+}
+
+// String renders the document with all regions and their markers.
+func (d *Document) String() string {
+	var b strings.Builder
+	for _, seg := range d.segments {
+		if seg.region != nil {
+			b.WriteString(Wrap(seg.region.Name, seg.region.Body))
+		} else {
+			b.WriteString(seg.text)
+		}
+	}
+
+	rendered := b.String()
+	if d.noEOL && len(d.segments) > 0 && d.segments[len(d.segments)-1].region != nil {
+		rendered = strings.TrimSuffix(rendered, "\n")
+	}
+	return rendered
+}
+
+// Upsert parses content, sets the named region and returns the rendered result.
+func Upsert(content, name, body string) (string, error) {
+	doc, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+	if err := doc.Set(name, body); err != nil {
+		return "", err
+	}
+	return doc.String(), nil
+}
+
+// find returns the named region or nil
+func (d *Document) find(name string) *Region {
+	for _, seg := range d.segments {
+		if seg.region != nil && seg.region.Name == name {
+			return seg.region
+		}
+	}
+	return nil
+}
+
+// separator returns the text needed before appending a new region so that it
+// starts on its own line after a blank line
+func (d *Document) separator() string {
+	rendered := d.String()
+	if d.noEOL {
+		rendered += "\n"
+	}
+	switch {
+	case rendered == "":
+		return ""
+	case strings.HasSuffix(rendered, "\n\n"):
+		return ""
+	case strings.HasSuffix(rendered, "\n"):
+		return "\n"
+	default:
+		return "\n\n"
+	}
+}
+
+// normalizeBody ensures a non-empty body ends with exactly one newline
+func normalizeBody(body string) string {
+	body = strings.TrimRight(body, "\n")
+	if body == "" {
+		return ""
+	}
+	return body + "\n"
+}
+
+// headingLevel returns the Markdown ATX heading level of a line, or 0
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
@@ -0,0 +1,100 @@
+package regions
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpsert(t *testing.T) {
+	f := func(name, content, region, body, expected string) {
+		t.Helper()
+
+		result, err := Upsert(content, region, body)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if result != expected {
+			t.Fatalf("%s: got %q, expected %q", name, result, expected)
+		}
+
+		// Rewriting with the same body must not change anything
+		again, err := Upsert(result, region, body)
+		if err != nil {
+			t.Fatalf("%s: unexpected error on rewrite: %v", name, err)
+		}
+		if again != result {
+			t.Fatalf("%s: rewrite is not idempotent: got %q, expected %q", name, again, result)
+		}
+	}
+
+	f("empty document", "", "tech", "- Go",
+		"<!-- specify:begin tech -->\n- Go\n<!-- specify:end tech -->\n")
+
+	f("append after manual content", "# Notes\nkeep me\n", "tech", "- Go",
+		"# Notes\nkeep me\n\n<!-- specify:begin tech -->\n- Go\n<!-- specify:end tech -->\n")
+
+	f("replace existing region",
+		"# Title\n<!-- specify:begin tech -->\n- Python\n<!-- specify:end tech -->\nmanual\n",
+		"tech", "- Go\n",
+		"# Title\n<!-- specify:begin tech -->\n- Go\n<!-- specify:end tech -->\nmanual\n")
+
+	f("region is last without newline",
+		"intro\n<!-- specify:begin tech -->\nold\n<!-- specify:end tech -->",
+		"tech", "new",
+		"intro\n<!-- specify:begin tech -->\nnew\n<!-- specify:end tech -->")
+
+	f("multiple regions keep order",
+		"<!-- specify:begin a -->\n1\n<!-- specify:end a -->\nx\n<!-- specify:begin b -->\n2\n<!-- specify:end b -->\n",
+		"a", "one",
+		"<!-- specify:begin a -->\none\n<!-- specify:end a -->\nx\n<!-- specify:begin b -->\n2\n<!-- specify:end b -->\n")
+}
+
+func TestParseErrors(t *testing.T) {
+	f := func(name, content string, expected error) {
+		t.Helper()
+
+		_, err := Parse(content)
+		if !errors.Is(err, expected) {
+			t.Fatalf("%s: got error %v, expected %v", name, err, expected)
+		}
+	}
+
+	f("nested", "<!-- specify:begin a -->\n<!-- specify:begin b -->\n<!-- specify:end b -->\n<!-- specify:end a -->\n",
+		ErrNestedRegion)
+	f("unclosed", "<!-- specify:begin a -->\nbody\n", ErrUnclosedRegion)
+	f("unmatched end", "body\n<!-- specify:end a -->\n", ErrUnmatchedEnd)
+	f("mismatched end", "<!-- specify:begin a -->\n<!-- specify:end b -->\n", ErrMismatchedEnd)
+	f("duplicate", "<!-- specify:begin a -->\n<!-- specify:end a -->\n<!-- specify:begin a -->\n<!-- specify:end a -->\n",
+		ErrDuplicateRegion)
+	f("invalid name", "<!-- specify:begin Bad! -->\n<!-- specify:end Bad! -->\n", ErrInvalidName)
+}
+
+func TestAdoptSection(t *testing.T) {
+	f := func(name, content, expected string) {
+		t.Helper()
+
+		doc, err := Parse(content)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		doc.AdoptSection("## Recent Changes", "changes")
+
+		if result := doc.String(); result != expected {
+			t.Fatalf("%s: got %q, expected %q", name, result, expected)
+		}
+	}
+
+	f("section in the middle",
+		"# T\n\n## Recent Changes\n- a\n\n## Other\ntext\n",
+		"# T\n\n<!-- specify:begin changes -->\n## Recent Changes\n- a\n<!-- specify:end changes -->\n\n## Other\ntext\n")
+
+	f("section is last in file",
+		"# T\n\n## Recent Changes\n- a\n- b\n",
+		"# T\n\n<!-- specify:begin changes -->\n## Recent Changes\n- a\n- b\n<!-- specify:end changes -->\n")
+
+	f("subsections stay inside",
+		"## Recent Changes\n### Details\n- a\n# Top\n",
+		"<!-- specify:begin changes -->\n## Recent Changes\n### Details\n- a\n<!-- specify:end changes -->\n# Top\n")
+
+	f("no section", "# T\n", "# T\n")
+}
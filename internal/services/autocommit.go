@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Actions recorded in automatic commit messages
+const (
+	commitActionCreate  = "create specification"
+	commitActionPlan    = "create implementation plan"
+	commitActionContext = "update agent context"
+)
+
+// SetAutoCommit overrides commit.auto for this service
+func (f *FeatureService) SetAutoCommit(enabled bool) {
+	f.autoCommit = enabled
+}
+
+// commitArtifacts commits the generated files at paths, and nothing else, when
+// auto-commit is enabled. The message follows the conventional commit format
+// scoped to the feature, e.g. "spec(004): create specification". It returns
+// the new commit, or an empty string when auto-commit is off or the files are
+// unchanged.
+func (f *FeatureService) commitArtifacts(git GitServiceInterface, featureDir, action string, paths ...string) (string, error) {
+	if !f.autoCommit || len(paths) == 0 {
+		return "", nil
+	}
+
+	author, err := f.config.GetCommitAuthor()
+	if err != nil {
+		return "", err
+	}
+
+	if err := git.Add(paths...); err != nil {
+		return "", fmt.Errorf("failed to stage spec artifacts: %w", err)
+	}
+
+	changed, err := f.artifactsChanged(git, paths)
+	if err != nil || !changed {
+		return "", err
+	}
+
+	hash, err := git.Commit(f.commitMessage(featureDir, action), author, paths...)
+	if err != nil {
+		return "", fmt.Errorf("failed to commit spec artifacts: %w", err)
+	}
+	return hash, nil
+}
+
+// artifactsChanged reports whether any of paths has staged changes
+func (f *FeatureService) artifactsChanged(git GitServiceInterface, paths []string) (bool, error) {
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return false, fmt.Errorf("failed to get repository root: %w", err)
+	}
+	relative, err := repoPaths(root, paths)
+	if err != nil {
+		return false, err
+	}
+
+	status, err := git.Status()
+	if err != nil {
+		return false, err
+	}
+	for _, file := range status {
+		if file.Staging != ' ' && !file.IsUntracked() && underPaths(file.Path, relative) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// commitMessage builds "spec(<scope>): <action>" plus the configured trailers
+func (f *FeatureService) commitMessage(featureDir, action string) string {
+	message := fmt.Sprintf("spec(%s): %s", f.commitScope(featureDir), action)
+	if len(f.config.Commit.Trailers) > 0 {
+		message += "\n\n" + strings.Join(f.config.Commit.Trailers, "\n")
+	}
+	return message
+}
+
+// commitScope identifies a feature in commit messages and pull request titles:
+// its number, or its ticket or directory name when the branch pattern has no
+// number
+func (f *FeatureService) commitScope(featureDir string) string {
+	name := filepath.Base(featureDir)
+	if parsed, ok := f.scheme.Parse(name); ok {
+		switch {
+		case parsed.HasNumber:
+			return f.scheme.FormatNumber(parsed.Number)
+		case parsed.Ticket != "":
+			return parsed.Ticket
+		}
+	}
+	return name
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestAutoCommit(t *testing.T) {
+	forEachGitBackend(t, func(t *testing.T, _ string, git GitServiceInterface) {
+		config := models.DefaultProjectConfig()
+		for key, value := range map[string]string{
+			"commit.auto":     "true",
+			"commit.author":   "Spec Bot <bot@example.com>",
+			"commit.trailers": "Refs: PROJ-1",
+			"templates.plan":  "plan-template.md",
+		} {
+			if err := config.Set(key, value); err != nil {
+				t.Fatalf("failed to set %s: %v", key, err)
+			}
+		}
+		commitTestFile(t, "plan-template.md", "# Plan\n")
+		commitTestFile(t, ".claude/settings.json", "{}\n")
+		feature := NewFeatureService(NewFilesystemService(), git, config)
+
+		f := func(name, hash, subject string, files ...string) {
+			t.Helper()
+
+			if hash == "" || hash != runGit(t, "rev-parse", "HEAD") {
+				t.Fatalf("%s: commit %q is not HEAD", name, hash)
+			}
+			message := runGit(t, "log", "-1", "--format=%an <%ae>%n%B")
+			if expected := "Spec Bot <bot@example.com>\n" + subject + "\n\nRefs: PROJ-1"; message != expected {
+				t.Fatalf("%s: commit message:\n%s\nexpected:\n%s", name, message, expected)
+			}
+			committed := strings.Fields(runGit(t, "show", "--name-only", "--format=", "HEAD"))
+			if !slices.Equal(committed, files) {
+				t.Fatalf("%s: committed %v, expected %v", name, committed, files)
+			}
+		}
+
+		created, err := feature.CreateFeature(FeatureCreateOptions{Description: "user login"})
+		if err != nil {
+			t.Fatalf("CreateFeature: %v", err)
+		}
+		f("create", created.Commit, "spec(001): create specification", "specs/001-user-login/spec.md")
+
+		// Unrelated changes, staged or not, stay out of the commit
+		writeTestFile(t, "README.md", "# staged\n")
+		runGit(t, "add", "README.md")
+		writeTestFile(t, "notes.txt", "untracked\n")
+
+		planned, err := feature.SetupPlan()
+		if err != nil {
+			t.Fatalf("SetupPlan: %v", err)
+		}
+		f("plan", planned.Commit, "spec(001): create implementation plan", "specs/001-user-login/plan.md")
+		if status := runGit(t, "status", "--porcelain"); status != "M  README.md\n?? notes.txt" {
+			t.Fatalf("unrelated changes were touched:\n%s", status)
+		}
+
+		// Nothing is committed when the artifacts are unchanged
+		if planned, err = feature.SetupPlan(); err != nil {
+			t.Fatalf("SetupPlan: %v", err)
+		}
+		if planned.Commit != "" {
+			t.Fatalf("SetupPlan() committed %q for an unchanged plan", planned.Commit)
+		}
+
+		feature.SetAutoCommit(false)
+		writeTestFile(t, "plan-template.md", "# Plan v2\n")
+		if planned, err = feature.SetupPlan(); err != nil {
+			t.Fatalf("SetupPlan: %v", err)
+		}
+		if planned.Commit != "" {
+			t.Fatalf("SetupPlan() committed %q with auto-commit disabled", planned.Commit)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// ConfigService loads, layers and saves project and user configuration
+type ConfigService struct {
+	filesystem FilesystemServiceInterface
+	getenv     func(string) string
+}
+
+// NewConfigService creates a new config service instance
+func NewConfigService(filesystem FilesystemServiceInterface) *ConfigService {
+	return &ConfigService{filesystem: filesystem, getenv: os.Getenv}
+}
+
+// Load returns the effective configuration for startDir. Values are layered
+// as defaults < user config < project config < SPECIFY_* environment
+// variables; command-line flags are applied on top by the caller.
+func (c *ConfigService) Load(startDir string) (*models.ProjectConfig, error) {
+	cfg, err := c.LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := c.LoadProjectConfig(startDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Overlay(project); err != nil {
+		return nil, err
+	}
+	cfg.Root = project.Root
+
+	for _, key := range models.ListConfigKeys() {
+		name := models.ConfigEnvVar(key)
+		if value := c.getenv(name); value != "" {
+			if err := cfg.Set(key, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// UserConfigPath returns $XDG_CONFIG_HOME/specify/config.yaml, defaulting
+// XDG_CONFIG_HOME to ~/.config
+func (c *ConfigService) UserConfigPath() (string, error) {
+	configHome := c.getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "specify", models.ConfigFileName), nil
+}
+
+// LoadUserConfig returns the user configuration, or the defaults when the file
+// does not exist
+func (c *ConfigService) LoadUserConfig() (*models.ProjectConfig, error) {
+	path, err := c.UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if exists, _ := c.filesystem.FileExists(path); !exists {
+		return models.DefaultProjectConfig(), nil
+	}
+
+	cfg, err := c.readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SaveUserConfig writes the user configuration file
+func (c *ConfigService) SaveUserConfig(cfg *models.ProjectConfig) (string, error) {
+	path, err := c.UserConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return path, c.writeConfig(path, cfg)
+}
+
+// FindProjectConfig walks up from startDir looking for .specify/config.yaml and
+// returns its path, or an empty string when no project config exists
+func (c *ConfigService) FindProjectConfig(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", startDir, err)
+	}
+
+	for {
+		path := filepath.Join(dir, models.ConfigDirName, models.ConfigFileName)
+		if exists, _ := c.filesystem.FileExists(path); exists {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig returns the project configuration file found from startDir
+// alone, or the defaults when there is none. Unknown keys and invalid values
+// are errors.
+func (c *ConfigService) LoadProjectConfig(startDir string) (*models.ProjectConfig, error) {
+	path, err := c.FindProjectConfig(startDir)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return models.DefaultProjectConfig(), nil
+	}
+
+	return c.ReadProjectConfig(path)
+}
+
+// ReadProjectConfig parses and validates a project configuration file
+func (c *ConfigService) ReadProjectConfig(path string) (*models.ProjectConfig, error) {
+	cfg, err := c.readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Root = filepath.Dir(filepath.Dir(path))
+	return cfg, nil
+}
+
+// readConfig parses and validates a configuration file
+func (c *ConfigService) readConfig(path string) (*models.ProjectConfig, error) {
+	content, err := c.filesystem.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := models.DefaultProjectConfig()
+
+	decoder := yaml.NewDecoder(bytes.NewBufferString(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s: %v", models.ErrConfigInvalid, path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// SaveProjectConfig writes the configuration to .specify/config.yaml under root
+func (c *ConfigService) SaveProjectConfig(root string, cfg *models.ProjectConfig) (string, error) {
+	path := filepath.Join(root, models.ConfigDirName, models.ConfigFileName)
+	return path, c.writeConfig(path, cfg)
+}
+
+// writeConfig validates and writes a configuration file
+func (c *ConfigService) writeConfig(path string, cfg *models.ProjectConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	var data bytes.Buffer
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := c.filesystem.CreateDirectory(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := c.filesystem.WriteFile(path, data.String()); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestConfigServiceLoad(t *testing.T) {
+	root := t.TempDir()
+	userHome := filepath.Join(root, "xdg")
+	project := filepath.Join(root, "repo")
+	nested := filepath.Join(project, "a", "b")
+
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(filepath.Join(userHome, "specify", "config.yaml"),
+		"ai: gemini\nhttp:\n  timeout: 10s\ngithub:\n  owner: acme\n")
+	writeFile(filepath.Join(project, ".specify", "config.yaml"),
+		"ai: codex\nnumbering:\n  width: 4\n")
+
+	env := map[string]string{
+		"XDG_CONFIG_HOME":      userHome,
+		"SPECIFY_HTTP_TIMEOUT": "45s",
+	}
+	service := &ConfigService{
+		filesystem: NewFilesystemService(),
+		getenv:     func(key string) string { return env[key] },
+	}
+
+	config, err := service.Load(nested)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := func(key, expected string) {
+		t.Helper()
+		value, err := config.Get(key)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", key, err)
+		}
+		if value != expected {
+			t.Fatalf("%s: got %q, expected %q", key, value, expected)
+		}
+	}
+
+	f("ai", "codex")             // project overrides user
+	f("github.owner", "acme")    // user overrides default
+	f("github.repo", "spec-kit") // default
+	f("http.timeout", "45s")     // env overrides user
+	f("numbering.width", "4")
+
+	if config.Root != project {
+		t.Fatalf("got root %q, expected %q", config.Root, project)
+	}
+
+	env["SPECIFY_NUMBERING_WIDTH"] = "many"
+	if _, err := service.Load(nested); err == nil {
+		t.Fatal("expected error for invalid environment value")
+	}
+
+	writeFile(filepath.Join(project, ".specify", "config.yaml"), "colour: blue\n")
+	if _, err := service.LoadProjectConfig(nested); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+}
+
+func TestConfigServiceLoadUserOnlyKeys(t *testing.T) {
+	root := t.TempDir()
+	key := "RWQBAgMEBQYHCAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	writeTestFile(t, filepath.Join(root, "xdg", "specify", "config.yaml"), "templates:\n  trusted_keys: ["+key+"]\n")
+
+	env := map[string]string{"XDG_CONFIG_HOME": filepath.Join(root, "xdg")}
+	service := &ConfigService{
+		filesystem: NewFilesystemService(),
+		getenv:     func(key string) string { return env[key] },
+	}
+
+	// The user config may trust a key
+	config, err := service.Load(root)
+	if err != nil || len(config.Templates.TrustedKeys) != 1 {
+		t.Fatalf("got %v, %v", config, err)
+	}
+
+	// A repository may not trust its own
+	writeTestFile(t, filepath.Join(root, "repo", ".specify", "config.yaml"), "templates:\n  trusted_keys: ["+key+"]\n")
+	if _, err := service.Load(filepath.Join(root, "repo")); !errors.Is(err, models.ErrConfigInvalid) {
+		t.Fatalf("got %v, want ErrConfigInvalid", err)
+	}
+
+	// Nor send the GitHub token or any other traffic to its own servers
+	for _, content := range []string{
+		"github:\n  api_url: https://evil.example.com/api/v3\n",
+		"http:\n  proxy: http://evil.example.com:3128\n",
+		"http:\n  ca_bundle: evil.pem\n",
+		"http:\n  mirror: https://evil.example.com\n",
+		"cache_dir: /home\n",
+	} {
+		writeTestFile(t, filepath.Join(root, "repo", ".specify", "config.yaml"), content)
+		if _, err := service.Load(filepath.Join(root, "repo")); !errors.Is(err, models.ErrConfigInvalid) {
+			t.Fatalf("%q: got %v, want ErrConfigInvalid", content, err)
+		}
+	}
+}
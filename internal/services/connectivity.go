@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// ConnectivityTimeout bounds the whole connectivity probe
+const ConnectivityTimeout = 3 * time.Second
+
+// ConnectivityProber checks that the template source is reachable
+type ConnectivityProber struct {
+	client   *http.Client
+	targets  []string
+	deadline time.Duration
+}
+
+// NewConnectivityProber creates a prober for targets using client, which
+// should come from NewHTTPClient so probes take the same route as downloads
+func NewConnectivityProber(client *http.Client, targets []string) *ConnectivityProber {
+	return &ConnectivityProber{client: client, targets: targets, deadline: ConnectivityTimeout}
+}
+
+// ConnectivityTargets returns the endpoints templates are fetched from: the
+// API server releases are listed on and the host, or mirror, assets are
+// downloaded from
+func ConnectivityTargets(config *models.ProjectConfig) []string {
+	assets := config.GetMirror()
+	if assets == "" {
+		assets = "https://" + config.GetGitHubHost()
+	}
+	return []string{config.GetGitHubAPIURL(), assets}
+}
+
+// Probe checks every target concurrently within the overall deadline
+func (p *ConnectivityProber) Probe() *models.ConnectivityReport {
+	ctx, cancel := context.WithTimeout(context.Background(), p.deadline)
+	defer cancel()
+
+	report := &models.ConnectivityReport{Checks: make([]models.ConnectivityCheck, len(p.targets))}
+	var wg sync.WaitGroup
+	for i, target := range p.targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = p.check(ctx, target)
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// check probes one target with a HEAD request. Any answer below 500 means
+// the server is reachable, except a 407 from the proxy.
+func (p *ConnectivityProber) check(ctx context.Context, target string) (check models.ConnectivityCheck) {
+	check.URL = target
+	start := time.Now()
+	defer func() { check.Duration = time.Since(start) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		check.Failure, check.Error = models.ConnectivityConnection, err.Error()
+		return check
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		check.Failure, check.Error = connectivityFailure(err), err.Error()
+		return check
+	}
+	resp.Body.Close()
+
+	check.Status = resp.StatusCode
+	switch {
+	case resp.StatusCode == http.StatusProxyAuthRequired:
+		check.Failure, check.Error = models.ConnectivityProxy, "proxy authentication required"
+	case resp.StatusCode >= 500:
+		check.Failure, check.Error = models.ConnectivityHTTP, resp.Status
+	default:
+		check.OK = true
+	}
+	return check
+}
+
+// connectivityFailure classifies a failed request
+func connectivityFailure(err error) string {
+	var (
+		dnsErr         *net.DNSError
+		opErr          *net.OpError
+		unknownAuthErr x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		certErr        x509.CertificateInvalidError
+		verifyErr      *tls.CertificateVerificationError
+		recordErr      tls.RecordHeaderError
+		netErr         net.Error
+	)
+	switch {
+	case errors.As(err, &opErr) && opErr.Op == "proxyconnect":
+		return models.ConnectivityProxy
+	case errors.As(err, &dnsErr):
+		return models.ConnectivityDNS
+	case errors.As(err, &verifyErr), errors.As(err, &unknownAuthErr), errors.As(err, &hostnameErr),
+		errors.As(err, &certErr), errors.As(err, &recordErr):
+		return models.ConnectivityTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return models.ConnectivityTimeout
+	default:
+		return models.ConnectivityConnection
+	}
+}
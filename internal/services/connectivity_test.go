@@ -0,0 +1,86 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestConnectivityProber(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/slow":
+			<-release
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	// A port nothing listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := listener.Addr().String()
+	listener.Close()
+
+	f := func(client *http.Client, target, want string) {
+		t.Helper()
+		prober := NewConnectivityProber(client, []string{target})
+		prober.deadline = 200 * time.Millisecond
+		check := prober.Probe().Checks[0]
+		if check.OK != (want == "") || check.Failure != want || check.Duration <= 0 {
+			t.Errorf("%s: got %+v, want failure %q", target, check, want)
+		}
+	}
+
+	f(server.Client(), server.URL+"/missing", "")
+	f(server.Client(), server.URL+"/down", models.ConnectivityHTTP)
+	f(server.Client(), server.URL+"/slow", models.ConnectivityTimeout)
+	f(&http.Client{}, tlsServer.URL, models.ConnectivityTLS)
+	f(&http.Client{}, "http://"+closed, models.ConnectivityConnection)
+
+	proxy, _ := url.Parse("http://" + closed)
+	f(&http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxy)}}, "http://example.com", models.ConnectivityProxy)
+
+	// Targets are probed concurrently within one deadline
+	prober := NewConnectivityProber(server.Client(), []string{server.URL + "/slow", server.URL + "/slow", server.URL + "/missing"})
+	prober.deadline = 200 * time.Millisecond
+	start := time.Now()
+	report := prober.Probe()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("probe took %s", elapsed)
+	}
+	if report.OK() || !report.Checks[2].OK {
+		t.Errorf("got %+v", report)
+	}
+}
+
+func TestConnectivityTargets(t *testing.T) {
+	f := func(config *models.ProjectConfig, want ...string) {
+		t.Helper()
+		got := ConnectivityTargets(config)
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+
+	f(&models.ProjectConfig{}, "https://api.github.com", "https://github.com")
+	f(&models.ProjectConfig{GitHub: models.GitHubConfig{APIURL: "https://github.example.com/api/v3"}},
+		"https://github.example.com/api/v3", "https://github.example.com")
+	f(&models.ProjectConfig{HTTP: models.HTTPConfig{Mirror: "https://artifacts.example.com/github/"}},
+		"https://api.github.com", "https://artifacts.example.com/github")
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// Retry policy for asset downloads
+const (
+	downloadMaxAttempts = 5
+	downloadRetryDelay  = time.Second // doubled after each failed attempt
+)
+
+// checksumAssetNames are release assets listing SHA256 checksums in sha256sum
+// format ("<hex>  <file name>"). An "<asset>.sha256" asset is used as well.
+var checksumAssetNames = []string{"checksums.txt", "SHA256SUMS", "sha256sums.txt"}
+
+// DownloadReleaseAsset downloads an asset of release to path and verifies it
+// against the SHA256 checksum published with the release. Failed attempts are
+// retried with exponential backoff and resume where they stopped with an HTTP
+// Range request; an interrupted download also resumes on the next run. It
+// reports whether a checksum was published; a mismatch fails with
+// ErrChecksumMismatch and nothing is written to path.
+func (g *GitHubService) DownloadReleaseAsset(release *GitHubRelease, asset *GitHubAsset, path string, progress func(downloaded, total int64)) (bool, error) {
+	if release == nil || asset == nil {
+		return false, fmt.Errorf("%w: release and asset cannot be nil", models.ErrTemplateDownloadFailed)
+	}
+
+	expected, err := g.assetChecksum(release, asset)
+	if err != nil {
+		return false, err
+	}
+
+	partial := path + ".part"
+	if g.downloadDir != "" {
+		partial = filepath.Join(g.downloadDir, fmt.Sprintf("%d-%s.part", asset.ID, asset.Name))
+	}
+	if err := g.downloadResumable(asset, partial, progress); err != nil {
+		return false, err
+	}
+
+	if expected != "" {
+		actual, err := fileSHA256(partial)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", models.ErrTemplateDownloadFailed, err)
+		}
+		if actual != expected {
+			os.Remove(partial) // never resume a corrupt download
+			return false, fmt.Errorf("%w: %s has SHA256 %s, the release publishes %s",
+				models.ErrChecksumMismatch, asset.Name, actual, expected)
+		}
+	}
+
+	if err := moveFile(partial, path); err != nil {
+		return false, fmt.Errorf("%w: %v", models.ErrTemplateDownloadFailed, err)
+	}
+	return expected != "", nil
+}
+
+// OpenReleaseZIP opens a ZIP release asset without downloading it: the
+// archive is read with HTTP Range requests, so only its central directory and
+// the entries that are read are transferred. It fails when the server does
+// not honor Range requests.
+func (g *GitHubService) OpenReleaseZIP(asset *GitHubAsset) (*zip.Reader, error) {
+	if asset == nil || asset.Size <= 0 {
+		return nil, fmt.Errorf("%w: asset size is unknown", models.ErrTemplateDownloadFailed)
+	}
+	reader, err := zip.NewReader(&assetRangeReader{github: g, asset: asset}, asset.Size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", models.ErrTemplateDownloadFailed, asset.Name, err)
+	}
+	return reader, nil
+}
+
+// assetRangeReader reads byte ranges of a release asset over HTTP
+type assetRangeReader struct {
+	github *GitHubService
+	asset  *GitHubAsset
+}
+
+func (r *assetRangeReader) ReadAt(p []byte, offset int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if offset >= r.asset.Size {
+		return 0, io.EOF
+	}
+	end := min(offset+int64(len(p)), r.asset.Size)
+
+	req, err := r.github.newRequest("GET", r.github.mirrorURL(r.asset.BrowserDownloadURL), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, end-1))
+
+	resp, err := r.github.downloadClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("range request failed with status %d", resp.StatusCode)
+	}
+
+	n, err := io.ReadFull(resp.Body, p[:end-offset])
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// assetChecksum returns the SHA256 the release publishes for asset, or an
+// empty string when it publishes no checksums
+func (g *GitHubService) assetChecksum(release *GitHubRelease, asset *GitHubAsset) (string, error) {
+	for _, candidate := range release.Assets {
+		if candidate.Name != asset.Name+".sha256" && !slices.Contains(checksumAssetNames, candidate.Name) {
+			continue
+		}
+
+		var checksums bytes.Buffer
+		if err := g.DownloadAsset(&candidate, &checksums); err != nil {
+			return "", fmt.Errorf("failed to download %s: %w", candidate.Name, err)
+		}
+		if sum := parseChecksum(checksums.String(), asset.Name); sum != "" {
+			return sum, nil
+		}
+		return "", fmt.Errorf("%w: %s has no SHA256 for %s", models.ErrChecksumMismatch, candidate.Name, asset.Name)
+	}
+	return "", nil
+}
+
+// parseChecksum finds the SHA256 of name in sha256sum output. A line with
+// only a checksum, as in "<asset>.sha256" files, matches any name.
+func parseChecksum(checksums, name string) string {
+	for _, line := range strings.Split(checksums, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 1 && len(fields[0]) == 64,
+			len(fields) >= 2 && strings.TrimPrefix(fields[1], "*") == name:
+			if sum := strings.ToLower(fields[0]); len(sum) == 64 && isHex(sum) {
+				return sum
+			}
+		}
+	}
+	return ""
+}
+
+func isHex(value string) bool {
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+// downloadResumable downloads asset into partial, retrying failed attempts
+func (g *GitHubService) downloadResumable(asset *GitHubAsset, partial string, progress func(downloaded, total int64)) error {
+	if err := os.MkdirAll(filepath.Dir(partial), 0o755); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrTemplateDownloadFailed, err)
+	}
+	// Without a known size a leftover partial file cannot be trusted
+	if asset.Size <= 0 {
+		os.Remove(partial)
+	}
+
+	delay := downloadRetryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := g.downloadAttempt(asset, partial, progress)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == downloadMaxAttempts {
+			return fmt.Errorf("%w: %s: %w", models.ErrTemplateDownloadFailed, asset.Name, err)
+		}
+		g.sleep(delay)
+		delay *= 2
+	}
+}
+
+// downloadAttempt appends the rest of asset to partial. It reports whether a
+// failure is worth retrying.
+func (g *GitHubService) downloadAttempt(asset *GitHubAsset, partial string, progress func(downloaded, total int64)) (bool, error) {
+	file, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+	if asset.Size > 0 && offset == asset.Size {
+		return false, nil
+	}
+	if asset.Size > 0 && offset > asset.Size {
+		if offset, err = restartFile(file); err != nil {
+			return false, err
+		}
+	}
+
+	req, err := g.newRequest("GET", g.mirrorURL(asset.BrowserDownloadURL), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := g.downloadClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+	case resp.StatusCode == http.StatusOK:
+		// The server ignored the range; start over
+		if offset, err = restartFile(file); err != nil {
+			return false, err
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		if _, err := restartFile(file); err != nil {
+			return false, err
+		}
+		return true, fmt.Errorf("server cannot resume at byte %d", offset)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	writer := &progressWriter{writer: file, total: asset.Size, downloaded: offset, callback: progress}
+	if _, err := io.Copy(writer, resp.Body); err != nil {
+		return true, err // what was written is kept and resumed
+	}
+	if asset.Size > 0 && writer.downloaded != asset.Size {
+		return true, fmt.Errorf("download stopped after %d of %d bytes", writer.downloaded, asset.Size)
+	}
+	return false, nil
+}
+
+// restartFile empties a partial download
+func restartFile(file *os.File) (int64, error) {
+	if err := file.Truncate(0); err != nil {
+		return 0, err
+	}
+	return file.Seek(0, io.SeekStart)
+}
+
+// fileSHA256 returns the hex SHA256 of a file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// moveFile renames source to target, copying when they are on different
+// filesystems
+func moveFile(source, target string) error {
+	if err := os.Rename(source, target); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(source)
+}
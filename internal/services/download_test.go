@@ -0,0 +1,105 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestDownloadReleaseAsset(t *testing.T) {
+	content := strings.Repeat("template bytes ", 100)
+	sum := sha256.Sum256([]byte(content))
+	checksum := hex.EncodeToString(sum[:])
+
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/template.zip":
+			ranges = append(ranges, r.Header.Get("Range"))
+			if r.Header.Get("Range") == "" {
+				// Cut the first response short
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				w.Write([]byte(content[:500]))
+				return
+			}
+			var offset int
+			fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &offset)
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(content)-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte(content[offset:]))
+		case "/checksums.txt":
+			fmt.Fprintf(w, "%s  template.zip\n%s  other.zip\n", checksum, strings.Repeat("0", 64))
+		case "/bad-checksums.txt":
+			fmt.Fprintf(w, "%s  template.zip\n", strings.Repeat("0", 64))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	f := func(checksums string) (bool, string, error) {
+		t.Helper()
+		ranges = nil
+
+		github := NewGitHubServiceWithClient(server.Client(), "acme", "widgets")
+		github.sleep = func(time.Duration) {}
+		github.downloadDir = t.TempDir()
+
+		asset := GitHubAsset{ID: 7, Name: "template.zip", Size: int64(len(content)), BrowserDownloadURL: server.URL + "/template.zip"}
+		release := &GitHubRelease{TagName: "v1.0.0", Assets: []GitHubAsset{asset}}
+		if checksums != "" {
+			release.Assets = append(release.Assets, GitHubAsset{Name: "checksums.txt", BrowserDownloadURL: server.URL + checksums})
+		}
+
+		path := filepath.Join(t.TempDir(), "template.zip")
+		verified, err := github.DownloadReleaseAsset(release, &release.Assets[0], path, nil)
+		data, _ := os.ReadFile(path)
+		return verified, string(data), err
+	}
+
+	// The truncated first attempt resumes with a Range request and is verified
+	verified, data, err := f("/checksums.txt")
+	if err != nil || !verified || data != content {
+		t.Fatalf("got verified=%v, %d bytes, err=%v", verified, len(data), err)
+	}
+	if len(ranges) != 2 || ranges[0] != "" || ranges[1] != "bytes=500-" {
+		t.Errorf("got ranges %q", ranges)
+	}
+
+	// Without published checksums the download succeeds unverified
+	if verified, data, err := f(""); err != nil || verified || data != content {
+		t.Errorf("got verified=%v, %d bytes, err=%v", verified, len(data), err)
+	}
+
+	// A mismatch fails and writes nothing
+	if _, data, err := f("/bad-checksums.txt"); !errors.Is(err, models.ErrChecksumMismatch) || data != "" {
+		t.Errorf("got %d bytes, err=%v", len(data), err)
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	f := func(checksums, want string) {
+		t.Helper()
+		if got := parseChecksum(checksums, "template.zip"); got != want {
+			t.Errorf("parseChecksum(%q) = %q, want %q", checksums, got, want)
+		}
+	}
+
+	f(sum+"  template.zip\n", sum)
+	f(sum+" *template.zip\n", sum)
+	f(strings.ToUpper(sum)+"\n", sum)
+	f(sum+"  other.zip\n", "")
+	f("not-a-checksum  template.zip\n", "")
+}
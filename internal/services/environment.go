@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,15 +17,31 @@ import (
 // EnvironmentService handles environment detection and validation
 type EnvironmentService struct {
 	filesystem *FilesystemService
+	git        GitServiceInterface
+	prober     *ConnectivityProber
+	skipProbe  string // why connectivity is not probed; empty probes
 }
 
 // NewEnvironmentService creates a new environment service instance
-func NewEnvironmentService(filesystem *FilesystemService) *EnvironmentService {
+func NewEnvironmentService(filesystem *FilesystemService, git GitServiceInterface) *EnvironmentService {
 	return &EnvironmentService{
 		filesystem: filesystem,
+		git:        git,
+		prober:     NewConnectivityProber(&http.Client{}, ConnectivityTargets(models.DefaultProjectConfig())),
 	}
 }
 
+// SetConnectivityProber sets the prober that checks the template source
+func (e *EnvironmentService) SetConnectivityProber(prober *ConnectivityProber) {
+	e.prober = prober
+}
+
+// SkipConnectivity skips the connectivity probe, e.g. because templates come
+// from a local source; reason is reported instead of the checks
+func (e *EnvironmentService) SkipConnectivity(reason string) {
+	e.skipProbe = reason
+}
+
 // DetectEnvironment detects and returns the current environment information
 func (e *EnvironmentService) DetectEnvironment() (*models.Environment, error) {
 	workingDir, err := e.filesystem.GetWorkingDirectory()
@@ -36,8 +53,9 @@ func (e *EnvironmentService) DetectEnvironment() (*models.Environment, error) {
 
 	// Detect platform (already set by NewEnvironment using runtime.GOOS)
 
-	// Check internet connectivity
-	env.HasInternet = e.checkInternetConnectivity()
+	// Check that the template source is reachable
+	env.Connectivity = e.checkConnectivity()
+	env.HasInternet = env.Connectivity.Skipped == "" && env.Connectivity.OK()
 
 	// Check required tools
 	e.checkRequiredTools(env)
@@ -56,30 +74,12 @@ func (e *EnvironmentService) DetectEnvironment() (*models.Environment, error) {
 	return env, nil
 }
 
-// checkInternetConnectivity tests internet connectivity
-func (e *EnvironmentService) checkInternetConnectivity() bool {
-	// Test multiple endpoints for reliability
-	testURLs := []string{
-		"https://api.github.com",
-		"https://www.google.com",
-		"https://www.cloudflare.com",
-	}
-
-	for _, url := range testURLs {
-		if e.testConnectivity(url) {
-			return true
-		}
+// checkConnectivity probes the template source unless it was skipped
+func (e *EnvironmentService) checkConnectivity() *models.ConnectivityReport {
+	if e.skipProbe != "" {
+		return &models.ConnectivityReport{Skipped: e.skipProbe}
 	}
-
-	return false
-}
-
-// testConnectivity tests connectivity to a specific URL
-func (e *EnvironmentService) testConnectivity(url string) bool {
-	// Use curl if available, otherwise skip
-	cmd := exec.Command("curl", "-s", "--max-time", "5", "--head", url)
-	err := cmd.Run()
-	return err == nil
+	return e.prober.Probe()
 }
 
 // checkRequiredTools checks for required development tools
@@ -153,12 +153,11 @@ func (e *EnvironmentService) checkGitConfiguration(env *models.Environment) {
 
 // getGitConfig gets a git configuration value
 func (e *EnvironmentService) getGitConfig(key string) string {
-	cmd := exec.Command("git", "config", "--global", "--get", key)
-	output, err := cmd.Output()
+	value, err := e.git.GetConfig(key)
 	if err != nil {
 		return ""
 	}
-	return strings.TrimSpace(string(output))
+	return value
 }
 
 // IsInGitRepository checks if the current directory is inside a git repository
@@ -171,9 +170,7 @@ func (e *EnvironmentService) IsInGitRepository(path string) bool {
 		}
 	}
 
-	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
-	cmd.Dir = path
-	err := cmd.Run()
+	_, err := e.git.At(path).GetRepoRoot()
 	return err == nil
 }
 
@@ -183,29 +180,20 @@ func (e *EnvironmentService) InitializeGitRepository(path string) error {
 		return fmt.Errorf("path cannot be empty")
 	}
 
-	// Check if git is available
-	if _, err := exec.LookPath("git"); err != nil {
-		return fmt.Errorf("%w: git is not installed or not found in PATH (install from https://git-scm.com/downloads)", models.ErrToolNotFound)
-	}
+	repo := e.git.At(path)
 
 	// Initialize repository
-	cmd := exec.Command("git", "init")
-	cmd.Dir = path
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to initialize git repository: %w", err)
+	if err := repo.Init(); err != nil {
+		return err
 	}
 
 	// Add all files
-	cmd = exec.Command("git", "add", ".")
-	cmd.Dir = path
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add files to git: %w", err)
+	if err := repo.Add("."); err != nil {
+		return err
 	}
 
 	// Create initial commit
-	cmd = exec.Command("git", "commit", "-m", "Initial commit from Specify template")
-	cmd.Dir = path
-	if err := cmd.Run(); err != nil {
+	if _, err := repo.Commit("Initial commit from Specify template", nil); err != nil {
 		return fmt.Errorf("failed to create initial commit: %w", err)
 	}
 
@@ -277,8 +265,8 @@ func (e *EnvironmentService) ValidateProjectPath(path string, isHere bool) error
 func (e *EnvironmentService) GetRecommendations(env *models.Environment) []string {
 	var recommendations []string
 
-	// Check internet connectivity
-	if !env.HasInternet {
+	// Check the template source
+	if !env.TemplateSourceAvailable() {
 		recommendations = append(recommendations,
 			"Internet connection is required to download templates")
 	}
@@ -306,7 +294,7 @@ func (e *EnvironmentService) GetRecommendations(env *models.Environment) []strin
 func (e *EnvironmentService) WaitForConnectivity(timeout time.Duration) error {
 	start := time.Now()
 	for time.Since(start) < timeout {
-		if e.checkInternetConnectivity() {
+		if e.checkConnectivity().OK() {
 			return nil
 		}
 		time.Sleep(5 * time.Second)
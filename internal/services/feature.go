@@ -4,23 +4,17 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/euforicio/spec-kit/internal/models"
+	"github.com/euforicio/spec-kit/internal/naming"
+	"github.com/euforicio/spec-kit/internal/regions"
 )
 
 // Compiled regexes for performance
 var (
-	langRegex          = regexp.MustCompile(`\*\*Language/Version\*\*: (.+)`)
-	depRegex           = regexp.MustCompile(`\*\*Primary Dependencies\*\*: (.+)`)
-	testRegex          = regexp.MustCompile(`\*\*Testing\*\*: (.+)`)
-	storageRegex       = regexp.MustCompile(`\*\*Storage\*\*: (.+)`)
-	projectTypeRegex   = regexp.MustCompile(`\*\*Project Type\*\*: (.+)`)
-	activeTechtRegex   = regexp.MustCompile(`(## Active Technologies\n)(.*?)(\n\n)`)
-	recentChangesRegex = regexp.MustCompile(`(## Recent Changes\n)(.*?)(\n\n)`)
-	updateDateRegex    = regexp.MustCompile(`Last updated: \d{4}-\d{2}-\d{2}`)
+	updateDateRegex = regexp.MustCompile(`Last updated: \d{4}-\d{2}-\d{2}`)
 )
 
 // Language commands map for better maintainability
@@ -35,63 +29,189 @@ var languageCommands = map[string]string{
 type FeatureService struct {
 	filesystem FilesystemServiceInterface
 	git        GitServiceInterface
+	config     *models.ProjectConfig
+	scheme     *naming.Scheme
+	selector   string // feature chosen with --feature; empty uses the current branch
+	autoCommit bool   // commit generated spec artifacts
+}
+
+// FeatureCreateOptions contains options for creating a feature
+type FeatureCreateOptions struct {
+	Description string
+	Ticket      string        // issue key for patterns using {ticket}, e.g. PROJ-1234
+	Reserve     bool          // push a ref reserving the feature number on the remote
+	Worktree    bool          // create the branch in a new worktree instead of switching to it
+	From        string        // ref to branch from; empty uses the default branch
+	Stash       bool          // stash uncommitted changes instead of refusing to switch branches
+	Issue       *models.Issue // issue to seed the spec from; its title is the default description
+}
+
+// FeatureRenumberOptions contains options for renumbering the current feature
+type FeatureRenumberOptions struct {
+	Number  int  // new number; zero picks the next free number if the current one collides
+	Reserve bool // push a ref reserving the new number on the remote
 }
 
 type FeatureServiceInterface interface {
-	CreateFeature(description string) (*models.FeatureCreateResult, error)
+	CreateFeature(opts FeatureCreateOptions) (*models.FeatureCreateResult, error)
 	SetupPlan() (*models.FeaturePlanResult, error)
 	CheckPrerequisites() (*models.FeatureCheckResult, error)
 	ValidateAgentType(agentType string) error
-	UpdateContext(agentType string) (*models.FeatureContextResult, error)
+	UpdateContext(agentType string, historySize int) (*models.FeatureContextResult, error)
 	GetPaths() (*models.FeaturePathsResult, error)
+	RenumberFeature(opts FeatureRenumberOptions) (*models.FeatureRenumberResult, error)
+	ListWorktrees() ([]models.FeatureWorktree, error)
+	RemoveWorktree(selector string, opts FeatureWorktreeRemoveOptions) (*models.FeatureWorktree, error)
+	RenameFeature(description string) (*models.FeatureRenameResult, error)
+	ArchiveFeature(opts FeatureArchiveOptions) (*models.FeatureArchiveResult, error)
+	DeleteFeature(opts FeatureDeleteOptions) (*models.FeatureDeleteResult, error)
+	LintFeature() (*models.FeatureLintResult, error)
+	ComposePullRequest(opts PullRequestOptions) (*models.PullRequest, error)
+	ExportTasks(tracker IssueTracker, repo models.GitHubRepository) (*models.TaskExportResult, error)
+	ImportTasks(tracker IssueTracker, repo models.GitHubRepository) (*models.TaskImportResult, error)
 }
 
-func NewFeatureService(filesystem FilesystemServiceInterface, git GitServiceInterface) *FeatureService {
+// NewFeatureService creates a feature service; a nil config uses the defaults.
+// Configs are validated when loaded, so an invalid branch scheme falls back to
+// the default one.
+func NewFeatureService(filesystem FilesystemServiceInterface, git GitServiceInterface, config *models.ProjectConfig) *FeatureService {
+	if config == nil {
+		config = models.DefaultProjectConfig()
+	}
+
+	scheme, err := config.BranchScheme()
+	if err != nil {
+		scheme, _ = naming.New(naming.Options{})
+	}
+
 	return &FeatureService{
 		filesystem: filesystem,
 		git:        git,
+		config:     config,
+		scheme:     scheme,
+		autoCommit: config.AutoCommit(),
 	}
 }
 
-func (f *FeatureService) CreateFeature(description string) (*models.FeatureCreateResult, error) {
+func (f *FeatureService) CreateFeature(opts FeatureCreateOptions) (result *models.FeatureCreateResult, err error) {
 	repoRoot, err := f.git.GetRepoRoot()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	specsDir := filepath.Join(repoRoot, "specs")
+	specsDir := f.specsDir(repoRoot)
 
-	// Create specs directory if it doesn't exist
-	if err := f.filesystem.CreateDirectory(specsDir); err != nil {
-		return nil, fmt.Errorf("failed to create specs directory: %w", err)
+	baseRef, baseWarning, err := f.baseRef(opts.From)
+	if err != nil {
+		return nil, err
 	}
 
-	// Find highest numbered feature directory
-	highest, err := f.getHighestFeatureNumber(specsDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get highest feature number: %w", err)
+	// Switching branches would carry uncommitted changes along; a worktree
+	// leaves the current checkout alone
+	dirty := false
+	if !opts.Worktree {
+		if dirty, err = f.git.IsDirty(); err != nil {
+			return nil, err
+		}
+		if dirty && !opts.Stash {
+			return nil, fmt.Errorf("%w: commit them, rerun with --stash, or use --worktree", models.ErrWorkingTreeDirty)
+		}
+	}
+
+	description, ticket := opts.Description, opts.Ticket
+	if opts.Issue != nil {
+		if strings.TrimSpace(description) == "" {
+			description = opts.Issue.Title
+		}
+		if ticket == "" && f.scheme.HasPlaceholder(naming.PlaceholderTicket) {
+			ticket = issueTicket(opts.Issue)
+		}
+	}
+
+	values := naming.Values{
+		Ticket: ticket,
+		Slug:   f.scheme.Slugify(description),
+		Date:   time.Now(),
 	}
 
-	// Generate next feature number
-	next := highest + 1
-	featureNum := fmt.Sprintf("%03d", next)
+	// Number past every feature known locally, on remotes and on the default branch
+	if f.scheme.HasPlaceholder(naming.PlaceholderNum) {
+		if values.Number, err = f.nextFeatureNumber(repoRoot, specsDir, "", opts.Reserve); err != nil {
+			return nil, fmt.Errorf("failed to get next feature number: %w", err)
+		}
+	}
 
 	// Create branch name from description
-	branchName := f.createBranchName(description, featureNum)
+	branchName, err := f.createBranchName(values)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reserve the number only once the branch is known to be free, and release
+	// the reservation when the feature cannot be created
+	reservation := ""
+	if opts.Reserve && f.scheme.HasPlaceholder(naming.PlaceholderNum) {
+		reserved := 0
+		if reserved, reservation, err = f.reserveFeatureNumber(values.Number); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				if releaseErr := f.releaseReservation(reservation); releaseErr != nil {
+					err = fmt.Errorf("%w (the reservation %s was not released: %v)", err, reservation, releaseErr)
+				}
+			}
+		}()
 
-	// Create and switch to new branch
-	if err := f.git.CreateBranch(branchName); err != nil {
+		if reserved != values.Number {
+			values.Number = reserved
+			if branchName, err = f.createBranchName(values); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	featureNum := ticket
+	if f.scheme.HasPlaceholder(naming.PlaceholderNum) {
+		featureNum = f.scheme.FormatNumber(values.Number)
+	}
+
+	if dirty {
+		if err := f.git.Stash("specify: before creating " + branchName); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create and switch to new branch, or check it out in a worktree of its own
+	worktreePath := ""
+	if opts.Worktree {
+		if worktreePath, err = f.worktreePath(repoRoot, branchName); err != nil {
+			return nil, err
+		}
+		if err := f.git.AddWorktree(worktreePath, branchName, baseRef); err != nil {
+			return nil, fmt.Errorf("failed to create worktree: %w", err)
+		}
+
+		relSpecs, err := filepath.Rel(repoRoot, specsDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve specs directory: %w", err)
+		}
+		specsDir = filepath.Join(worktreePath, relSpecs)
+	} else if err := f.git.CreateBranch(branchName, baseRef); err != nil {
 		return nil, fmt.Errorf("failed to create branch: %w", err)
 	}
 
 	// Create feature directory
-	featureDir := filepath.Join(specsDir, branchName)
+	featureDir := filepath.Join(specsDir, f.scheme.DirName(branchName))
 	if err := f.filesystem.CreateDirectory(featureDir); err != nil {
 		return nil, fmt.Errorf("failed to create feature directory: %w", err)
 	}
 
 	// Copy template if it exists
 	templatePath := filepath.Join(repoRoot, "templates", "spec-template.md")
+	if f.config.Templates.Spec != "" {
+		templatePath = f.projectPath(repoRoot, f.config.Templates.Spec)
+	}
 	specFile := filepath.Join(featureDir, "spec.md")
 
 	if exists, _ := f.filesystem.FileExists(templatePath); exists {
@@ -105,29 +225,69 @@ func (f *FeatureService) CreateFeature(description string) (*models.FeatureCreat
 		}
 	}
 
-	return &models.FeatureCreateResult{
+	issueURL := ""
+	if opts.Issue != nil {
+		if err := f.seedSpecFromIssue(specFile, opts.Issue); err != nil {
+			return nil, err
+		}
+		issueURL = opts.Issue.URL
+	}
+
+	repo := f.git
+	if worktreePath != "" {
+		repo = f.git.At(worktreePath)
+	}
+	commit, err := f.commitArtifacts(repo, featureDir, commitActionCreate, featureDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &models.FeatureCreateResult{
 		BranchName: branchName,
 		SpecFile:   specFile,
 		FeatureNum: featureNum,
-	}, nil
+		Reserved:   reservation,
+		Worktree:   worktreePath,
+		BaseRef:    baseRef,
+		Stashed:    dirty,
+		Commit:     commit,
+		Issue:      issueURL,
+	}
+	if baseWarning != "" {
+		result.Warnings = append(result.Warnings, baseWarning)
+	}
+	return result, nil
 }
 
-func (f *FeatureService) SetupPlan() (*models.FeaturePlanResult, error) {
-	repoRoot, err := f.git.GetRepoRoot()
+// createBranchName formats the branch name of a new feature and checks the
+// branch does not exist yet
+func (f *FeatureService) createBranchName(values naming.Values) (string, error) {
+	branchName, err := f.scheme.Format(values)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get repository root: %w", err)
+		return "", fmt.Errorf("failed to create branch name: %w", err)
 	}
 
-	currentBranch, err := f.git.GetCurrentBranch()
+	exists, err := f.git.BranchExists(branchName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current branch: %w", err)
+		return "", err
+	}
+	if exists {
+		return "", fmt.Errorf("%w: %s. Switch to it with 'git checkout %s' or choose another description", models.ErrBranchExists, branchName, branchName)
 	}
+	return branchName, nil
+}
 
-	if !f.isFeatureBranch(currentBranch) {
-		return nil, fmt.Errorf("not on a feature branch. Current branch: %s. Feature branches should be named like: 001-feature-name", currentBranch)
+func (f *FeatureService) SetupPlan() (*models.FeaturePlanResult, error) {
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	featureDir := filepath.Join(repoRoot, "specs", currentBranch)
+	feature, err := f.currentFeature(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	currentBranch, featureDir := feature.Branch, feature.Dir
 
 	// Create feature directory if it doesn't exist
 	if err := f.filesystem.CreateDirectory(featureDir); err != nil {
@@ -142,7 +302,11 @@ func (f *FeatureService) SetupPlan() (*models.FeaturePlanResult, error) {
 
 	// Copy plan template from agent-specific directory if it exists
 	templatePath := filepath.Join(repoRoot, "."+aiAssistant, "templates", "plan-template.md")
+	if f.config.Templates.Plan != "" {
+		templatePath = f.projectPath(repoRoot, f.config.Templates.Plan)
+	}
 	planFile := filepath.Join(featureDir, "plan.md")
+	written := []string{}
 
 	if exists, _ := f.filesystem.FileExists(templatePath); exists {
 		// Read template content
@@ -158,6 +322,12 @@ func (f *FeatureService) SetupPlan() (*models.FeaturePlanResult, error) {
 		if err := f.filesystem.WriteFile(planFile, processedContent); err != nil {
 			return nil, fmt.Errorf("failed to write plan file: %w", err)
 		}
+		written = append(written, planFile)
+	}
+
+	commit, err := f.commitArtifacts(f.git, featureDir, commitActionPlan, written...)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.FeaturePlanResult{
@@ -165,6 +335,7 @@ func (f *FeatureService) SetupPlan() (*models.FeaturePlanResult, error) {
 		ImplPlan:    planFile,
 		SpecsDir:    featureDir,
 		Branch:      currentBranch,
+		Commit:      commit,
 	}, nil
 }
 
@@ -174,16 +345,11 @@ func (f *FeatureService) CheckPrerequisites() (*models.FeatureCheckResult, error
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	currentBranch, err := f.git.GetCurrentBranch()
+	feature, err := f.currentFeature(repoRoot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current branch: %w", err)
-	}
-
-	if !f.isFeatureBranch(currentBranch) {
-		return nil, fmt.Errorf("not on a feature branch. Current branch: %s. Feature branches should be named like: 001-feature-name", currentBranch)
+		return nil, err
 	}
-
-	featureDir := filepath.Join(repoRoot, "specs", currentBranch)
+	featureDir := feature.Dir
 
 	// Check if feature directory exists
 	if exists, _ := f.filesystem.DirectoryExists(featureDir); !exists {
@@ -238,22 +404,19 @@ func (f *FeatureService) ValidateAgentType(agentType string) error {
 		agentType, strings.Join(models.ListAgents(), ", "))
 }
 
-func (f *FeatureService) UpdateContext(agentType string) (*models.FeatureContextResult, error) {
+// UpdateContext refreshes the agent context files from the project tech profile.
+// historySize limits the Recent Changes list; zero uses the default.
+func (f *FeatureService) UpdateContext(agentType string, historySize int) (*models.FeatureContextResult, error) {
 	repoRoot, err := f.git.GetRepoRoot()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	currentBranch, err := f.git.GetCurrentBranch()
+	feature, err := f.currentFeature(repoRoot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current branch: %w", err)
+		return nil, err
 	}
-
-	if !f.isFeatureBranch(currentBranch) {
-		return nil, fmt.Errorf("not on a feature branch. Current branch: %s", currentBranch)
-	}
-
-	featureDir := filepath.Join(repoRoot, "specs", currentBranch)
+	currentBranch, featureDir := feature.Branch, feature.Dir
 	planFile := filepath.Join(featureDir, "plan.md")
 
 	// Check if plan exists
@@ -267,70 +430,68 @@ func (f *FeatureService) UpdateContext(agentType string) (*models.FeatureContext
 		return nil, fmt.Errorf("failed to read plan file: %w", err)
 	}
 
-	techInfo := f.extractTechInfo(planContent)
+	// Rebuild the project tech profile from every feature plan
+	specsDir := f.specsDir(repoRoot)
+	profile, err := f.buildTechProfile(repoRoot, specsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing or unreadable profile just starts a fresh history
+	var previous []models.ChangeEntry
+	if existing, found, err := f.readTechProfile(specsDir); err == nil && found {
+		previous = existing.History
+	}
+	featureName := filepath.Base(featureDir)
+	profile.History = f.buildChangeHistory(specsDir, profile, previous, featureName, historySize)
+	if err := f.writeTechProfile(specsDir, profile); err != nil {
+		return nil, err
+	}
+
+	current, ok := profile.GetFeature(featureName)
+	if !ok {
+		current = ParseTechStack(planContent)
+	}
 
 	// Determine which files to update
 	updates := []models.ContextUpdate{}
 	summary := []string{}
+	written := []string{filepath.Join(specsDir, models.TechProfileFile)}
 
-	if agentType == "" {
-		// Update all existing files
-		agentFiles := map[string]string{
-			"claude":  filepath.Join(repoRoot, "CLAUDE.md"),
-			"gemini":  filepath.Join(repoRoot, "GEMINI.md"),
-			"copilot": filepath.Join(repoRoot, ".github", "copilot-instructions.md"),
-		}
-
-		for agent, file := range agentFiles {
-			if exists, _ := f.filesystem.FileExists(file); exists {
-				if err := f.updateAgentFile(file, agent, techInfo, currentBranch); err != nil {
-					return nil, fmt.Errorf("failed to update %s: %w", agent, err)
-				}
-				updates = append(updates, models.ContextUpdate{Agent: f.getAgentDisplayName(agent)})
-			}
-		}
-
-		// If no files exist, create Claude file by default
-		if len(updates) == 0 {
-			claudeFile := agentFiles["claude"]
-			if err := f.updateAgentFile(claudeFile, "claude", techInfo, currentBranch); err != nil {
-				return nil, fmt.Errorf("failed to create Claude context file: %w", err)
-			}
-			updates = append(updates, models.ContextUpdate{Agent: "Claude Code"})
-		}
-	} else {
-		// Update specific agent file
-		var file string
-		switch agentType {
-		case "claude":
-			file = filepath.Join(repoRoot, "CLAUDE.md")
-		case "gemini":
-			file = filepath.Join(repoRoot, "GEMINI.md")
-		case "copilot":
-			file = filepath.Join(repoRoot, ".github", "copilot-instructions.md")
+	for _, target := range f.contextTargets(repoRoot, agentType) {
+		created, err := f.updateAgentFile(target.file, profile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update %s: %w", target.agent, err)
 		}
+		updates = append(updates, models.ContextUpdate{
+			Agent:   models.GetAIAssistantDisplayName(target.agent),
+			File:    target.file,
+			Created: created,
+		})
+		written = append(written, target.file)
+	}
 
-		if err := f.updateAgentFile(file, agentType, techInfo, currentBranch); err != nil {
-			return nil, fmt.Errorf("failed to update %s: %w", agentType, err)
-		}
-		updates = append(updates, models.ContextUpdate{Agent: f.getAgentDisplayName(agentType)})
+	commit, err := f.commitArtifacts(f.git, featureDir, commitActionContext, written...)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build summary
-	if techInfo.Language != "" {
-		summary = append(summary, fmt.Sprintf("Added language: %s", techInfo.Language))
+	for _, lang := range current.Languages {
+		summary = append(summary, fmt.Sprintf("Added language: %s", lang))
 	}
-	if techInfo.Framework != "" {
-		summary = append(summary, fmt.Sprintf("Added framework: %s", techInfo.Framework))
+	for _, framework := range current.Frameworks {
+		summary = append(summary, fmt.Sprintf("Added framework: %s", framework))
 	}
-	if techInfo.Database != "" {
-		summary = append(summary, fmt.Sprintf("Added database: %s", techInfo.Database))
+	for _, storage := range current.Storage {
+		summary = append(summary, fmt.Sprintf("Added database: %s", storage))
 	}
 
 	return &models.FeatureContextResult{
 		Branch:  currentBranch,
 		Updates: updates,
 		Summary: summary,
+		Commit:  commit,
 	}, nil
 }
 
@@ -340,16 +501,11 @@ func (f *FeatureService) GetPaths() (*models.FeaturePathsResult, error) {
 		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 
-	currentBranch, err := f.git.GetCurrentBranch()
+	feature, err := f.currentFeature(repoRoot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current branch: %w", err)
+		return nil, err
 	}
-
-	if !f.isFeatureBranch(currentBranch) {
-		return nil, fmt.Errorf("not on a feature branch. Current branch: %s. Feature branches should be named like: 001-feature-name", currentBranch)
-	}
-
-	featureDir := filepath.Join(repoRoot, "specs", currentBranch)
+	currentBranch, featureDir := feature.Branch, feature.Dir
 
 	return &models.FeaturePathsResult{
 		RepoRoot:    repoRoot,
@@ -363,255 +519,245 @@ func (f *FeatureService) GetPaths() (*models.FeaturePathsResult, error) {
 
 // Helper methods
 
-func (f *FeatureService) getHighestFeatureNumber(specsDir string) (int, error) {
-	highest := 0
+// Config returns the project configuration used by the service
+func (f *FeatureService) Config() *models.ProjectConfig {
+	return f.config
+}
 
-	exists, err := f.filesystem.DirectoryExists(specsDir)
-	if err != nil || !exists {
-		return highest, nil
+// projectRoot returns the directory that configured paths are relative to
+func (f *FeatureService) projectRoot(repoRoot string) string {
+	if f.config.Root != "" {
+		return f.config.Root
 	}
+	return repoRoot
+}
 
-	entries, err := f.filesystem.ListDirectory(specsDir)
-	if err != nil {
-		return highest, err
-	}
-
-	for _, entry := range entries {
-		if len(entry) >= 3 {
-			numberStr := entry[:3]
-			if number, err := strconv.Atoi(numberStr); err == nil {
-				if number > highest {
-					highest = number
-				}
-			}
-		}
+// projectPath resolves a configured path against the project root
+func (f *FeatureService) projectPath(repoRoot, path string) string {
+	if filepath.IsAbs(path) {
+		return path
 	}
+	return filepath.Join(f.projectRoot(repoRoot), path)
+}
 
-	return highest, nil
+// specsDir returns the configured feature root
+func (f *FeatureService) specsDir(repoRoot string) string {
+	return f.projectPath(repoRoot, f.config.GetSpecsDir())
 }
 
-func (f *FeatureService) createBranchName(description, featureNum string) string {
-	// Convert to lowercase and replace non-alphanumeric with hyphens
-	name := strings.ToLower(description)
-	name = strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-			return r
+// baseRef resolves the ref new feature branches start from: from when given,
+// otherwise the default branch (local, or remote-tracking when there is no
+// local copy). An empty ref branches from HEAD, and warning explains why.
+func (f *FeatureService) baseRef(from string) (ref, warning string, err error) {
+	if from != "" {
+		exists, err := f.git.RefExists(from)
+		if err != nil {
+			return "", "", err
 		}
-		return '-'
-	}, name)
-
-	// Remove multiple consecutive hyphens
-	for strings.Contains(name, "--") {
-		name = strings.ReplaceAll(name, "--", "-")
+		if !exists {
+			return "", "", fmt.Errorf("%w: %s", models.ErrRefNotFound, from)
+		}
+		return from, "", nil
 	}
 
-	// Trim leading/trailing hyphens
-	name = strings.Trim(name, "-")
-
-	// Extract first 3 meaningful words
-	words := strings.Split(name, "-")
-	meaningfulWords := []string{}
-	for _, word := range words {
-		if word != "" && len(meaningfulWords) < 3 {
-			meaningfulWords = append(meaningfulWords, word)
+	defaultBranch, err := f.git.GetDefaultBranch(f.config.GetNumberingRemote())
+	if err != nil {
+		return "", fmt.Sprintf("branching from HEAD: %v", err), nil
+	}
+	remoteBranch := f.config.GetNumberingRemote() + "/" + defaultBranch
+	for _, ref := range []string{defaultBranch, remoteBranch} {
+		exists, err := f.git.RefExists(ref)
+		if err != nil {
+			return "", "", err
+		}
+		if exists {
+			return ref, "", nil
 		}
 	}
-
-	return fmt.Sprintf("%s-%s", featureNum, strings.Join(meaningfulWords, "-"))
+	return "", fmt.Sprintf("branching from HEAD: neither %s nor %s exists", defaultBranch, remoteBranch), nil
 }
 
+// isFeatureBranch reports whether a branch follows the configured naming scheme
 func (f *FeatureService) isFeatureBranch(branch string) bool {
-	if len(branch) < 4 {
-		return false
-	}
-	// Check format: 001-feature-name
-	if branch[3] != '-' {
-		return false
-	}
-	for i := 0; i < 3; i++ {
-		if branch[i] < '0' || branch[i] > '9' {
-			return false
-		}
-	}
-	return true
+	_, ok := f.scheme.Parse(branch)
+	return ok
 }
 
-type TechInfo struct {
-	Language    string
-	Framework   string
-	Testing     string
-	Database    string
-	ProjectType string
+// featureNumber extracts the feature number from a branch or directory name
+func (f *FeatureService) featureNumber(name string) (int, bool) {
+	parsed, ok := f.scheme.Parse(name)
+	if !ok || !parsed.HasNumber {
+		return 0, false
+	}
+	return parsed.Number, true
 }
 
-func (f *FeatureService) extractTechInfo(content string) TechInfo {
-	info := TechInfo{}
+// contextTarget pairs an agent with the context file it reads.
+type contextTarget struct {
+	agent string
+	file  string
+}
 
-	// Extract technology information from plan content using pre-compiled regexes
-	if match := langRegex.FindStringSubmatch(content); len(match) > 1 {
-		if !strings.Contains(match[1], "NEEDS CLARIFICATION") {
-			info.Language = strings.TrimSpace(match[1])
+// contextTargets resolves which agent context files to refresh. A specific agent
+// always gets its file created or updated, as do the configured default agents;
+// otherwise every existing agent file is refreshed, falling back to the detected
+// assistant (or Claude) when none exist.
+func (f *FeatureService) contextTargets(repoRoot, agentType string) []contextTarget {
+	if agentType != "" {
+		return []contextTarget{{
+			agent: agentType,
+			file:  filepath.Join(repoRoot, models.GetAgentContextFile(agentType)),
+		}}
+	}
+
+	// Configured default agents replace file detection
+	if len(f.config.Agents) > 0 {
+		targets := []contextTarget{}
+		seen := make(map[string]bool)
+		for _, agent := range f.config.Agents {
+			file := filepath.Join(repoRoot, models.GetAgentContextFile(agent))
+			if !seen[file] {
+				seen[file] = true
+				targets = append(targets, contextTarget{agent: agent, file: file})
+			}
 		}
+		return targets
 	}
 
-	if match := depRegex.FindStringSubmatch(content); len(match) > 1 {
-		if !strings.Contains(match[1], "NEEDS CLARIFICATION") {
-			info.Framework = strings.TrimSpace(match[1])
+	targets := []contextTarget{}
+	seen := make(map[string]bool)
+	for _, agent := range models.ListAgents() {
+		file := filepath.Join(repoRoot, models.GetAgentContextFile(agent))
+		if seen[file] {
+			continue
 		}
-	}
+		seen[file] = true
 
-	if match := testRegex.FindStringSubmatch(content); len(match) > 1 {
-		if !strings.Contains(match[1], "NEEDS CLARIFICATION") {
-			info.Testing = strings.TrimSpace(match[1])
+		if exists, _ := f.filesystem.FileExists(file); exists {
+			targets = append(targets, contextTarget{agent: agent, file: file})
 		}
 	}
 
-	if match := storageRegex.FindStringSubmatch(content); len(match) > 1 {
-		if !strings.Contains(match[1], "N/A") && !strings.Contains(match[1], "NEEDS CLARIFICATION") {
-			info.Database = strings.TrimSpace(match[1])
+	if len(targets) == 0 {
+		agent, err := f.detectAIAssistant(repoRoot)
+		if err != nil {
+			agent = "claude"
 		}
+		targets = append(targets, contextTarget{
+			agent: agent,
+			file:  filepath.Join(repoRoot, models.GetAgentContextFile(agent)),
+		})
 	}
 
-	if match := projectTypeRegex.FindStringSubmatch(content); len(match) > 1 {
-		info.ProjectType = strings.TrimSpace(match[1])
-	}
-
-	return info
+	return targets
 }
 
-func (f *FeatureService) updateAgentFile(filePath, agentType string, techInfo TechInfo, currentBranch string) error {
+// updateAgentFile rewrites the generated regions of an agent context file from
+// the project tech profile, creating the file from the template when missing
+func (f *FeatureService) updateAgentFile(filePath string, profile *models.TechProfile) (bool, error) {
 	exists, err := f.filesystem.FileExists(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to check file existence: %w", err)
-	}
-
-	if !exists {
-		// Create new file from template
-		return f.createAgentFile(filePath, agentType, techInfo, currentBranch)
+		return false, fmt.Errorf("failed to check file existence: %w", err)
 	}
 
-	// Update existing file
-	return f.updateExistingAgentFile(filePath, techInfo, currentBranch)
-}
-
-func (f *FeatureService) createAgentFile(filePath, agentType string, techInfo TechInfo, currentBranch string) error {
 	repoRoot, _ := f.git.GetRepoRoot()
-	templatePath := filepath.Join(repoRoot, "templates", "agent-file-template.md")
 
 	var content string
-	if exists, _ := f.filesystem.FileExists(templatePath); exists {
-		templateContent, err := f.filesystem.ReadFile(templatePath)
+	if exists {
+		content, err = f.filesystem.ReadFile(filePath)
 		if err != nil {
-			return fmt.Errorf("failed to read template: %w", err)
+			return false, fmt.Errorf("failed to read existing file: %w", err)
 		}
-		content = templateContent
 	} else {
-		// Basic template if not found
-		content = f.getBasicAgentTemplate()
+		// Create new file from template
+		content, err = f.agentFileTemplate(repoRoot)
+		if err != nil {
+			return false, err
+		}
 	}
 
-	// Replace placeholders
-	content = strings.ReplaceAll(content, "[PROJECT NAME]", filepath.Base(repoRoot))
-	content = strings.ReplaceAll(content, "[DATE]", time.Now().Format("2006-01-02"))
+	doc, err := regions.Parse(content)
+	if err != nil {
+		return false, fmt.Errorf("malformed managed section in %s: %w", filepath.Base(filePath), err)
+	}
 
-	if techInfo.Language != "" && techInfo.Framework != "" {
-		content = strings.ReplaceAll(content, "[EXTRACTED FROM ALL PLAN.MD FILES]",
-			fmt.Sprintf("- %s + %s (%s)", techInfo.Language, techInfo.Framework, currentBranch))
+	// Files written before managed regions existed keep their generated
+	// sections under plain headings; adopt them so they get rewritten in place
+	for _, section := range legacyContextSections {
+		doc.AdoptSection(section.heading, section.region)
 	}
 
-	// Add project structure based on type
-	if strings.Contains(techInfo.ProjectType, "web") {
-		content = strings.ReplaceAll(content, "[ACTUAL STRUCTURE FROM PLANS]", "backend/\nfrontend/\ntests/")
-	} else {
-		content = strings.ReplaceAll(content, "[ACTUAL STRUCTURE FROM PLANS]", "src/\ntests/")
+	if err := f.setContextRegions(doc, profile); err != nil {
+		return false, fmt.Errorf("failed to update %s: %w", filepath.Base(filePath), err)
 	}
 
-	// Add commands based on language
-	commands := f.getCommandsForLanguage(techInfo.Language)
-	content = strings.ReplaceAll(content, "[ONLY COMMANDS FOR ACTIVE TECHNOLOGIES]", commands)
+	// Fill header placeholders and update date outside the managed regions
+	content = f.fillAgentTemplate(doc.String(), repoRoot)
+	content = updateDateRegex.ReplaceAllString(content, fmt.Sprintf("Last updated: %s", time.Now().Format("2006-01-02")))
 
-	// Add code style
-	if techInfo.Language != "" {
-		content = strings.ReplaceAll(content, "[LANGUAGE-SPECIFIC, ONLY FOR LANGUAGES IN USE]",
-			fmt.Sprintf("%s: Follow standard conventions", techInfo.Language))
-	}
+	return !exists, f.filesystem.WriteFile(filePath, content)
+}
 
-	// Add recent changes
-	if techInfo.Language != "" && techInfo.Framework != "" {
-		content = strings.ReplaceAll(content, "[LAST 3 FEATURES AND WHAT THEY ADDED]",
-			fmt.Sprintf("- %s: Added %s + %s", currentBranch, techInfo.Language, techInfo.Framework))
+// legacyContextSections maps headings from pre-region agent files to the
+// managed region that replaces them
+var legacyContextSections = []struct {
+	heading string
+	region  string
+}{
+	{"## Active Technologies", models.RegionTech},
+	{"## Project Structure", models.RegionStructure},
+	{"## Code Style", models.RegionStyle},
+	{"## Recent Changes", models.RegionChanges},
+}
+
+// agentFileTemplate returns the template used for new agent context files
+func (f *FeatureService) agentFileTemplate(repoRoot string) (string, error) {
+	templatePath := filepath.Join(repoRoot, "templates", "agent-file-template.md")
+	if f.config.Templates.AgentFile != "" {
+		templatePath = f.projectPath(repoRoot, f.config.Templates.AgentFile)
 	}
 
-	// Create directory if needed
-	if dir := filepath.Dir(filePath); dir != "." {
-		if err := f.filesystem.CreateDirectory(dir); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
+	if exists, _ := f.filesystem.FileExists(templatePath); exists {
+		templateContent, err := f.filesystem.ReadFile(templatePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template: %w", err)
 		}
+		return templateContent, nil
 	}
 
-	return f.filesystem.WriteFile(filePath, content)
+	// Basic template if not found
+	return f.getBasicAgentTemplate(), nil
 }
 
-func (f *FeatureService) updateExistingAgentFile(filePath string, techInfo TechInfo, currentBranch string) error {
-	content, err := f.filesystem.ReadFile(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to read existing file: %w", err)
-	}
-
-	// Simple update - add new technology to active technologies section
-	if techInfo.Language != "" {
-		newTech := fmt.Sprintf("- %s + %s (%s)", techInfo.Language, techInfo.Framework, currentBranch)
-
-		// Look for Active Technologies section
-		if strings.Contains(content, "## Active Technologies") {
-			// Add new tech if not already present
-			if !strings.Contains(content, newTech) {
-				content = activeTechtRegex.ReplaceAllStringFunc(content, func(match string) string {
-					parts := activeTechtRegex.FindStringSubmatch(match)
-					if len(parts) == 4 {
-						return parts[1] + parts[2] + "\n" + newTech + parts[3]
-					}
-					return match
-				})
-			}
-		}
-
-		// Update recent changes
-		if strings.Contains(content, "## Recent Changes") {
-			newChange := fmt.Sprintf("- %s: Added %s + %s", currentBranch, techInfo.Language, techInfo.Framework)
-			content = recentChangesRegex.ReplaceAllStringFunc(content, func(match string) string {
-				parts := recentChangesRegex.FindStringSubmatch(match)
-				if len(parts) == 4 {
-					// Add new change at the top, keep only last 3
-					changes := strings.Split(strings.TrimSpace(parts[2]), "\n")
-					changes = append([]string{newChange}, changes...)
-					if len(changes) > 3 {
-						changes = changes[:3]
-					}
-					return parts[1] + strings.Join(changes, "\n") + parts[3]
-				}
-				return match
-			})
+// setContextRegions rewrites the generated regions of an agent context file.
+// The stack regions are left alone while no plan has a parsable stack; the
+// change history is always updated.
+func (f *FeatureService) setContextRegions(doc *regions.Document, profile *models.TechProfile) error {
+	sections := []regions.Region{}
+	if !profile.Stack.IsEmpty() {
+		sections = append(sections,
+			regions.Region{Name: models.RegionTech, Body: renderTechRegion(profile.Stack)},
+			regions.Region{Name: models.RegionStructure, Body: renderStructureRegion(profile.Stack)},
+			regions.Region{Name: models.RegionCommands, Body: renderCommandsRegion(profile.Commands)},
+			regions.Region{Name: models.RegionStyle, Body: renderStyleRegion(profile.Stack)},
+		)
+	}
+	sections = append(sections, regions.Region{Name: models.RegionChanges, Body: renderChangesRegion(profile.History)})
+
+	for _, section := range sections {
+		if err := doc.Set(section.Name, section.Body); err != nil {
+			return err
 		}
 	}
 
-	// Update date
-	content = updateDateRegex.ReplaceAllString(content, fmt.Sprintf("Last updated: %s", time.Now().Format("2006-01-02")))
-
-	return f.filesystem.WriteFile(filePath, content)
+	return nil
 }
 
-func (f *FeatureService) getAgentDisplayName(agentType string) string {
-	switch agentType {
-	case "claude":
-		return "Claude Code"
-	case "gemini":
-		return "Gemini CLI"
-	case "copilot":
-		return "GitHub Copilot"
-	default:
-		return agentType
-	}
+// fillAgentTemplate replaces the header placeholders of the agent file template
+func (f *FeatureService) fillAgentTemplate(content, repoRoot string) string {
+	content = strings.ReplaceAll(content, "[PROJECT NAME]", filepath.Base(repoRoot))
+	content = strings.ReplaceAll(content, "[DATE]", time.Now().Format("2006-01-02"))
+	return content
 }
 
 func (f *FeatureService) getCommandsForLanguage(language string) string {
@@ -629,25 +775,11 @@ func (f *FeatureService) getBasicAgentTemplate() string {
 
 Last updated: [DATE]
 
-## Active Technologies
-[EXTRACTED FROM ALL PLAN.MD FILES]
-
-## Project Structure
-` + "```" + `
-[ACTUAL STRUCTURE FROM PLANS]
-` + "```" + `
-
-## Commands
-` + "```bash" + `
-[ONLY COMMANDS FOR ACTIVE TECHNOLOGIES]
-` + "```" + `
-
-## Code Style
-[LANGUAGE-SPECIFIC, ONLY FOR LANGUAGES IN USE]
-
-## Recent Changes
-[LAST 3 FEATURES AND WHAT THEY ADDED]
-`
+` + regions.Wrap(models.RegionTech, "## Active Technologies\n[EXTRACTED FROM ALL PLAN.MD FILES]") + `
+` + regions.Wrap(models.RegionStructure, "## Project Structure\n```\n[ACTUAL STRUCTURE FROM PLANS]\n```") + `
+` + regions.Wrap(models.RegionCommands, "## Commands\n```bash\n[ONLY COMMANDS FOR ACTIVE TECHNOLOGIES]\n```") + `
+` + regions.Wrap(models.RegionStyle, "## Code Style\n[LANGUAGE-SPECIFIC, ONLY FOR LANGUAGES IN USE]") + `
+` + regions.Wrap(models.RegionChanges, "## Recent Changes\n[RECENT FEATURES AND WHAT THEY ADDED]")
 }
 
 // detectAIAssistant detects which AI assistant is being used by checking for hidden directories
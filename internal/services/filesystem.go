@@ -24,6 +24,8 @@ type FilesystemServiceInterface interface {
 	ExtractZip(src, dest string) error
 	ListDirectory(path string) ([]string, error)
 	CopyFile(src, dest string) error
+	Rename(oldPath, newPath string) error
+	RemoveDirectory(path string) error
 }
 
 // FilesystemService handles file and directory operations
@@ -391,3 +393,14 @@ func (fs *FilesystemService) CopyFile(src, dest string) error {
 
 	return fs.copyFile(src, dest, info.Mode())
 }
+
+// Rename moves a file or directory, refusing to replace an existing destination
+func (fs *FilesystemService) Rename(oldPath, newPath string) error {
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("destination already exists: %s", newPath)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", oldPath, newPath, err)
+	}
+	return nil
+}
@@ -2,26 +2,75 @@ package services
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
 )
 
-type GitService struct{}
+// GitService runs the git executable. Commands run in dir, or in the working
+// directory when dir is empty.
+type GitService struct {
+	dir string
+}
 
+// GitServiceInterface is the repository abstraction used for all git access.
+// Methods operate on the repository containing the working directory; At
+// selects another one.
 type GitServiceInterface interface {
+	At(dir string) GitServiceInterface
+	Init() error
 	GetRepoRoot() (string, error)
 	GetCurrentBranch() (string, error)
-	CreateBranch(branchName string) error
+	CreateBranch(branchName, startPoint string) error
 	CheckoutBranch(branchName string) error
 	BranchExists(branchName string) (bool, error)
+	RefExists(ref string) (bool, error)
+	ResolveRef(ref string) (string, error)
+	IsDirty() (bool, error)
+	Status() ([]models.GitFileStatus, error)
+	Add(paths ...string) error
+	Commit(message string, author *models.GitSignature, paths ...string) (string, error)
+	Diff(ref string, paths ...string) (string, error)
+	Log(path string, limit int) ([]models.GitCommit, error)
+	ListTags() ([]string, error)
+	CreateTag(name, message string) error
+	GetConfig(key string) (string, error)
+	Stash(message string) error
+	RenameBranch(oldName, newName string) error
+	ListBranches() ([]string, error)
+	GetDefaultBranch(remote string) (string, error)
+	ListTree(ref, dir string) ([]string, error)
+	ListRemoteRefs(remote, prefix string) ([]string, error)
+	PushNewRef(remote, ref, message string) error
+	DeleteRemoteRef(remote, ref string) error
+	DeleteBranch(branchName string, force bool) error
+	AddWorktree(path, branchName, startPoint string) error
+	ListWorktrees() ([]models.Worktree, error)
+	RemoveWorktree(path string, force bool) error
 }
 
 func NewGitService() *GitService {
 	return &GitService{}
 }
 
+// At returns a service operating on the repository at dir
+func (g *GitService) At(dir string) GitServiceInterface {
+	return &GitService{dir: dir}
+}
+
+// command prepares a git command running in the service's directory
+func (g *GitService) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.dir
+	return cmd
+}
+
 func (g *GitService) GetRepoRoot() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd := g.command("rev-parse", "--show-toplevel")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get repository root: %w", err)
@@ -30,7 +79,7 @@ func (g *GitService) GetRepoRoot() (string, error) {
 }
 
 func (g *GitService) GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := g.command("rev-parse", "--abbrev-ref", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
@@ -38,16 +87,22 @@ func (g *GitService) GetCurrentBranch() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-func (g *GitService) CreateBranch(branchName string) error {
-	cmd := exec.Command("git", "checkout", "-b", branchName)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to create branch '%s': %w", branchName, err)
+// CreateBranch creates branchName at startPoint, or at HEAD when startPoint is
+// empty, and switches to it
+func (g *GitService) CreateBranch(branchName, startPoint string) error {
+	args := []string{"checkout", "--quiet", "-b", branchName}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	cmd := g.command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create branch '%s': %s", branchName, strings.TrimSpace(string(output)))
 	}
 	return nil
 }
 
 func (g *GitService) CheckoutBranch(branchName string) error {
-	cmd := exec.Command("git", "checkout", branchName)
+	cmd := g.command("checkout", branchName)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to checkout branch '%s': %w", branchName, err)
 	}
@@ -55,7 +110,7 @@ func (g *GitService) CheckoutBranch(branchName string) error {
 }
 
 func (g *GitService) BranchExists(branchName string) (bool, error) {
-	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branchName)
+	cmd := g.command("show-ref", "--verify", "--quiet", "refs/heads/"+branchName)
 	err := cmd.Run()
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
@@ -65,3 +120,434 @@ func (g *GitService) BranchExists(branchName string) (bool, error) {
 	}
 	return true, nil
 }
+
+// RefExists reports whether ref resolves to a commit
+func (g *GitService) RefExists(ref string) (bool, error) {
+	cmd := g.command("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	err := cmd.Run()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to resolve '%s': %w", ref, err)
+	}
+	return true, nil
+}
+
+// ResolveRef returns the commit a ref points to
+func (g *GitService) ResolveRef(ref string) (string, error) {
+	cmd := g.command("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", models.ErrRefNotFound, ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsDirty reports whether tracked files have uncommitted changes, staged or not
+func (g *GitService) IsDirty() (bool, error) {
+	cmd := g.command("status", "--porcelain", "--untracked-files=no")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// Stash stashes uncommitted changes to tracked files
+func (g *GitService) Stash(message string) error {
+	cmd := g.command("stash", "push", "--quiet", "--message", message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stash changes: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (g *GitService) RenameBranch(oldName, newName string) error {
+	cmd := g.command("branch", "-m", oldName, newName)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to rename branch '%s' to '%s': %s", oldName, newName, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ListBranches returns local and remote-tracking branch names; remote-tracking
+// branches are listed without their remote, so origin/004-login becomes 004-login
+func (g *GitService) ListBranches() ([]string, error) {
+	cmd := g.command("for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	branches := []string{}
+	for _, ref := range strings.Fields(string(output)) {
+		if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			branches = append(branches, name)
+			continue
+		}
+		// refs/remotes/<remote>/<branch>
+		parts := strings.SplitN(strings.TrimPrefix(ref, "refs/remotes/"), "/", 2)
+		if len(parts) == 2 && parts[1] != "HEAD" {
+			branches = append(branches, parts[1])
+		}
+	}
+	return branches, nil
+}
+
+// GetDefaultBranch returns the branch <remote>/HEAD points to, falling back
+// to a local main or master branch
+func (g *GitService) GetDefaultBranch(remote string) (string, error) {
+	cmd := g.command("symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if output, err := cmd.Output(); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(output)), remote+"/"), nil
+	}
+
+	for _, candidate := range []string{"main", "master"} {
+		if exists, err := g.BranchExists(candidate); err == nil && exists {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("failed to determine default branch: %s/HEAD is not set and neither main nor master exists", remote)
+}
+
+// ListTree returns the names of the entries of dir (relative to the repository
+// root) at ref, without reading the working tree
+func (g *GitService) ListTree(ref, dir string) ([]string, error) {
+	cmd := g.command("ls-tree", "--name-only", ref+":"+strings.TrimSuffix(dir, "/"))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s at %s: %w", dir, ref, err)
+	}
+
+	names := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			names = append(names, line[strings.LastIndex(line, "/")+1:])
+		}
+	}
+	return names, nil
+}
+
+// ListRemoteRefs returns the refs on a remote that start with prefix
+func (g *GitService) ListRemoteRefs(remote, prefix string) ([]string, error) {
+	cmd := g.command("ls-remote", "--refs", remote, prefix+"*")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs on %s: %w", remote, err)
+	}
+
+	refs := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			refs = append(refs, fields[1])
+		}
+	}
+	return refs, nil
+}
+
+// PushNewRef creates ref on the remote, pointing at a new commit on top of HEAD
+// with the given message. The push is rejected when the ref already exists; the
+// commit is unique, so a second clone can never "create" the same ref again.
+func (g *GitService) PushNewRef(remote, ref, message string) error {
+	nonce := fmt.Sprintf("%d-%d", time.Now().UnixNano(), os.Getpid())
+	cmd := g.command("commit-tree", "HEAD^{tree}", "-p", "HEAD", "-m", message, "-m", "Nonce: "+nonce)
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to create commit for %s: %w", ref, err)
+	}
+	commit := strings.TrimSpace(string(output))
+
+	cmd = g.command("push", "--quiet", "--force-with-lease="+ref+":", remote, commit+":"+ref)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %s", ref, remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// DeleteRemoteRef deletes ref on remote
+func (g *GitService) DeleteRemoteRef(remote, ref string) error {
+	cmd := g.command("push", "--quiet", remote, ":"+ref)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete %s on %s: %s", ref, remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (g *GitService) DeleteBranch(branchName string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	cmd := g.command("branch", flag, branchName)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete branch '%s': %s", branchName, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// AddWorktree creates branchName at startPoint, or at HEAD when startPoint is
+// empty, and checks it out in a new worktree at path
+func (g *GitService) AddWorktree(path, branchName, startPoint string) error {
+	args := []string{"worktree", "add", "--quiet", "-b", branchName, path}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	cmd := g.command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add worktree '%s': %s", path, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ListWorktrees returns every worktree of the repository, the main one first
+func (g *GitService) ListWorktrees() ([]models.Worktree, error) {
+	cmd := g.command("worktree", "list", "--porcelain")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	worktrees := []models.Worktree{}
+	for _, block := range strings.Split(strings.TrimSpace(string(output)), "\n\n") {
+		worktree := models.Worktree{}
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				worktree.Path = value
+			case "HEAD":
+				worktree.Head = value
+			case "branch":
+				worktree.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "detached":
+				worktree.Detached = true
+			}
+		}
+		if worktree.Path != "" {
+			worktrees = append(worktrees, worktree)
+		}
+	}
+	return worktrees, nil
+}
+
+func (g *GitService) RemoveWorktree(path string, force bool) error {
+	args := []string{"worktree", "remove", path}
+	if force {
+		args = append(args, "--force")
+	}
+	cmd := g.command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove worktree '%s': %s", path, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Init creates an empty repository in the service's directory
+func (g *GitService) Init() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("%w: git is not installed or not found in PATH (install from https://git-scm.com/downloads)", models.ErrToolNotFound)
+	}
+
+	cmd := g.command("init", "--quiet")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to initialize git repository: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Status returns the changed and untracked files, ignored files excluded
+func (g *GitService) Status() ([]models.GitFileStatus, error) {
+	cmd := g.command("status", "--porcelain", "-z", "--untracked-files=all")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+
+	files := []models.GitFileStatus{}
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		file := models.GitFileStatus{Path: entry[3:], Staging: entry[0], Worktree: entry[1]}
+		files = append(files, file)
+
+		// Renames and copies are followed by their source path
+		if file.Staging == 'R' || file.Staging == 'C' {
+			i++
+		}
+	}
+	return files, nil
+}
+
+// Add stages paths, absolute or relative to the repository root, including
+// deletions
+func (g *GitService) Add(paths ...string) error {
+	root, err := g.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to add files to git: %w", err)
+	}
+	if paths, err = repoPaths(root, paths); err != nil {
+		return fmt.Errorf("failed to add files to git: %w", err)
+	}
+
+	cmd := g.command(append([]string{"add", "--all", "--"}, paths...)...)
+	cmd.Dir = root
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to add files to git: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Commit records the staged changes and returns the new commit. With paths,
+// absolute or relative to the repository root, only the changes to those paths
+// are committed and anything else staged stays staged. A nil author uses the
+// configured user.
+func (g *GitService) Commit(message string, author *models.GitSignature, paths ...string) (string, error) {
+	root, err := g.GetRepoRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	args := []string{"commit", "--quiet", "--message", message}
+	if author != nil {
+		args = append(args, "--author", author.String())
+	}
+	if len(paths) > 0 {
+		if paths, err = repoPaths(root, paths); err != nil {
+			return "", fmt.Errorf("failed to create commit: %w", err)
+		}
+		args = append(append(args, "--only", "--"), paths...)
+	}
+
+	cmd := g.command(args...)
+	cmd.Dir = root
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to create commit: %s", strings.TrimSpace(string(output)))
+	}
+	return g.ResolveRef("HEAD")
+}
+
+// Diff returns the unified diff between ref, or HEAD when ref is empty, and the
+// working tree, limited to paths relative to the repository root
+func (g *GitService) Diff(ref string, paths ...string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	root, err := g.GetRepoRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff against %s: %w", ref, err)
+	}
+
+	cmd := g.command(append([]string{"diff", "--no-color", "--no-ext-diff", ref, "--"}, paths...)...)
+	cmd.Dir = root
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff against %s: %w", ref, err)
+	}
+	return string(output), nil
+}
+
+// logFormat separates the fields of a log entry with NUL bytes
+const logFormat = "--format=%H%x00%an%x00%ae%x00%aI%x00%s"
+
+// Log returns up to limit commits reachable from HEAD that touch path
+// (relative to the repository root), newest first. An empty path lists every commit and a limit of zero lists them all.
+func (g *GitService) Log(path string, limit int) ([]models.GitCommit, error) {
+	root, err := g.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	args := []string{"log", logFormat}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", limit))
+	}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+
+	cmd := g.command(args...)
+	cmd.Dir = root
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	commits := []models.GitCommit{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 5 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read log: invalid date %q: %w", fields[3], err)
+		}
+		commits = append(commits, models.GitCommit{
+			Hash:    fields[0],
+			Author:  models.GitSignature{Name: fields[1], Email: fields[2]},
+			Date:    date,
+			Subject: fields[4],
+		})
+	}
+	return commits, nil
+}
+
+// ListTags returns the tag names, sorted
+func (g *GitService) ListTags() ([]string, error) {
+	cmd := g.command("tag", "--list")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// CreateTag tags HEAD; the tag is annotated when message is not empty
+func (g *GitService) CreateTag(name, message string) error {
+	args := []string{"tag", name}
+	if message != "" {
+		args = append(args, "--annotate", "--message", message)
+	}
+
+	cmd := g.command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create tag '%s': %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// GetConfig returns the effective value of a git configuration key, or an
+// empty string when it is not set
+func (g *GitService) GetConfig(key string) (string, error) {
+	cmd := g.command("config", "--get", key)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read git config %s: %w", key, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// repoPaths makes paths relative to the repository root; relative paths are
+// taken to be relative to it already
+func repoPaths(root string, paths []string) ([]string, error) {
+	relative := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if filepath.IsAbs(path) {
+			rel, err := filepath.Rel(root, path)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return nil, fmt.Errorf("%s is outside the repository %s", path, root)
+			}
+			path = rel
+		}
+		relative = append(relative, filepath.ToSlash(path))
+	}
+	return relative, nil
+}
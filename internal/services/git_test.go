@@ -0,0 +1,547 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// newTestRepo creates a repository with one commit on main and changes into it
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to resolve temp dir: %v", err)
+	}
+	t.Chdir(dir)
+
+	// Keep the user's git configuration out of the tests
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	runGit(t, "init", "--quiet", "--initial-branch=main")
+	runGit(t, "config", "user.name", "Test")
+	runGit(t, "config", "user.email", "test@example.com")
+	writeTestFile(t, "README.md", "# test\n")
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial commit")
+
+	return dir
+}
+
+// runGit runs git in the working directory and returns its trimmed output
+func runGit(t *testing.T, args ...string) string {
+	t.Helper()
+
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// writeTestFile writes a file relative to the working directory
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// commitTestFile writes and commits a file on the current branch
+func commitTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	writeTestFile(t, path, content)
+	runGit(t, "add", path)
+	runGit(t, "commit", "--quiet", "-m", "add "+path)
+}
+
+// gitBackends are the GitServiceInterface implementations the conformance
+// tests run against
+var gitBackends = map[string]func() GitServiceInterface{
+	models.GitBackendExec:   func() GitServiceInterface { return NewGitService() },
+	models.GitBackendNative: func() GitServiceInterface { return NewGoGitService() },
+}
+
+// forEachGitBackend runs test once per backend, each in a fresh test repository
+func forEachGitBackend(t *testing.T, test func(t *testing.T, dir string, git GitServiceInterface)) {
+	for name, newGit := range gitBackends {
+		t.Run(name, func(t *testing.T) {
+			dir := newTestRepo(t)
+			test(t, dir, newGit())
+		})
+	}
+}
+
+func TestGitServiceRepoRoot(t *testing.T) {
+	forEachGitBackend(t, func(t *testing.T, dir string, git GitServiceInterface) {
+		writeTestFile(t, "docs/notes.md", "notes\n")
+		t.Chdir("docs")
+
+		if root, err := git.GetRepoRoot(); err != nil || root != dir {
+			t.Fatalf("GetRepoRoot() = %q, %v, expected %s", root, err, dir)
+		}
+
+		runGit(t, "checkout", "--quiet", "--detach")
+		if branch, err := git.GetCurrentBranch(); err != nil || branch != "HEAD" {
+			t.Fatalf("GetCurrentBranch() = %q, %v on a detached HEAD", branch, err)
+		}
+
+		head := runGit(t, "rev-parse", "HEAD")
+		runGit(t, "tag", "-a", "v1", "-m", "v1")
+		if resolved, err := git.ResolveRef("v1"); err != nil || resolved != head {
+			t.Fatalf("ResolveRef(v1) = %q, %v, expected %s", resolved, err, head)
+		}
+		if _, err := git.ResolveRef("missing"); !errors.Is(err, models.ErrRefNotFound) {
+			t.Fatalf("ResolveRef(missing) error = %v, expected %v", err, models.ErrRefNotFound)
+		}
+	})
+}
+
+func TestGitServiceRepository(t *testing.T) {
+	forEachGitBackend(t, func(t *testing.T, _ string, git GitServiceInterface) {
+		// A new repository in another directory
+		project, err := filepath.EvalSymlinks(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to resolve temp dir: %v", err)
+		}
+		writeTestFile(t, filepath.Join(project, "spec.md"), "# Spec\n")
+		repo := git.At(project)
+		if err := repo.Init(); err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		if root, err := repo.GetRepoRoot(); err != nil || root != project {
+			t.Fatalf("GetRepoRoot() = %q, %v after Init, expected %s", root, err, project)
+		}
+
+		if name, err := git.GetConfig("user.name"); err != nil || name != "Test" {
+			t.Fatalf("GetConfig(user.name) = %q, %v", name, err)
+		}
+		if value, err := git.GetConfig("specify.missing"); err != nil || value != "" {
+			t.Fatalf("GetConfig(specify.missing) = %q, %v", value, err)
+		}
+
+		// Status reports staged, modified and untracked files
+		writeTestFile(t, "README.md", "# changed\n")
+		writeTestFile(t, "specs/001-login/spec.md", "# Login\n")
+		status, err := git.Status()
+		if err != nil {
+			t.Fatalf("Status: %v", err)
+		}
+		expected := []models.GitFileStatus{
+			{Path: "README.md", Staging: ' ', Worktree: 'M'},
+			{Path: "specs/001-login/spec.md", Staging: '?', Worktree: '?'},
+		}
+		if !slices.Equal(status, expected) {
+			t.Fatalf("Status() = %+v, expected %+v", status, expected)
+		}
+
+		diff, err := git.Diff("", "README.md")
+		if err != nil || !strings.Contains(diff, "+# changed") {
+			t.Fatalf("Diff() = %q, %v", diff, err)
+		}
+
+		// Commit the spec only, with an explicit author
+		if err := git.Add("specs"); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		author := &models.GitSignature{Name: "Spec Bot", Email: "bot@example.com"}
+		hash, err := git.Commit("spec(001): create specification", author)
+		if err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		if head := runGit(t, "rev-parse", "HEAD"); hash != head {
+			t.Fatalf("Commit() = %s, HEAD is %s", hash, head)
+		}
+		if status, _ := git.Status(); len(status) != 1 || status[0].Path != "README.md" {
+			t.Fatalf("Status() after commit = %+v", status)
+		}
+
+		commits, err := git.Log("specs/001-login", 0)
+		if err != nil {
+			t.Fatalf("Log: %v", err)
+		}
+		if len(commits) != 1 || commits[0].Hash != hash || commits[0].Author != *author ||
+			commits[0].Subject != "spec(001): create specification" || commits[0].Date.IsZero() {
+			t.Fatalf("Log(specs/001-login) = %+v", commits)
+		}
+		if commits, _ := git.Log("", 1); len(commits) != 1 || commits[0].Hash != hash {
+			t.Fatalf("Log(\"\", 1) = %+v", commits)
+		}
+
+		if err := git.CreateTag("v1", ""); err != nil {
+			t.Fatalf("CreateTag: %v", err)
+		}
+		if err := git.CreateTag("v2", "second release"); err != nil {
+			t.Fatalf("CreateTag: %v", err)
+		}
+		if tags, err := git.ListTags(); err != nil || !slices.Equal(tags, []string{"v1", "v2"}) {
+			t.Fatalf("ListTags() = %v, %v", tags, err)
+		}
+		if resolved, _ := git.ResolveRef("v2"); resolved != hash {
+			t.Fatalf("ResolveRef(v2) = %s, expected %s", resolved, hash)
+		}
+	})
+}
+
+func TestGitServiceBranches(t *testing.T) {
+	forEachGitBackend(t, testGitServiceBranches)
+}
+
+func testGitServiceBranches(t *testing.T, _ string, git GitServiceInterface) {
+
+	runGit(t, "checkout", "--quiet", "-b", "topic")
+	commitTestFile(t, "topic.txt", "topic\n")
+
+	// Branching from main leaves the topic commit behind
+	if err := git.CreateBranch("001-from-main", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if branch, _ := git.GetCurrentBranch(); branch != "001-from-main" {
+		t.Fatalf("current branch is %q, expected 001-from-main", branch)
+	}
+	if _, err := os.Stat("topic.txt"); !os.IsNotExist(err) {
+		t.Fatalf("topic.txt exists on a branch created from main")
+	}
+
+	if err := git.CreateBranch("001-from-main", "main"); err == nil {
+		t.Fatalf("CreateBranch succeeded for an existing branch")
+	}
+
+	f := func(ref string, expected bool) {
+		t.Helper()
+
+		exists, err := git.RefExists(ref)
+		if err != nil {
+			t.Fatalf("RefExists(%q): %v", ref, err)
+		}
+		if exists != expected {
+			t.Fatalf("RefExists(%q) = %v, expected %v", ref, exists, expected)
+		}
+	}
+
+	f("main", true)
+	f("topic", true)
+	f("HEAD~0", true)
+	f("missing", false)
+
+	if exists, _ := git.BranchExists("topic"); !exists {
+		t.Fatalf("BranchExists(topic) = false")
+	}
+	if exists, _ := git.BranchExists("missing"); exists {
+		t.Fatalf("BranchExists(missing) = true")
+	}
+
+	if defaultBranch, err := git.GetDefaultBranch("origin"); err != nil || defaultBranch != "main" {
+		t.Fatalf("GetDefaultBranch(origin) = %q, %v, expected main", defaultBranch, err)
+	}
+	runGit(t, "update-ref", "refs/remotes/upstream/trunk", "HEAD")
+	runGit(t, "symbolic-ref", "refs/remotes/upstream/HEAD", "refs/remotes/upstream/trunk")
+	if defaultBranch, err := git.GetDefaultBranch("upstream"); err != nil || defaultBranch != "trunk" {
+		t.Fatalf("GetDefaultBranch(upstream) = %q, %v, expected trunk", defaultBranch, err)
+	}
+	runGit(t, "update-ref", "-d", "refs/remotes/upstream/HEAD")
+	runGit(t, "update-ref", "-d", "refs/remotes/upstream/trunk")
+
+	if err := git.RenameBranch("topic", "002-topic"); err != nil {
+		t.Fatalf("RenameBranch: %v", err)
+	}
+	branches, err := git.ListBranches()
+	if err != nil {
+		t.Fatalf("ListBranches: %v", err)
+	}
+	slices.Sort(branches)
+	if expected := []string{"001-from-main", "002-topic", "main"}; !slices.Equal(branches, expected) {
+		t.Fatalf("ListBranches() = %v, expected %v", branches, expected)
+	}
+
+	if err := git.DeleteBranch("002-topic", false); err == nil {
+		t.Fatalf("DeleteBranch removed an unmerged branch without force")
+	}
+	if err := git.DeleteBranch("002-topic", true); err != nil {
+		t.Fatalf("DeleteBranch: %v", err)
+	}
+}
+
+func TestGitServiceDirtyAndStash(t *testing.T) {
+	forEachGitBackend(t, testGitServiceDirtyAndStash)
+}
+
+func testGitServiceDirtyAndStash(t *testing.T, _ string, git GitServiceInterface) {
+
+	f := func(name string, expected bool) {
+		t.Helper()
+
+		dirty, err := git.IsDirty()
+		if err != nil {
+			t.Fatalf("%s: IsDirty: %v", name, err)
+		}
+		if dirty != expected {
+			t.Fatalf("%s: IsDirty() = %v, expected %v", name, dirty, expected)
+		}
+	}
+
+	f("clean", false)
+
+	writeTestFile(t, "notes.txt", "untracked\n")
+	f("untracked file", false)
+
+	writeTestFile(t, "README.md", "# changed\n")
+	f("modified file", true)
+
+	if err := git.Stash("test stash"); err != nil {
+		t.Fatalf("Stash: %v", err)
+	}
+	f("after stash", false)
+
+	if list := runGit(t, "stash", "list"); !strings.Contains(list, "test stash") {
+		t.Fatalf("stash list %q does not contain the stash message", list)
+	}
+}
+
+func TestGitServiceTreeAndWorktrees(t *testing.T) {
+	forEachGitBackend(t, testGitServiceTreeAndWorktrees)
+}
+
+func testGitServiceTreeAndWorktrees(t *testing.T, dir string, git GitServiceInterface) {
+
+	commitTestFile(t, "specs/001-login/spec.md", "# Login\n")
+	commitTestFile(t, "specs/002-search/spec.md", "# Search\n")
+
+	names, err := git.ListTree("main", "specs")
+	if err != nil {
+		t.Fatalf("ListTree: %v", err)
+	}
+	if expected := []string{"001-login", "002-search"}; !slices.Equal(names, expected) {
+		t.Fatalf("ListTree() = %v, expected %v", names, expected)
+	}
+	if _, err := git.ListTree("main", "missing"); err == nil {
+		t.Fatalf("ListTree succeeded for a missing directory")
+	}
+
+	path := filepath.Join(dir, "worktrees", "003-export")
+	if err := git.AddWorktree(path, "003-export", "main~1"); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "specs", "002-search")); !os.IsNotExist(err) {
+		t.Fatalf("worktree was not created from main~1")
+	}
+
+	worktrees, err := git.ListWorktrees()
+	if err != nil {
+		t.Fatalf("ListWorktrees: %v", err)
+	}
+	if len(worktrees) != 2 || worktrees[0].Path != dir || worktrees[1].Path != path || worktrees[1].Branch != "003-export" {
+		t.Fatalf("ListWorktrees() = %+v", worktrees)
+	}
+
+	if err := git.RemoveWorktree(path, false); err != nil {
+		t.Fatalf("RemoveWorktree: %v", err)
+	}
+	if worktrees, _ = git.ListWorktrees(); len(worktrees) != 1 {
+		t.Fatalf("worktree still listed after removal: %+v", worktrees)
+	}
+}
+
+func TestGitServiceRemoteRefs(t *testing.T) {
+	forEachGitBackend(t, testGitServiceRemoteRefs)
+}
+
+func testGitServiceRemoteRefs(t *testing.T, dir string, git GitServiceInterface) {
+
+	remote := filepath.Join(dir, "remote.git")
+	runGit(t, "init", "--quiet", "--bare", remote)
+	runGit(t, "remote", "add", "origin", remote)
+
+	ref := models.ReservationRefPrefix + "001"
+	if err := git.PushNewRef("origin", ref, "reserve 001"); err != nil {
+		t.Fatalf("PushNewRef: %v", err)
+	}
+	if err := git.PushNewRef("origin", ref, "reserve 001"); err == nil {
+		t.Fatalf("PushNewRef succeeded for an existing ref")
+	}
+
+	refs, err := git.ListRemoteRefs("origin", models.ReservationRefPrefix)
+	if err != nil {
+		t.Fatalf("ListRemoteRefs: %v", err)
+	}
+	if !slices.Equal(refs, []string{ref}) {
+		t.Fatalf("ListRemoteRefs() = %v, expected [%s]", refs, ref)
+	}
+}
+
+func TestCreateFeatureSafety(t *testing.T) {
+	forEachGitBackend(t, testCreateFeatureSafety)
+}
+
+func testCreateFeatureSafety(t *testing.T, _ string, git GitServiceInterface) {
+	config := models.DefaultProjectConfig()
+	if err := config.Set("branch.pattern", "{ticket}-{slug}"); err != nil {
+		t.Fatalf("failed to set branch.pattern: %v", err)
+	}
+	feature := NewFeatureService(NewFilesystemService(), git, config)
+
+	runGit(t, "checkout", "--quiet", "-b", "topic")
+	commitTestFile(t, "topic.txt", "topic\n")
+
+	f := func(name string, opts FeatureCreateOptions, expectedErr error) *models.FeatureCreateResult {
+		t.Helper()
+
+		result, err := feature.CreateFeature(opts)
+		if expectedErr != nil {
+			if !errors.Is(err, expectedErr) {
+				t.Fatalf("%s: got error %v, expected %v", name, err, expectedErr)
+			}
+			return nil
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		return result
+	}
+
+	// The default branch is the default base
+	result := f("default base", FeatureCreateOptions{Description: "login page", Ticket: "APP-1"}, nil)
+	if result.BranchName != "APP-1-login-page" || result.BaseRef != "main" || len(result.Warnings) != 0 {
+		t.Fatalf("default base: got branch %q from %q", result.BranchName, result.BaseRef)
+	}
+	if _, err := os.Stat("topic.txt"); !os.IsNotExist(err) {
+		t.Fatalf("default base: feature branch contains topic.txt")
+	}
+
+	result = f("explicit base", FeatureCreateOptions{Description: "search", Ticket: "APP-2", From: "topic"}, nil)
+	if _, err := os.Stat("topic.txt"); err != nil || result.BaseRef != "topic" {
+		t.Fatalf("explicit base: branch was not created from topic")
+	}
+
+	f("missing base", FeatureCreateOptions{Description: "export", Ticket: "APP-3", From: "missing"}, models.ErrRefNotFound)
+	f("existing branch", FeatureCreateOptions{Description: "login page", Ticket: "APP-1"}, models.ErrBranchExists)
+
+	writeTestFile(t, "topic.txt", "changed\n")
+	f("dirty tree", FeatureCreateOptions{Description: "export", Ticket: "APP-3"}, models.ErrWorkingTreeDirty)
+	if branch := runGit(t, "rev-parse", "--abbrev-ref", "HEAD"); branch != "APP-2-search" {
+		t.Fatalf("dirty tree: switched to %s", branch)
+	}
+
+	result = f("stash", FeatureCreateOptions{Description: "export", Ticket: "APP-3", Stash: true}, nil)
+	if !result.Stashed || runGit(t, "stash", "list") == "" {
+		t.Fatalf("stash: changes were not stashed")
+	}
+
+	// Without a default branch the feature starts from HEAD, with a warning
+	runGit(t, "branch", "-m", "main", "trunk")
+	result = f("no default branch", FeatureCreateOptions{Description: "import", Ticket: "APP-4"}, nil)
+	if result.BaseRef != "" || len(result.Warnings) != 1 {
+		t.Fatalf("no default branch: got base %q and warnings %v", result.BaseRef, result.Warnings)
+	}
+}
+
+func TestGoGitServiceWithoutGit(t *testing.T) {
+	dir := newTestRepo(t)
+	t.Setenv("PATH", t.TempDir())
+
+	git := NewGoGitService()
+	if branch, err := git.GetCurrentBranch(); err != nil || branch != "main" {
+		t.Fatalf("GetCurrentBranch() = %q, %v", branch, err)
+	}
+
+	f := func(name string, err error) {
+		t.Helper()
+		if !errors.Is(err, models.ErrToolNotFound) || !strings.Contains(err.Error(), "requires the git binary") {
+			t.Errorf("%s: got %v, expected ErrToolNotFound", name, err)
+		}
+	}
+
+	f("Stash", git.Stash("test"))
+	f("RenameBranch", git.RenameBranch("main", "trunk"))
+	f("DeleteBranch", git.DeleteBranch("main", false))
+	_, err := git.Diff("HEAD")
+	f("Diff", err)
+	_, err = git.ListRemoteRefs("origin", models.ReservationRefPrefix)
+	f("ListRemoteRefs", err)
+	f("PushNewRef", git.PushNewRef("origin", models.ReservationRefPrefix+"001", "reserve"))
+	f("DeleteRemoteRef", git.DeleteRemoteRef("origin", models.ReservationRefPrefix+"001"))
+	f("AddWorktree", git.AddWorktree(filepath.Join(dir, "wt"), "002-x", ""))
+	_, err = git.ListWorktrees()
+	f("ListWorktrees", err)
+	f("RemoveWorktree", git.RemoveWorktree(filepath.Join(dir, "wt"), false))
+}
+
+func TestCreateFeatureReservation(t *testing.T) {
+	forEachGitBackend(t, testCreateFeatureReservation)
+}
+
+func testCreateFeatureReservation(t *testing.T, dir string, git GitServiceInterface) {
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, "init", "--quiet", "--bare", remote)
+	runGit(t, "remote", "add", "origin", remote)
+	feature := NewFeatureService(NewFilesystemService(), git, models.DefaultProjectConfig())
+
+	reservations := func() []string {
+		t.Helper()
+		refs := runGit(t, "ls-remote", "--refs", "origin", models.ReservationRefPrefix+"*")
+		var names []string
+		for line := range strings.Lines(refs) {
+			names = append(names, strings.TrimPrefix(strings.Fields(line)[1], models.ReservationRefPrefix))
+		}
+		return names
+	}
+
+	if _, err := feature.CreateFeature(FeatureCreateOptions{Description: "login", Reserve: true}); err != nil {
+		t.Fatalf("CreateFeature: %v", err)
+	}
+	if got := reservations(); !slices.Equal(got, []string{"001"}) {
+		t.Fatalf("reservations = %v, expected [001]", got)
+	}
+	runGit(t, "checkout", "--quiet", "main")
+
+	// A failure after reserving releases the number
+	if err := os.MkdirAll(filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-worktrees", "002-export"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := feature.CreateFeature(FeatureCreateOptions{Description: "export", Reserve: true, Worktree: true}); err == nil {
+		t.Fatalf("CreateFeature succeeded with an existing worktree directory")
+	}
+	if got := reservations(); !slices.Equal(got, []string{"001"}) {
+		t.Fatalf("reservations = %v after a failed create, expected [001]", got)
+	}
+
+	// So does a failed renumber
+	runGit(t, "checkout", "--quiet", "001-login")
+	failing := NewFeatureService(NewFilesystemService(), renameFailingGit{git}, models.DefaultProjectConfig())
+	if _, err := failing.RenumberFeature(FeatureRenumberOptions{Number: 5, Reserve: true}); err == nil {
+		t.Fatalf("RenumberFeature succeeded although the branch could not be renamed")
+	}
+	if got := reservations(); !slices.Equal(got, []string{"001"}) {
+		t.Fatalf("reservations = %v after a failed renumber, expected [001]", got)
+	}
+}
+
+// renameFailingGit is a git service that cannot rename branches
+type renameFailingGit struct {
+	GitServiceInterface
+}
+
+func (renameFailingGit) RenameBranch(oldName, newName string) error {
+	return errors.New("rename failed")
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"os"
 	"strings"
 	"time"
 
@@ -17,6 +20,16 @@ type GitHubService struct {
 	baseURL   string
 	repoOwner string
 	repoName  string
+	token     string // sent with API requests
+	source    string // where the token was found, e.g. GITHUB_TOKEN
+	userAgent string
+	cache     *gitHubCache // ETag cache of API responses; nil disables it
+	sleep     func(time.Duration)
+
+	downloadClient *http.Client // client for release assets, with a longer timeout
+	downloadDir    string       // where partial downloads are kept to resume them; empty uses the target directory
+	host           string       // GitHub server host, e.g. github.com
+	mirror         string       // replaces https://<host> in release asset URLs when set
 }
 
 // GitHubRelease represents a GitHub release from the API
@@ -44,44 +57,116 @@ type GitHubAsset struct {
 	UpdatedAt          time.Time `json:"updated_at"`
 }
 
+// defaultUserAgent identifies requests when no versioned agent is set
+const defaultUserAgent = "specify-cli"
+
 // NewGitHubService creates a new GitHub service instance
 func NewGitHubService() *GitHubService {
 	return &GitHubService{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		baseURL:   "https://api.github.com",
+		baseURL:   models.DefaultGitHubAPIURL,
 		repoOwner: "euforicio",
 		repoName:  "spec-kit",
+		userAgent: defaultUserAgent,
+		sleep:     time.Sleep,
+		downloadClient: &http.Client{
+			Timeout: models.DefaultDownloadTimeout,
+		},
+	}
+}
+
+// NewGitHubServiceWithConfig creates a GitHub service for the configured
+// template repository, API server, HTTP timeouts, proxy, CA bundle and asset
+// mirror. It authenticates with the token found by ResolveGitHubToken and
+// caches API responses on disk.
+func NewGitHubServiceWithConfig(config *models.ProjectConfig) (*GitHubService, error) {
+	client, err := NewHTTPClient(config, config.GetHTTPTimeout())
+	if err != nil {
+		return nil, err
+	}
+	// Downloads share the transport and only wait longer
+	downloadClient := *client
+	downloadClient.Timeout = config.GetDownloadTimeout()
+	token, source := ResolveGitHubToken(config.GetGitHubHost(), os.Getenv)
+
+	service := &GitHubService{
+		client:         client,
+		baseURL:        config.GetGitHubAPIURL(),
+		repoOwner:      config.GetGitHubOwner(),
+		repoName:       config.GetGitHubRepo(),
+		token:          token,
+		source:         source,
+		userAgent:      defaultUserAgent,
+		sleep:          time.Sleep,
+		downloadClient: &downloadClient,
+		downloadDir:    userCacheDir("downloads"),
+		host:           config.GetGitHubHost(),
+		mirror:         config.GetMirror(),
+	}
+	if dir := userCacheDir("github"); dir != "" {
+		service.cache = &gitHubCache{dir: dir}
 	}
+	return service, nil
 }
 
 // NewGitHubServiceWithClient creates a new GitHub service with a custom HTTP client
 func NewGitHubServiceWithClient(client *http.Client, repoOwner, repoName string) *GitHubService {
 	return &GitHubService{
 		client:    client,
-		baseURL:   "https://api.github.com",
+		baseURL:   models.DefaultGitHubAPIURL,
 		repoOwner: repoOwner,
 		repoName:  repoName,
+		userAgent: defaultUserAgent,
+		sleep:     time.Sleep,
+
+		downloadClient: client,
+	}
+}
+
+// SetToken sets the token used for authenticated API requests
+func (g *GitHubService) SetToken(token string) {
+	g.token, g.source = strings.TrimSpace(token), ""
+}
+
+// TokenSource returns where the token was found, e.g. GITHUB_TOKEN, or an
+// empty string for anonymous requests
+func (g *GitHubService) TokenSource() string {
+	if g.token == "" {
+		return ""
 	}
+	if g.source == "" {
+		return "token"
+	}
+	return g.source
+}
+
+// SetUserAgent sets the User-Agent of requests, e.g. specify-cli/1.4.0
+func (g *GitHubService) SetUserAgent(userAgent string) {
+	g.userAgent = userAgent
 }
 
 // GetLatestRelease fetches the latest release from the GitHub repository
 func (g *GitHubService) GetLatestRelease() (*GitHubRelease, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", g.baseURL, g.repoOwner, g.repoName)
+	return g.getRelease(fmt.Sprintf("%s/repos/%s/%s/releases/latest", g.baseURL, g.repoOwner, g.repoName))
+}
+
+// GetReleaseByTag fetches the release of a tag from the GitHub repository
+func (g *GitHubService) GetReleaseByTag(tag string) (*GitHubRelease, error) {
+	return g.getRelease(fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", g.baseURL, g.repoOwner, g.repoName, neturl.PathEscape(tag)))
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// getRelease fetches a release from a releases API URL
+func (g *GitHubService) getRelease(url string) (*GitHubRelease, error) {
+	req, err := g.newRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set User-Agent header for better API interaction
-	req.Header.Set("User-Agent", "specify-cli/1.0.0")
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := g.client.Do(req)
+	resp, err := g.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", models.ErrTemplateDownloadFailed, err)
+		return nil, fmt.Errorf("%w: %w", models.ErrTemplateDownloadFailed, err)
 	}
 	defer resp.Body.Close()
 
@@ -169,7 +254,7 @@ func (g *GitHubService) DownloadAsset(asset *GitHubAsset, writer io.Writer) erro
 		return fmt.Errorf("%w: asset cannot be nil", models.ErrTemplateDownloadFailed)
 	}
 
-	req, err := http.NewRequest("GET", asset.BrowserDownloadURL, nil)
+	req, err := g.newRequest("GET", g.mirrorURL(asset.BrowserDownloadURL), nil)
 	if err != nil {
 		return fmt.Errorf(
 			"%w: failed to create download request: %v",
@@ -178,11 +263,9 @@ func (g *GitHubService) DownloadAsset(asset *GitHubAsset, writer io.Writer) erro
 		)
 	}
 
-	req.Header.Set("User-Agent", "specify-cli/1.0.0")
-
-	resp, err := g.client.Do(req)
+	resp, err := g.do(req)
 	if err != nil {
-		return fmt.Errorf("%w: failed to download asset: %v", models.ErrTemplateDownloadFailed, err)
+		return fmt.Errorf("%w: failed to download asset: %w", models.ErrTemplateDownloadFailed, err)
 	}
 	defer resp.Body.Close()
 
@@ -209,13 +292,11 @@ func (g *GitHubService) DownloadAsset(asset *GitHubAsset, writer io.Writer) erro
 
 // CheckConnectivity tests if GitHub API is accessible
 func (g *GitHubService) CheckConnectivity() error {
-	req, err := http.NewRequest("GET", g.baseURL, nil)
+	req, err := g.newRequest("GET", g.baseURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create connectivity test request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "specify-cli/1.0.0")
-
 	resp, err := g.client.Do(req)
 	if err != nil {
 		return fmt.Errorf(
@@ -245,14 +326,12 @@ func (g *GitHubService) CheckConnectivity() error {
 func (g *GitHubService) GetRateLimitInfo() (limit, remaining int, resetTime time.Time, err error) {
 	url := fmt.Sprintf("%s/rate_limit", g.baseURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := g.newRequest("GET", url, nil)
 	if err != nil {
 		return 0, 0, time.Time{}, fmt.Errorf("failed to create rate limit request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "specify-cli/1.0.0")
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
+	// Not retried or cached: the rate limit endpoint is free and always current
 	resp, err := g.client.Do(req)
 	if err != nil {
 		return 0, 0, time.Time{}, fmt.Errorf("failed to get rate limit info: %w", err)
@@ -282,3 +361,219 @@ func (g *GitHubService) GetRateLimitInfo() (limit, remaining int, resetTime time
 		time.Unix(rateLimitResp.Resources.Core.Reset, 0),
 		nil
 }
+
+// CreatePullRequest opens a pull request on repo and returns it with its number
+// and URL filled in. The head branch must already be pushed.
+func (g *GitHubService) CreatePullRequest(repo models.GitHubRepository, pr *models.PullRequest) (*models.PullRequest, error) {
+	if g.token == "" {
+		return nil, fmt.Errorf("%w: set GITHUB_TOKEN or GH_TOKEN, or log in with 'gh auth login', to create pull requests", models.ErrGitHubAuthRequired)
+	}
+
+	payload := map[string]any{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+		"draft": pr.Draft,
+	}
+	var response struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls", g.baseURL, repo.Owner, repo.Name)
+	if err := g.apiJSON("POST", url, payload, http.StatusCreated, &response,
+		"creating a pull request on "+repo.String()); err != nil {
+		return nil, err
+	}
+
+	created := *pr
+	created.Number, created.URL = response.Number, response.HTMLURL
+	return &created, nil
+}
+
+// gitHubIssue is an issue as returned by the API
+type gitHubIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// issue converts the API representation to models.Issue
+func (i gitHubIssue) issue(repo models.GitHubRepository) *models.Issue {
+	issue := &models.Issue{
+		Repository: repo,
+		Number:     i.Number,
+		Title:      strings.TrimSpace(i.Title),
+		Body:       strings.TrimSpace(strings.ReplaceAll(i.Body, "\r\n", "\n")),
+		State:      i.State,
+		URL:        i.HTMLURL,
+	}
+	for _, label := range i.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return issue
+}
+
+// GetIssue fetches an issue with its labels. The token is optional but needed
+// for private repositories.
+func (g *GitHubService) GetIssue(repo models.GitHubRepository, number int) (*models.Issue, error) {
+	var response gitHubIssue
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", g.baseURL, repo.Owner, repo.Name, number)
+	if err := g.apiJSON("GET", url, nil, http.StatusOK, &response,
+		fmt.Sprintf("fetching issue %s#%d", repo, number)); err != nil {
+		return nil, err
+	}
+	if response.PullRequest != nil {
+		return nil, fmt.Errorf("%w: %s#%d is a pull request, not an issue", models.ErrGitHubRequest, repo, number)
+	}
+	response.Number = number
+	return response.issue(repo), nil
+}
+
+// ListIssues returns the open and closed issues of repo carrying label,
+// without pull requests
+func (g *GitHubService) ListIssues(repo models.GitHubRepository, label string) ([]models.Issue, error) {
+	const perPage = 100
+
+	issues := []models.Issue{}
+	for page := 1; ; page++ {
+		var response []gitHubIssue
+		url := fmt.Sprintf("%s/repos/%s/%s/issues?state=all&labels=%s&per_page=%d&page=%d",
+			g.baseURL, repo.Owner, repo.Name, neturl.QueryEscape(label), perPage, page)
+		if err := g.apiJSON("GET", url, nil, http.StatusOK, &response, "listing issues of "+repo.String()); err != nil {
+			return nil, err
+		}
+
+		for _, issue := range response {
+			if issue.PullRequest == nil {
+				issues = append(issues, *issue.issue(repo))
+			}
+		}
+		if len(response) < perPage {
+			return issues, nil
+		}
+	}
+}
+
+// CreateIssue opens an issue with the title, body and labels of issue and
+// returns it with its number and URL filled in
+func (g *GitHubService) CreateIssue(repo models.GitHubRepository, issue *models.Issue) (*models.Issue, error) {
+	if g.token == "" {
+		return nil, fmt.Errorf("%w: set GITHUB_TOKEN or GH_TOKEN, or log in with 'gh auth login', to create issues", models.ErrGitHubAuthRequired)
+	}
+
+	payload := map[string]any{
+		"title":  issue.Title,
+		"body":   issue.Body,
+		"labels": issue.Labels,
+	}
+	var response gitHubIssue
+	url := fmt.Sprintf("%s/repos/%s/%s/issues", g.baseURL, repo.Owner, repo.Name)
+	if err := g.apiJSON("POST", url, payload, http.StatusCreated, &response,
+		"creating an issue on "+repo.String()); err != nil {
+		return nil, err
+	}
+	return response.issue(repo), nil
+}
+
+// UpdateIssue replaces the title, body, labels and state of the issue
+func (g *GitHubService) UpdateIssue(repo models.GitHubRepository, issue *models.Issue) (*models.Issue, error) {
+	if g.token == "" {
+		return nil, fmt.Errorf("%w: set GITHUB_TOKEN or GH_TOKEN, or log in with 'gh auth login', to update issues", models.ErrGitHubAuthRequired)
+	}
+
+	payload := map[string]any{
+		"title":  issue.Title,
+		"body":   issue.Body,
+		"labels": issue.Labels,
+	}
+	if issue.State != "" {
+		payload["state"] = issue.State
+	}
+	var response gitHubIssue
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", g.baseURL, repo.Owner, repo.Name, issue.Number)
+	if err := g.apiJSON("PATCH", url, payload, http.StatusOK, &response,
+		fmt.Sprintf("updating issue %s#%d", repo, issue.Number)); err != nil {
+		return nil, err
+	}
+	return response.issue(repo), nil
+}
+
+// apiJSON sends an API request with an optional JSON payload and decodes the
+// response into result. action describes the request in errors, e.g. "creating
+// an issue on acme/widgets".
+func (g *GitHubService) apiJSON(method, url string, payload any, expected int, result any, action string) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := g.newRequest(method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", models.ErrGitHubRequest, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		message := gitHubErrorMessage(resp.Body)
+		if resp.StatusCode == http.StatusNotFound && g.token == "" {
+			message += "; set GITHUB_TOKEN or GH_TOKEN, or log in with 'gh auth login', for private repositories"
+		}
+		return fmt.Errorf("%w: %s returned status %d: %s", models.ErrGitHubRequest, action, resp.StatusCode, message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("%w: failed to parse GitHub API response: %v", models.ErrGitHubRequest, err)
+	}
+	return nil
+}
+
+// gitHubErrorMessage extracts the message and validation errors of an API error
+// response, falling back to the raw body
+func gitHubErrorMessage(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, 64<<10))
+
+	var apiError struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &apiError); err != nil || apiError.Message == "" {
+		return strings.TrimSpace(string(data))
+	}
+
+	details := []string{}
+	for _, e := range apiError.Errors {
+		switch {
+		case e.Message != "":
+			details = append(details, e.Message)
+		case e.Field != "":
+			details = append(details, e.Field+" "+e.Code)
+		}
+	}
+	if len(details) == 0 {
+		return apiError.Message
+	}
+	return apiError.Message + " (" + strings.Join(details, "; ") + ")"
+}
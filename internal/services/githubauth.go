@@ -0,0 +1,60 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables holding a GitHub token, in order of precedence
+var gitHubTokenEnvVars = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+
+// ResolveGitHubToken returns a token for the GitHub server at host (github.com
+// or a GitHub Enterprise host) and where it was found: GITHUB_TOKEN, GH_TOKEN,
+// or the gh CLI's hosts.yml. It returns empty strings when there is none, e.g.
+// when gh keeps its token in the system keyring. host must come from the user:
+// github.api_url cannot be set by a project config, so a repository cannot
+// direct the token to its own server.
+func ResolveGitHubToken(host string, getenv func(string) string) (token, source string) {
+	for _, name := range gitHubTokenEnvVars {
+		if token := strings.TrimSpace(getenv(name)); token != "" {
+			return token, name
+		}
+	}
+
+	hostsFile := filepath.Join(ghConfigDir(getenv), "hosts.yml")
+	data, err := os.ReadFile(hostsFile)
+	if err != nil {
+		return "", ""
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", ""
+	}
+	if token := strings.TrimSpace(hosts[host].OAuthToken); token != "" {
+		return token, hostsFile
+	}
+	return "", ""
+}
+
+// ghConfigDir returns the gh CLI configuration directory: $GH_CONFIG_DIR,
+// $XDG_CONFIG_HOME/gh, %AppData%\GitHub CLI on Windows, or ~/.config/gh
+func ghConfigDir(getenv func(string) string) string {
+	if dir := getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	if dir := getenv("AppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "GitHub CLI")
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "gh")
+}
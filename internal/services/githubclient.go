@@ -0,0 +1,233 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// Retry policy for GitHub requests
+const (
+	gitHubMaxRetries = 3
+	gitHubRetryDelay = time.Second // first wait after a server error, doubled on each retry
+	gitHubMaxWait    = time.Minute // longer rate-limit waits fail instead
+)
+
+// gitHubCache stores API responses with their ETag so repeated requests can be
+// conditional. A 304 Not Modified does not count against the rate limit of
+// authenticated requests.
+type gitHubCache struct {
+	dir string
+}
+
+// gitHubCacheEntry is a cached response body and its ETag
+type gitHubCacheEntry struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+// userCacheDir returns a directory under the user cache directory, e.g.
+// ~/.cache/specify/github, or an empty string when there is none
+func userCacheDir(name string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "specify", name)
+}
+
+// path returns the cache file of a request. The token is part of the key so
+// responses of private repositories are never served to another token.
+func (c *gitHubCache) path(url, token string) string {
+	sum := sha256.Sum256([]byte(url + "\x00" + token))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *gitHubCache) load(url, token string) *gitHubCacheEntry {
+	data, err := os.ReadFile(c.path(url, token))
+	if err != nil {
+		return nil
+	}
+	var entry gitHubCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.ETag == "" {
+		return nil
+	}
+	return &entry
+}
+
+// store writes an entry; the cache is an optimization, so failures are ignored
+func (c *gitHubCache) store(url, token string, entry *gitHubCacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil || os.MkdirAll(c.dir, 0o755) != nil {
+		return
+	}
+	path := c.path(url, token)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err == nil {
+		os.Rename(path+".tmp", path)
+	}
+}
+
+// idempotentMethods are retried after a server error
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// isAPIURL reports whether target is on the API server: same scheme and host
+// as baseURL, under its path
+func (g *GitHubService) isAPIURL(target *neturl.URL) bool {
+	base, err := neturl.Parse(g.baseURL)
+	if err != nil {
+		return false
+	}
+	if !strings.EqualFold(target.Scheme, base.Scheme) || !strings.EqualFold(target.Host, base.Host) {
+		return false
+	}
+	basePath := strings.TrimSuffix(base.Path, "/")
+	return target.Path == basePath || strings.HasPrefix(target.Path, basePath+"/")
+}
+
+// newRequest creates a request with the client headers. The token is only
+// sent to the API server, never to download hosts.
+func (g *GitHubService) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", g.userAgent)
+	if g.isAPIURL(req.URL) {
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		if g.token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.token)
+		}
+	}
+	return req, nil
+}
+
+// do sends a request. API GETs are made conditional on a cached ETag and a 304
+// is answered from the cache. Rate-limited requests wait for Retry-After or
+// X-RateLimit-Reset and server errors on idempotent requests back off
+// exponentially, up to gitHubMaxRetries times; a POST is not replayed after a
+// server error since it may have taken effect. A rate limit that resets later than gitHubMaxWait
+// fails with ErrGitHubRateLimited.
+func (g *GitHubService) do(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	cacheable := g.cache != nil && req.Method == http.MethodGet && g.isAPIURL(req.URL)
+
+	var cached *gitHubCacheEntry
+	if cacheable {
+		if cached = g.cache.load(url, g.token); cached != nil {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
+	delay := gitHubRetryDelay
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, retry, limited := g.retryWait(req.Method, resp, delay)
+		if !retry {
+			return g.cachedResponse(resp, cacheable, cached, url)
+		}
+		if attempt == gitHubMaxRetries || wait > gitHubMaxWait {
+			if !limited {
+				return resp, nil // the caller reports the server error
+			}
+			resp.Body.Close()
+			return nil, g.rateLimitError(resp)
+		}
+
+		resp.Body.Close()
+		g.sleep(wait)
+		delay *= 2
+	}
+}
+
+// retryWait decides whether a response to a method is retried and after how
+// long. limited reports a primary or secondary rate limit.
+func (g *GitHubService) retryWait(method string, resp *http.Response, delay time.Duration) (wait time.Duration, retry, limited bool) {
+	retryAfter, hasRetryAfter := time.Duration(0), false
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		retryAfter, hasRetryAfter = time.Duration(seconds)*time.Second, true
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && (hasRetryAfter || resp.Header.Get("X-RateLimit-Remaining") == "0")):
+		if hasRetryAfter {
+			return retryAfter, true, true
+		}
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Until(time.Unix(reset, 0))+time.Second, time.Second), true, true
+		}
+		return delay, true, true
+	case !idempotentMethods[method]:
+		return 0, false, false
+	case resp.StatusCode == http.StatusInternalServerError || resp.StatusCode == http.StatusBadGateway ||
+		resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout:
+		if hasRetryAfter {
+			return retryAfter, true, false
+		}
+		return delay, true, false
+	}
+	return 0, false, false
+}
+
+// rateLimitError explains an exhausted rate limit
+func (g *GitHubService) rateLimitError(resp *http.Response) error {
+	message := "GitHub API rate limit exceeded"
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		message += ", resets at " + time.Unix(reset, 0).Format("15:04:05")
+	}
+	if g.token == "" {
+		message += "; set GITHUB_TOKEN or GH_TOKEN, or log in with 'gh auth login', for a higher limit"
+	}
+	return fmt.Errorf("%w: %s", models.ErrGitHubRateLimited, message)
+}
+
+// cachedResponse answers a 304 from the cache and stores new ETag responses
+func (g *GitHubService) cachedResponse(resp *http.Response, cacheable bool, cached *gitHubCacheEntry, url string) (*http.Response, error) {
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		resp.StatusCode, resp.Status = http.StatusOK, "200 OK"
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+	case resp.StatusCode == http.StatusOK && cacheable && resp.Header.Get("ETag") != "":
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		g.cache.store(url, g.token, &gitHubCacheEntry{ETag: resp.Header.Get("ETag"), Body: data})
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	return resp, nil
+}
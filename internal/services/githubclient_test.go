@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestResolveGitHubToken(t *testing.T) {
+	configDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(configDir, "hosts.yml"), []byte(`github.com:
+    user: octocat
+    oauth_token: gho_from_gh
+github.example.com:
+    user: octocat
+`), 0o644); err != nil {
+		t.Fatalf("failed to write hosts.yml: %v", err)
+	}
+
+	f := func(host string, env map[string]string, expectedToken, expectedSource string) {
+		t.Helper()
+
+		env["GH_CONFIG_DIR"] = configDir
+		token, source := ResolveGitHubToken(host, func(name string) string { return env[name] })
+		if token != expectedToken || source != expectedSource {
+			t.Fatalf("ResolveGitHubToken(%q, %v) = %q, %q, expected %q, %q", host, env, token, source, expectedToken, expectedSource)
+		}
+	}
+
+	f("github.com", map[string]string{"GITHUB_TOKEN": "ghp_env", "GH_TOKEN": "ghp_gh"}, "ghp_env", "GITHUB_TOKEN")
+	f("github.com", map[string]string{"GH_TOKEN": "ghp_gh"}, "ghp_gh", "GH_TOKEN")
+	f("github.com", map[string]string{}, "gho_from_gh", filepath.Join(configDir, "hosts.yml"))
+	f("github.example.com", map[string]string{}, "", "") // token kept in the keyring
+}
+
+func TestGitHubClient(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/repos/acme/widgets/issues/1":
+			if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("User-Agent") != "specify-cli/1.2.3" {
+				t.Errorf("unexpected headers %v", r.Header)
+			}
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"number":1,"title":"Cached","state":"open"}`))
+		case "/repos/acme/widgets/issues/2":
+			// Secondary rate limit on the first attempt, a server error on the second
+			switch requests {
+			case 1:
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(http.StatusForbidden)
+			case 2:
+				w.WriteHeader(http.StatusBadGateway)
+			default:
+				w.Write([]byte(`{"number":2,"title":"Retried","state":"open"}`))
+			}
+		case "/repos/acme/widgets/issues/3":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+		case "/repos/acme/widgets/issues":
+			// The issue may have been created before the gateway timed out
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case "/download":
+			if r.Header.Get("Authorization") != "" {
+				t.Errorf("token sent to a download host")
+			}
+		}
+	}))
+	defer server.Close()
+
+	waits := []time.Duration{}
+	github := NewGitHubServiceWithClient(server.Client(), "acme", "widgets")
+	github.cache = &gitHubCache{dir: t.TempDir()}
+	github.sleep = func(wait time.Duration) { waits = append(waits, wait) }
+	github.SetToken("secret")
+	github.SetUserAgent("specify-cli/1.2.3")
+	repo := models.GitHubRepository{Owner: "acme", Name: "widgets"}
+	github.baseURL = server.URL
+
+	// The second request is conditional and answered from the cache
+	for range 2 {
+		issue, err := github.GetIssue(repo, 1)
+		if err != nil || issue.Title != "Cached" {
+			t.Fatalf("GetIssue(1) = %+v, %v", issue, err)
+		}
+	}
+
+	requests = 0
+	issue, err := github.GetIssue(repo, 2)
+	if err != nil || issue.Title != "Retried" {
+		t.Fatalf("GetIssue(2) = %+v, %v", issue, err)
+	}
+	if !slices.Equal(waits, []time.Duration{7 * time.Second, 2 * time.Second}) {
+		t.Fatalf("waited %v, expected Retry-After then backoff", waits)
+	}
+
+	// A limit resetting in an hour fails instead of waiting
+	if _, err := github.GetIssue(repo, 3); !errors.Is(err, models.ErrGitHubRateLimited) || len(waits) != 2 {
+		t.Fatalf("GetIssue(3) = %v after waiting %v", err, waits)
+	}
+
+	// A POST is not replayed after a server error
+	requests, waits = 0, waits[:0]
+	if _, err := github.CreateIssue(repo, &models.Issue{Title: "Once"}); err == nil || requests != 1 || len(waits) != 0 {
+		t.Fatalf("CreateIssue = %v after %d requests", err, requests)
+	}
+
+	// Downloads from other hosts never carry the token
+	download := "http://localhost:" + strconv.Itoa(server.Listener.Addr().(*net.TCPAddr).Port) + "/download"
+	if err := github.DownloadAsset(&GitHubAsset{BrowserDownloadURL: download}, io.Discard); err != nil {
+		t.Fatalf("DownloadAsset: %v", err)
+	}
+}
+
+func TestGitHubIsAPIURL(t *testing.T) {
+	github := NewGitHubServiceWithClient(http.DefaultClient, "acme", "widgets")
+	github.baseURL = "https://github.example.com/api/v3"
+
+	f := func(target string, expected bool) {
+		t.Helper()
+		parsed, err := url.Parse(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := github.isAPIURL(parsed); got != expected {
+			t.Errorf("isAPIURL(%s) = %v, expected %v", target, got, expected)
+		}
+	}
+
+	f("https://github.example.com/api/v3", true)
+	f("https://github.example.com/api/v3/repos/acme/widgets", true)
+	f("https://GITHUB.example.com/api/v3/user", true)
+	f("https://github.example.com.evil.example/api/v3/repos", false)
+	f("https://github.example.com/api/v30/repos", false)
+	f("http://github.example.com/api/v3/repos", false)
+	f("https://github.example.com/acme/widgets/releases/download/v1/a.zip", false)
+}
@@ -0,0 +1,666 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// GoGitService reads repositories, creates branches and commits with a pure-Go
+// git implementation, without spawning processes. Operations it does not
+// implement natively (stash, diff, worktrees, pushes, renames and deletes) fall
+// back to the git executable through the embedded GitService, and fail with
+// ErrToolNotFound when it is not installed.
+type GoGitService struct {
+	*GitService
+}
+
+// NewGoGitService creates a new native git service instance
+func NewGoGitService() *GoGitService {
+	return &GoGitService{GitService: NewGitService()}
+}
+
+// NewGitServiceForBackend returns the git service for a backend name; an empty
+// name selects auto
+func NewGitServiceForBackend(backend string) (GitServiceInterface, error) {
+	switch backend {
+	case "", models.GitBackendAuto:
+		if _, err := exec.LookPath("git"); err != nil {
+			return NewGoGitService(), nil
+		}
+		return NewGitService(), nil
+	case models.GitBackendNative:
+		return NewGoGitService(), nil
+	case models.GitBackendExec:
+		return NewGitService(), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown git backend %q, must be one of: %s, %s, %s",
+			models.ErrConfigInvalid, backend, models.GitBackendAuto, models.GitBackendNative, models.GitBackendExec)
+	}
+}
+
+// At returns a service operating on the repository at dir
+func (g *GoGitService) At(dir string) GitServiceInterface {
+	return &GoGitService{GitService: &GitService{dir: dir}}
+}
+
+// workDir returns the directory the service operates in
+func (g *GoGitService) workDir() (string, error) {
+	if g.dir != "" {
+		return g.dir, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return cwd, nil
+}
+
+// open opens the repository containing the service's directory
+func (g *GoGitService) open() (*git.Repository, error) {
+	dir, err := g.workDir()
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	return repo, nil
+}
+
+func (g *GoGitService) GetRepoRoot() (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+	return worktree.Filesystem.Root(), nil
+}
+
+// GetCurrentBranch returns the checked out branch, or HEAD when detached
+func (g *GoGitService) GetCurrentBranch() (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if head.Type() == plumbing.SymbolicReference && head.Target().IsBranch() {
+		return head.Target().Short(), nil
+	}
+	return "HEAD", nil
+}
+
+// CreateBranch creates branchName at startPoint, or at HEAD when startPoint is
+// empty, and switches to it. Uncommitted changes make the checkout fail.
+func (g *GoGitService) CreateBranch(branchName, startPoint string) error {
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to create branch '%s': %w", branchName, err)
+	}
+
+	if startPoint == "" {
+		startPoint = "HEAD"
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(startPoint))
+	if err != nil {
+		return fmt.Errorf("failed to create branch '%s': %w: %s", branchName, models.ErrRefNotFound, startPoint)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to create branch '%s': %w", branchName, err)
+	}
+
+	err = worktree.Checkout(&git.CheckoutOptions{
+		Hash:   *hash,
+		Branch: plumbing.NewBranchReferenceName(branchName),
+		Create: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create branch '%s': %w", branchName, err)
+	}
+	return nil
+}
+
+func (g *GoGitService) CheckoutBranch(branchName string) error {
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to checkout branch '%s': %w", branchName, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to checkout branch '%s': %w", branchName, err)
+	}
+
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branchName)}); err != nil {
+		return fmt.Errorf("failed to checkout branch '%s': %w", branchName, err)
+	}
+	return nil
+}
+
+func (g *GoGitService) BranchExists(branchName string) (bool, error) {
+	repo, err := g.open()
+	if err != nil {
+		return false, fmt.Errorf("failed to check if branch exists: %w", err)
+	}
+
+	_, err = repo.Reference(plumbing.NewBranchReferenceName(branchName), false)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check if branch exists: %w", err)
+	}
+	return true, nil
+}
+
+// RefExists reports whether ref resolves to a commit
+func (g *GoGitService) RefExists(ref string) (bool, error) {
+	if _, err := g.ResolveRef(ref); err != nil {
+		if errors.Is(err, models.ErrRefNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ResolveRef returns the commit a ref points to
+func (g *GoGitService) ResolveRef(ref string) (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve '%s': %w", ref, err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", models.ErrRefNotFound, ref)
+	}
+
+	// Annotated tags resolve to their commit
+	if tag, err := repo.TagObject(*hash); err == nil {
+		commit, err := tag.Commit()
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", models.ErrRefNotFound, ref)
+		}
+		return commit.Hash.String(), nil
+	}
+	return hash.String(), nil
+}
+
+// IsDirty reports whether tracked files have uncommitted changes, staged or not
+func (g *GoGitService) IsDirty() (bool, error) {
+	repo, err := g.open()
+	if err != nil {
+		return false, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return false, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return false, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+
+	for _, file := range status {
+		if file.Worktree == git.Untracked {
+			continue
+		}
+		if file.Staging != git.Unmodified || file.Worktree != git.Unmodified {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListBranches returns local and remote-tracking branch names; remote-tracking
+// branches are listed without their remote, so origin/004-login becomes 004-login
+func (g *GoGitService) ListBranches() ([]string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	branches := []string{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		switch {
+		case name.IsBranch():
+			branches = append(branches, name.Short())
+		case name.IsRemote():
+			// refs/remotes/<remote>/<branch>
+			parts := strings.SplitN(strings.TrimPrefix(name.String(), "refs/remotes/"), "/", 2)
+			if len(parts) == 2 && parts[1] != "HEAD" {
+				branches = append(branches, parts[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	return branches, nil
+}
+
+// GetDefaultBranch returns the branch <remote>/HEAD points to, falling back
+// to a local main or master branch
+func (g *GoGitService) GetDefaultBranch(remote string) (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	remoteHead, err := repo.Reference(plumbing.NewRemoteHEADReferenceName(remote), false)
+	if err == nil && remoteHead.Type() == plumbing.SymbolicReference {
+		return strings.TrimPrefix(remoteHead.Target().Short(), remote+"/"), nil
+	}
+
+	for _, candidate := range []string{"main", "master"} {
+		if exists, err := g.BranchExists(candidate); err == nil && exists {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("failed to determine default branch: %s/HEAD is not set and neither main nor master exists", remote)
+}
+
+// ListTree returns the names of the entries of dir (relative to the repository
+// root) at ref, without reading the working tree
+func (g *GoGitService) ListTree(ref, dir string) ([]string, error) {
+	tree, err := g.treeAt(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s at %s: %w", dir, ref, err)
+	}
+
+	if dir = strings.Trim(dir, "/"); dir != "" && dir != "." {
+		if tree, err = tree.Tree(dir); err != nil {
+			return nil, fmt.Errorf("failed to list %s at %s: %w", dir, ref, err)
+		}
+	}
+
+	names := make([]string, 0, len(tree.Entries))
+	for _, entry := range tree.Entries {
+		names = append(names, entry.Name)
+	}
+	return names, nil
+}
+
+// treeAt returns the root tree of the commit ref resolves to
+func (g *GoGitService) treeAt(ref string) (*object.Tree, error) {
+	repo, err := g.open()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := g.ResolveRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return nil, err
+	}
+	return commit.Tree()
+}
+
+// Init creates an empty repository in the service's directory
+func (g *GoGitService) Init() error {
+	dir, err := g.workDir()
+	if err != nil {
+		return fmt.Errorf("failed to initialize git repository: %w", err)
+	}
+
+	if _, err := git.PlainInit(dir, false); err != nil {
+		return fmt.Errorf("failed to initialize git repository: %w", err)
+	}
+	return nil
+}
+
+// Status returns the changed and untracked files, ignored files excluded
+func (g *GoGitService) Status() ([]models.GitFileStatus, error) {
+	repo, err := g.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+
+	files := []models.GitFileStatus{}
+	for path, file := range status {
+		if file.Staging == git.Unmodified && file.Worktree == git.Unmodified {
+			continue
+		}
+		files = append(files, models.GitFileStatus{Path: path, Staging: byte(file.Staging), Worktree: byte(file.Worktree)})
+	}
+	slices.SortFunc(files, func(a, b models.GitFileStatus) int { return strings.Compare(a.Path, b.Path) })
+	return files, nil
+}
+
+// Add stages paths, absolute or relative to the repository root, including
+// deletions
+func (g *GoGitService) Add(paths ...string) error {
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to add files to git: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to add files to git: %w", err)
+	}
+	if paths, err = repoPaths(worktree.Filesystem.Root(), paths); err != nil {
+		return fmt.Errorf("failed to add files to git: %w", err)
+	}
+
+	for _, path := range paths {
+		options := &git.AddOptions{Path: path}
+		if path == "." {
+			options = &git.AddOptions{All: true}
+		}
+		if err := worktree.AddWithOptions(options); err != nil {
+			return fmt.Errorf("failed to add %s to git: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Commit records the staged changes and returns the new commit. With paths,
+// absolute or relative to the repository root, only the changes to those paths
+// are committed and anything else staged stays staged; that needs the git
+// executable when other changes are staged. A nil author uses the configured
+// user.
+func (g *GoGitService) Commit(message string, author *models.GitSignature, paths ...string) (string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	if len(paths) > 0 {
+		if paths, err = repoPaths(worktree.Filesystem.Root(), paths); err != nil {
+			return "", fmt.Errorf("failed to create commit: %w", err)
+		}
+
+		// The index is committed as a whole, so it may only hold changes to paths
+		status, err := g.Status()
+		if err != nil {
+			return "", fmt.Errorf("failed to create commit: %w", err)
+		}
+		for _, file := range status {
+			if file.Staging != ' ' && !file.IsUntracked() && !underPaths(file.Path, paths) {
+				return g.GitService.Commit(message, author, paths...)
+			}
+		}
+	}
+
+	options := &git.CommitOptions{}
+	if author != nil {
+		options.Author = &object.Signature{Name: author.Name, Email: author.Email, When: time.Now()}
+	}
+
+	hash, err := worktree.Commit(message, options)
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+	return hash.String(), nil
+}
+
+// Log returns up to limit commits reachable from HEAD that touch path
+// (relative to the repository root), newest first. An empty path lists every
+// commit and a limit of zero lists them all.
+func (g *GoGitService) Log(path string, limit int) ([]models.GitCommit, error) {
+	repo, err := g.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	options := &git.LogOptions{}
+	if path = strings.Trim(path, "/"); path != "" && path != "." {
+		// A directory matches the files below it
+		options.PathFilter = func(file string) bool {
+			return file == path || strings.HasPrefix(file, path+"/")
+		}
+	}
+
+	iter, err := repo.Log(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+	defer iter.Close()
+
+	commits := []models.GitCommit{}
+	err = iter.ForEach(func(commit *object.Commit) error {
+		if limit > 0 && len(commits) == limit {
+			return storer.ErrStop
+		}
+		subject, _, _ := strings.Cut(commit.Message, "\n")
+		commits = append(commits, models.GitCommit{
+			Hash:    commit.Hash.String(),
+			Author:  models.GitSignature{Name: commit.Author.Name, Email: commit.Author.Email},
+			Date:    commit.Author.When,
+			Subject: subject,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+	return commits, nil
+}
+
+// ListTags returns the tag names, sorted
+func (g *GoGitService) ListTags() ([]string, error) {
+	repo, err := g.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := []string{}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		tags = append(tags, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	slices.Sort(tags)
+	return tags, nil
+}
+
+// CreateTag tags HEAD; the tag is annotated when message is not empty
+func (g *GoGitService) CreateTag(name, message string) error {
+	repo, err := g.open()
+	if err != nil {
+		return fmt.Errorf("failed to create tag '%s': %w", name, err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to create tag '%s': %w", name, err)
+	}
+
+	var options *git.CreateTagOptions
+	if message != "" {
+		options = &git.CreateTagOptions{Message: message}
+	}
+	if _, err := repo.CreateTag(name, head.Hash(), options); err != nil {
+		return fmt.Errorf("failed to create tag '%s': %w", name, err)
+	}
+	return nil
+}
+
+// GetConfig returns the effective value of a git configuration key, or an
+// empty string when it is not set
+func (g *GoGitService) GetConfig(key string) (string, error) {
+	section, rest, ok := strings.Cut(key, ".")
+	if !ok || rest == "" {
+		return "", fmt.Errorf("failed to read git config %s: key must be section.name", key)
+	}
+	subsection, option := "", rest
+	if i := strings.LastIndex(rest, "."); i >= 0 {
+		subsection, option = rest[:i], rest[i+1:]
+	}
+
+	// Outside a repository only the user's configuration applies
+	var cfg *config.Config
+	repo, err := g.open()
+	if err == nil {
+		cfg, err = repo.ConfigScoped(config.SystemScope)
+	} else {
+		cfg, err = config.LoadConfig(config.GlobalScope)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read git config %s: %w", key, err)
+	}
+
+	if !cfg.Raw.HasSection(section) {
+		return "", nil
+	}
+	if subsection != "" {
+		return cfg.Raw.Section(section).Subsection(subsection).Option(option), nil
+	}
+	return cfg.Raw.Section(section).Option(option), nil
+}
+
+// underPaths reports whether path is one of paths or inside one of them
+func underPaths(path string, paths []string) bool {
+	for _, candidate := range paths {
+		candidate = strings.TrimSuffix(candidate, "/")
+		if candidate == "." || path == candidate || strings.HasPrefix(path, candidate+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// requireGit fails with ErrToolNotFound when an operation that falls back to
+// the git executable runs without git in PATH
+func requireGit(operation string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("%w: %s requires the git binary, which is not in PATH (install from https://git-scm.com/downloads)",
+			models.ErrToolNotFound, operation)
+	}
+	return nil
+}
+
+func (g *GoGitService) Stash(message string) error {
+	if err := requireGit("stashing changes"); err != nil {
+		return err
+	}
+	return g.GitService.Stash(message)
+}
+
+func (g *GoGitService) RenameBranch(oldName, newName string) error {
+	if err := requireGit("renaming a branch"); err != nil {
+		return err
+	}
+	return g.GitService.RenameBranch(oldName, newName)
+}
+
+func (g *GoGitService) DeleteBranch(branchName string, force bool) error {
+	if err := requireGit("deleting a branch"); err != nil {
+		return err
+	}
+	return g.GitService.DeleteBranch(branchName, force)
+}
+
+func (g *GoGitService) Diff(ref string, paths ...string) (string, error) {
+	if err := requireGit("diffing"); err != nil {
+		return "", err
+	}
+	return g.GitService.Diff(ref, paths...)
+}
+
+func (g *GoGitService) ListRemoteRefs(remote, prefix string) ([]string, error) {
+	if err := requireGit("listing remote refs"); err != nil {
+		return nil, err
+	}
+	return g.GitService.ListRemoteRefs(remote, prefix)
+}
+
+func (g *GoGitService) PushNewRef(remote, ref, message string) error {
+	if err := requireGit("pushing a ref"); err != nil {
+		return err
+	}
+	return g.GitService.PushNewRef(remote, ref, message)
+}
+
+func (g *GoGitService) DeleteRemoteRef(remote, ref string) error {
+	if err := requireGit("deleting a remote ref"); err != nil {
+		return err
+	}
+	return g.GitService.DeleteRemoteRef(remote, ref)
+}
+
+func (g *GoGitService) AddWorktree(path, branchName, startPoint string) error {
+	if err := requireGit("adding a worktree"); err != nil {
+		return err
+	}
+	return g.GitService.AddWorktree(path, branchName, startPoint)
+}
+
+func (g *GoGitService) ListWorktrees() ([]models.Worktree, error) {
+	if err := requireGit("listing worktrees"); err != nil {
+		return nil, err
+	}
+	return g.GitService.ListWorktrees()
+}
+
+func (g *GoGitService) RemoveWorktree(path string, force bool) error {
+	if err := requireGit("removing a worktree"); err != nil {
+		return err
+	}
+	return g.GitService.RemoveWorktree(path, force)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// NewHTTPClient creates a client with the configured proxy and CA bundle; all
+// outgoing requests go through clients made here so they behave the same
+func NewHTTPClient(config *models.ProjectConfig, timeout time.Duration) (*http.Client, error) {
+	transport, err := newHTTPTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// newHTTPTransport creates a transport that sends requests through http.proxy,
+// or HTTPS_PROXY and HTTP_PROXY, except to hosts in NO_PROXY, and trusts the
+// certificates in http.ca_bundle in addition to the system roots
+func newHTTPTransport(config *models.ProjectConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxy := httpproxy.FromEnvironment()
+	if config.HTTP.Proxy != "" {
+		proxy.HTTPProxy, proxy.HTTPSProxy = config.HTTP.Proxy, config.HTTP.Proxy
+	}
+	proxyURL := proxy.ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyURL(req.URL)
+	}
+
+	if path := config.GetCABundle(); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: http.ca_bundle: %v", models.ErrConfigInvalid, err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: http.ca_bundle: no PEM certificates in %s", models.ErrConfigInvalid, path)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	}
+
+	return transport, nil
+}
+
+// mirrorURL rewrites a release asset URL on the GitHub server to the mirror,
+// keeping its path, e.g. https://github.com/o/r/releases/download/v1/a.zip to
+// https://mirror.example.com/o/r/releases/download/v1/a.zip
+func (g *GitHubService) mirrorURL(assetURL string) string {
+	prefix := "https://" + g.host + "/"
+	if g.mirror == "" || !strings.HasPrefix(assetURL, prefix) {
+		return assetURL
+	}
+	return g.mirror + "/" + strings.TrimPrefix(assetURL, prefix)
+}
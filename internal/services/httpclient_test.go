@@ -0,0 +1,80 @@
+package services
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy.example.com:8080")
+	t.Setenv("NO_PROXY", "internal.example.com")
+
+	// http.proxy overrides HTTPS_PROXY; NO_PROXY applies either way
+	f := func(proxy, target, want string) {
+		t.Helper()
+		config := &models.ProjectConfig{HTTP: models.HTTPConfig{Proxy: proxy}}
+		transport, err := newHTTPTransport(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, _ := http.NewRequest("GET", target, nil)
+		got, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (got == nil && want != "") || (got != nil && got.String() != want) {
+			t.Errorf("proxy for %s = %v, want %q", target, got, want)
+		}
+	}
+
+	f("", "https://api.github.com/repos", "http://env-proxy.example.com:8080")
+	f("http://proxy.example.com:3128", "https://api.github.com/repos", "http://proxy.example.com:3128")
+	f("http://proxy.example.com:3128", "https://internal.example.com/api/v3", "")
+
+	// The CA bundle makes a server with a private certificate trusted
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certificate, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("NO_PROXY", "*")
+	config := &models.ProjectConfig{HTTP: models.HTTPConfig{CABundle: bundle}}
+	client, err := NewHTTPClient(config, ConnectivityTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with CA bundle failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := NewHTTPClient(&models.ProjectConfig{HTTP: models.HTTPConfig{CABundle: bundle + ".missing"}}, ConnectivityTimeout); !errors.Is(err, models.ErrConfigInvalid) {
+		t.Errorf("missing CA bundle: got %v", err)
+	}
+}
+
+func TestMirrorURL(t *testing.T) {
+	github := &GitHubService{host: "github.com", mirror: "https://artifacts.example.com/github"}
+	f := func(assetURL, want string) {
+		t.Helper()
+		if got := github.mirrorURL(assetURL); got != want {
+			t.Errorf("mirrorURL(%q) = %q, want %q", assetURL, got, want)
+		}
+	}
+
+	f("https://github.com/euforicio/spec-kit/releases/download/v1.0.0/spec-kit-cache-template.zip",
+		"https://artifacts.example.com/github/euforicio/spec-kit/releases/download/v1.0.0/spec-kit-cache-template.zip")
+	f("https://objects.githubusercontent.com/asset", "https://objects.githubusercontent.com/asset")
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// Spec template lines seeded from an issue
+var (
+	specInputRegex = regexp.MustCompile(`(?m)^\*\*Input\*\*:.*$`)
+	userStoryRegex = regexp.MustCompile(`(?m)^(###? Primary User Story[ \t]*\n)\[[^\n]*\][ \t]*$`)
+)
+
+// issueTicket is the ticket of a feature created from an issue when the branch
+// pattern uses {ticket} and none was given, e.g. GH-123
+func issueTicket(issue *models.Issue) string {
+	return fmt.Sprintf("GH-%d", issue.Number)
+}
+
+// seedSpecFromIssue fills the spec title, Input and primary user story from an
+// issue, and records the issue and its labels below Input
+func (f *FeatureService) seedSpecFromIssue(specFile string, issue *models.Issue) error {
+	content, err := f.filesystem.ReadFile(specFile)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	title := "# Feature Specification: " + issue.Title
+	if specTitleRegex.MatchString(content) {
+		content = specTitleRegex.ReplaceAllLiteralString(content, title)
+	} else if _, rest, ok := strings.Cut(content, "\n"); ok && strings.HasPrefix(content, "# ") {
+		content = title + "\n" + rest
+	}
+
+	metadata := fmt.Sprintf("**Input**: User description: %q  \n**Issue**: [%s](%s)", issue.Title, issue.Reference(), issue.URL)
+	if len(issue.Labels) > 0 {
+		metadata += "  \n**Labels**: " + strings.Join(issue.Labels, ", ")
+	}
+	if loc := specInputRegex.FindStringIndex(content); loc != nil {
+		content = content[:loc[0]] + metadata + content[loc[1]:]
+	} else {
+		// No template: put the metadata below the title
+		heading, rest, _ := strings.Cut(content, "\n")
+		content = heading + "\n\n" + metadata + "\n" + rest
+	}
+
+	// The issue body is quoted as the starting point of the user story
+	if issue.Body != "" {
+		story := "> " + strings.ReplaceAll(issue.Body, "\n", "\n> ")
+		if loc := userStoryRegex.FindStringSubmatchIndex(content); loc != nil {
+			content = content[:loc[3]] + story + content[loc[1]:]
+		} else {
+			content = strings.TrimRight(content, "\n") + "\n\n## Issue Description\n\n" + story + "\n"
+		}
+	}
+
+	if err := f.filesystem.WriteFile(specFile, content); err != nil {
+		return fmt.Errorf("failed to seed spec from issue: %w", err)
+	}
+	return nil
+}
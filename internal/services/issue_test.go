@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestCreateFeatureFromIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/widgets/issues/123":
+			w.Write([]byte(`{"title":"Export invoices as PDF","body":"As an accountant\r\nI want PDF invoices","html_url":"https://github.com/acme/widgets/issues/123","labels":[{"name":"billing"},{"name":"enhancement"}]}`))
+		case "/repos/acme/widgets/issues/124":
+			w.Write([]byte(`{"title":"Fix login","html_url":"https://github.com/acme/widgets/pull/124","pull_request":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	github := NewGitHubServiceWithClient(server.Client(), "acme", "widgets")
+	github.baseURL = server.URL
+	repo := models.GitHubRepository{Owner: "acme", Name: "widgets"}
+
+	if _, err := github.GetIssue(repo, 124); !errors.Is(err, models.ErrGitHubRequest) {
+		t.Fatalf("GetIssue of a pull request: %v", err)
+	}
+	if _, err := github.GetIssue(repo, 999); !errors.Is(err, models.ErrGitHubRequest) {
+		t.Fatalf("GetIssue of a missing issue: %v", err)
+	}
+	issue, err := github.GetIssue(repo, 123)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+
+	newTestRepo(t)
+	commitTestFile(t, "templates/spec-template.md", `# Feature Specification: [FEATURE NAME]
+
+**Feature Branch**: `+"`[###-feature-name]`"+`
+**Status**: Draft
+**Input**: User description: "$ARGUMENTS"
+
+### Primary User Story
+[Describe the main user journey in plain language]
+
+### Acceptance Scenarios
+`)
+	feature := NewFeatureService(NewFilesystemService(), NewGitService(), nil)
+
+	result, err := feature.CreateFeature(FeatureCreateOptions{Issue: issue})
+	if err != nil {
+		t.Fatalf("CreateFeature: %v", err)
+	}
+	if result.BranchName != "001-export-invoices-as" || result.Issue != "https://github.com/acme/widgets/issues/123" {
+		t.Fatalf("CreateFeature() = %+v", result)
+	}
+
+	spec, err := os.ReadFile(result.SpecFile)
+	if err != nil {
+		t.Fatalf("failed to read spec: %v", err)
+	}
+	expected := `# Feature Specification: Export invoices as PDF
+
+**Feature Branch**: ` + "`[###-feature-name]`" + `
+**Status**: Draft
+**Input**: User description: "Export invoices as PDF"` + "  " + `
+**Issue**: [acme/widgets#123](https://github.com/acme/widgets/issues/123)` + "  " + `
+**Labels**: billing, enhancement
+
+### Primary User Story
+> As an accountant
+> I want PDF invoices
+
+### Acceptance Scenarios
+`
+	if string(spec) != expected {
+		t.Fatalf("spec:\n%s\nexpected:\n%s", spec, expected)
+	}
+}
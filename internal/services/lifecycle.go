@@ -0,0 +1,310 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
+	"github.com/euforicio/spec-kit/internal/naming"
+)
+
+// specTitleRegex matches the title line of a feature specification
+var specTitleRegex = regexp.MustCompile(`(?m)^# Feature Specification: .*$`)
+
+// FeatureArchiveOptions contains options for archiving a feature
+type FeatureArchiveOptions struct {
+	Reason       string // recorded in the archive metadata
+	DeleteBranch bool   // delete the feature branch once it is merged
+}
+
+// FeatureDeleteOptions contains options for deleting a feature
+type FeatureDeleteOptions struct {
+	KeepBranch bool // delete only the spec directory
+}
+
+// RenameFeature gives the feature a new description, renaming its branch and
+// spec directory together and updating the spec title. The number, ticket and
+// date of the feature are kept.
+func (f *FeatureService) RenameFeature(description string) (*models.FeatureRenameResult, error) {
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	feature, err := f.currentFeature(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	slug := f.scheme.Slugify(description)
+	if slug == "" {
+		return nil, naming.ErrEmptySlug
+	}
+
+	oldName := filepath.Base(feature.Dir)
+	newName, ok := f.scheme.Reslug(oldName, slug)
+	if !ok {
+		return nil, fmt.Errorf("branch pattern %s has no {slug} to rename", f.scheme.Pattern())
+	}
+	if newName == oldName {
+		return nil, fmt.Errorf("feature is already named %s", oldName)
+	}
+
+	newBranch := feature.Branch
+	if exists, _ := f.git.BranchExists(feature.Branch); exists {
+		newBranch, _ = f.scheme.Reslug(feature.Branch, slug)
+		if exists, _ := f.git.BranchExists(newBranch); exists {
+			return nil, fmt.Errorf("%w: %s", models.ErrBranchExists, newBranch)
+		}
+	}
+
+	// Renaming onto an empty directory would succeed and hide it
+	newDir := filepath.Join(filepath.Dir(feature.Dir), newName)
+	if exists, _ := f.filesystem.DirectoryExists(newDir); exists {
+		return nil, fmt.Errorf("feature directory %s already exists", newDir)
+	}
+
+	featureDir, updated, err := f.moveFeature(feature.Branch, newBranch, feature.Dir, newDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retitle the spec after the new description; a feature with only a
+	// branch has no spec yet
+	if featureDir != "" {
+		specFile := filepath.Join(featureDir, "spec.md")
+		if content, err := f.filesystem.ReadFile(specFile); err == nil && specTitleRegex.MatchString(content) {
+			title := "# Feature Specification: " + strings.TrimSpace(description)
+			retitled := specTitleRegex.ReplaceAllLiteralString(content, title)
+			if retitled != content {
+				if err := f.filesystem.WriteFile(specFile, retitled); err != nil {
+					return nil, fmt.Errorf("failed to update spec title: %w", err)
+				}
+				if !slices.Contains(updated, specFile) {
+					updated = append(updated, specFile)
+				}
+			}
+		}
+	}
+
+	return &models.FeatureRenameResult{
+		OldBranch:    feature.Branch,
+		NewBranch:    newBranch,
+		OldDir:       feature.Dir,
+		NewDir:       newDir,
+		DirMoved:     featureDir != "",
+		UpdatedFiles: updated,
+	}, nil
+}
+
+// ArchiveFeature moves the spec directory of a feature to specs/_archive and
+// records when and why it was archived. Archived features keep their number.
+func (f *FeatureService) ArchiveFeature(opts FeatureArchiveOptions) (*models.FeatureArchiveResult, error) {
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	feature, err := f.currentFeature(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists, _ := f.filesystem.DirectoryExists(feature.Dir); !exists {
+		return nil, fmt.Errorf("%w: directory %s does not exist", models.ErrFeatureNotFound, feature.Dir)
+	}
+
+	branchExists, _ := f.git.BranchExists(feature.Branch)
+	if opts.DeleteBranch && branchExists {
+		if current, _ := f.git.GetCurrentBranch(); current == feature.Branch {
+			return nil, fmt.Errorf("cannot delete %s while it is checked out; switch to another branch or archive with --feature from it", feature.Branch)
+		}
+	}
+
+	name := filepath.Base(feature.Dir)
+	archiveDir := filepath.Join(f.specsDir(repoRoot), models.ArchiveDirName)
+	archivedPath := filepath.Join(archiveDir, name)
+	if exists, _ := f.filesystem.DirectoryExists(archivedPath); exists {
+		return nil, fmt.Errorf("feature %s is already archived at %s", name, archivedPath)
+	}
+
+	metadata := models.FeatureArchive{
+		Feature:    name,
+		ArchivedAt: time.Now().UTC(),
+		Reason:     opts.Reason,
+	}
+	if branchExists {
+		metadata.Branch = feature.Branch
+		metadata.Commit, _ = f.git.ResolveRef(feature.Branch)
+	}
+
+	if err := f.filesystem.CreateDirectory(archiveDir); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := f.filesystem.Rename(feature.Dir, archivedPath); err != nil {
+		return nil, fmt.Errorf("failed to archive feature directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal archive metadata: %w", err)
+	}
+	if err := f.filesystem.WriteFile(filepath.Join(archivedPath, models.ArchiveMetadataFile), string(data)+"\n"); err != nil {
+		return nil, fmt.Errorf("failed to write archive metadata: %w", err)
+	}
+
+	result := &models.FeatureArchiveResult{
+		Archive:    metadata,
+		ArchiveDir: archivedPath,
+	}
+
+	// An unmerged branch is kept, so archiving never loses work
+	if opts.DeleteBranch && branchExists {
+		if err := f.git.DeleteBranch(feature.Branch, false); err != nil {
+			return result, err
+		}
+		result.BranchDeleted = true
+	}
+
+	return result, nil
+}
+
+// DeleteFeature removes the spec directory and branch of a feature. When the
+// feature branch is checked out, the default branch is checked out first.
+func (f *FeatureService) DeleteFeature(opts FeatureDeleteOptions) (*models.FeatureDeleteResult, error) {
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	feature, err := f.currentFeature(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.FeatureDeleteResult{
+		Branch:     feature.Branch,
+		FeatureDir: feature.Dir,
+	}
+
+	branchExists, _ := f.git.BranchExists(feature.Branch)
+	deleteBranch := branchExists && !opts.KeepBranch
+
+	if current, _ := f.git.GetCurrentBranch(); deleteBranch && current == feature.Branch {
+		dirty, err := f.git.IsDirty()
+		if err != nil {
+			return nil, err
+		}
+		if dirty {
+			return nil, fmt.Errorf("%w: commit or stash them before deleting the checked out feature", models.ErrWorkingTreeDirty)
+		}
+
+		base, _, err := f.baseRef("")
+		if err != nil {
+			return nil, err
+		}
+		if base == "" {
+			return nil, fmt.Errorf("cannot delete %s while it is checked out and no default branch was found; switch branches first", feature.Branch)
+		}
+		if err := f.git.CheckoutBranch(base); err != nil {
+			return nil, err
+		}
+		result.SwitchedTo = base
+	}
+
+	// Untracked spec files survive a checkout, so remove the directory either way
+	if exists, _ := f.filesystem.DirectoryExists(feature.Dir); exists {
+		if err := f.filesystem.RemoveDirectory(feature.Dir); err != nil {
+			return nil, fmt.Errorf("failed to remove feature directory: %w", err)
+		}
+	}
+
+	if deleteBranch {
+		if err := f.git.DeleteBranch(feature.Branch, true); err != nil {
+			return nil, err
+		}
+		result.BranchDeleted = true
+	}
+
+	return result, nil
+}
+
+// moveFeature renames a feature directory and, when it exists, its branch,
+// then rewrites references to the old names in the feature's markdown files.
+// The directory is moved first so a failed branch rename can be undone.
+func (f *FeatureService) moveFeature(oldBranch, newBranch, oldPath, newPath string) (string, []string, error) {
+	renameBranch := oldBranch != newBranch
+	if renameBranch {
+		if exists, _ := f.git.BranchExists(oldBranch); !exists {
+			renameBranch = false
+		}
+	}
+
+	if exists, _ := f.filesystem.DirectoryExists(oldPath); !exists {
+		if renameBranch {
+			if err := f.git.RenameBranch(oldBranch, newBranch); err != nil {
+				return "", nil, err
+			}
+		}
+		return "", nil, nil
+	}
+
+	if err := f.filesystem.Rename(oldPath, newPath); err != nil {
+		return "", nil, fmt.Errorf("failed to rename feature directory: %w", err)
+	}
+	if renameBranch {
+		if err := f.git.RenameBranch(oldBranch, newBranch); err != nil {
+			_ = f.filesystem.Rename(newPath, oldPath)
+			return "", nil, err
+		}
+	}
+
+	// Branch names contain directory names, so replace them first
+	replacements := []string{}
+	if oldBranch != newBranch {
+		replacements = append(replacements, oldBranch, newBranch)
+	}
+	replacements = append(replacements, filepath.Base(oldPath), filepath.Base(newPath))
+
+	updated, err := f.replaceFeatureReferences(newPath, strings.NewReplacer(replacements...))
+	if err != nil {
+		return "", nil, err
+	}
+	return newPath, updated, nil
+}
+
+// replaceFeatureReferences rewrites mentions of old feature names in the
+// markdown files of a feature directory and returns the files changed
+func (f *FeatureService) replaceFeatureReferences(featureDir string, replacer *strings.Replacer) ([]string, error) {
+	entries, err := f.filesystem.ListDirectory(featureDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature directory: %w", err)
+	}
+
+	updated := []string{}
+	for _, entry := range entries {
+		if filepath.Ext(entry) != ".md" {
+			continue
+		}
+		path := filepath.Join(featureDir, entry)
+		content, err := f.filesystem.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry, err)
+		}
+		replaced := replacer.Replace(content)
+		if replaced == content {
+			continue
+		}
+		if err := f.filesystem.WriteFile(path, replaced); err != nil {
+			return nil, fmt.Errorf("failed to update %s: %w", entry, err)
+		}
+		updated = append(updated, path)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestFeatureLifecycle(t *testing.T) {
+	dir := newTestRepo(t)
+	feature := NewFeatureService(NewFilesystemService(), NewGitService(), nil)
+
+	created, err := feature.CreateFeature(FeatureCreateOptions{Description: "user login"})
+	if err != nil {
+		t.Fatalf("CreateFeature: %v", err)
+	}
+	writeTestFile(t, created.SpecFile, "# Feature Specification: User Login\n\n**Feature Branch**: `001-user-login`\n")
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "spec")
+
+	renamed, err := feature.RenameFeature("Passwordless sign in")
+	if err != nil {
+		t.Fatalf("RenameFeature: %v", err)
+	}
+	if renamed.NewBranch != "001-passwordless-sign" || runGit(t, "rev-parse", "--abbrev-ref", "HEAD") != renamed.NewBranch {
+		t.Fatalf("RenameFeature: branch is %q", renamed.NewBranch)
+	}
+	spec, err := os.ReadFile(filepath.Join(dir, "specs", "001-passwordless-sign", "spec.md"))
+	if err != nil {
+		t.Fatalf("renamed spec: %v", err)
+	}
+	if expected := "# Feature Specification: Passwordless sign in\n\n**Feature Branch**: `001-passwordless-sign`\n"; string(spec) != expected {
+		t.Fatalf("renamed spec:\n%s\nexpected:\n%s", spec, expected)
+	}
+	runGit(t, "add", "-A")
+	runGit(t, "commit", "--quiet", "-m", "rename")
+
+	// Archive from main after merging; the merged branch can be deleted
+	runGit(t, "checkout", "--quiet", "main")
+	runGit(t, "merge", "--quiet", "--no-edit", renamed.NewBranch)
+	feature.SelectFeature("1")
+	archived, err := feature.ArchiveFeature(FeatureArchiveOptions{Reason: "shipped", DeleteBranch: true})
+	if err != nil {
+		t.Fatalf("ArchiveFeature: %v", err)
+	}
+	if !archived.BranchDeleted || archived.Archive.Commit == "" || archived.Archive.Reason != "shipped" {
+		t.Fatalf("ArchiveFeature() = %+v", archived)
+	}
+	metadata, err := os.ReadFile(filepath.Join(dir, "specs", models.ArchiveDirName, "001-passwordless-sign", models.ArchiveMetadataFile))
+	if err != nil || !strings.Contains(string(metadata), `"reason": "shipped"`) {
+		t.Fatalf("archive metadata: %s, %v", metadata, err)
+	}
+	runGit(t, "add", "-A")
+	runGit(t, "commit", "--quiet", "-m", "archive")
+
+	// Archived numbers are not reused; deleting the checked out feature returns to main
+	feature.SelectFeature("")
+	created, err = feature.CreateFeature(FeatureCreateOptions{Description: "search"})
+	if err != nil {
+		t.Fatalf("CreateFeature: %v", err)
+	}
+	if created.FeatureNum != "002" {
+		t.Fatalf("CreateFeature reused an archived number: %s", created.FeatureNum)
+	}
+
+	deleted, err := feature.DeleteFeature(FeatureDeleteOptions{})
+	if err != nil {
+		t.Fatalf("DeleteFeature: %v", err)
+	}
+	if deleted.SwitchedTo != "main" || !deleted.BranchDeleted {
+		t.Fatalf("DeleteFeature() = %+v", deleted)
+	}
+	if _, err := os.Stat(deleted.FeatureDir); !os.IsNotExist(err) {
+		t.Fatalf("feature directory still exists after delete")
+	}
+}
+
+func TestRenameFeatureTargets(t *testing.T) {
+	dir := newTestRepo(t)
+	feature := NewFeatureService(NewFilesystemService(), NewGitService(), nil)
+
+	// A feature with only a branch keeps unrelated spec.md files alone
+	writeTestFile(t, "spec.md", "# Feature Specification: Unrelated\n")
+	runGit(t, "checkout", "--quiet", "-b", "002-user-export")
+	renamed, err := feature.RenameFeature("bulk export")
+	if err != nil {
+		t.Fatalf("RenameFeature: %v", err)
+	}
+	if expected := filepath.Join(dir, "specs", "002-bulk-export"); renamed.NewDir != expected || renamed.DirMoved || renamed.NewBranch != "002-bulk-export" {
+		t.Fatalf("RenameFeature() = %+v, expected %s without moving a directory", renamed, expected)
+	}
+	if content, _ := os.ReadFile("spec.md"); string(content) != "# Feature Specification: Unrelated\n" {
+		t.Fatalf("RenameFeature rewrote spec.md in the working directory:\n%s", content)
+	}
+
+	// An existing directory, even an empty one, is not renamed over
+	writeTestFile(t, "specs/002-bulk-export/spec.md", "# Feature Specification: Bulk export\n")
+	if err := os.MkdirAll(filepath.Join(dir, "specs", "002-csv-export"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := feature.RenameFeature("csv export"); err == nil {
+		t.Fatalf("RenameFeature renamed onto an existing directory")
+	}
+	if branch := runGit(t, "rev-parse", "--abbrev-ref", "HEAD"); branch != "002-bulk-export" {
+		t.Fatalf("RenameFeature renamed the branch to %s", branch)
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// Precompiled regexes for the lint rules
+var (
+	clarificationRegex = regexp.MustCompile(`\[NEEDS CLARIFICATION[^\]]*\]`)
+	placeholderRegex   = regexp.MustCompile(`\[(FEATURE NAME|FEATURE|DATE|###-feature-name|link)\]|\$ARGUMENTS`)
+	lintTaskIDRegex    = regexp.MustCompile(`^T\d{3,}\s`)
+)
+
+// lintRequiredSections lists the level-2 headings each artifact must keep
+var lintRequiredSections = map[string][]string{
+	"spec.md": {"User Scenarios & Testing", "Requirements"},
+	"plan.md": {"Summary", "Technical Context"},
+}
+
+// lintGuidanceSections are template instructions that quote the markers the
+// rules look for; they are not checked
+var lintGuidanceSections = []string{"Execution Flow", "Quick Guidelines", "Review & Acceptance Checklist", "Execution Status"}
+
+// LintFeature checks the spec, plan and tasks of the current feature against
+// the lint rules, reporting each finding with the severity configured in
+// lint.rules. Rules set to off are skipped.
+func (f *FeatureService) LintFeature() (*models.FeatureLintResult, error) {
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	feature, err := f.currentFeature(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	if exists, _ := f.filesystem.DirectoryExists(feature.Dir); !exists {
+		return nil, fmt.Errorf("%w: %s", models.ErrFeatureNotFound, feature.Dir)
+	}
+
+	result := &models.FeatureLintResult{FeatureDir: feature.Dir, Findings: []models.LintFinding{}}
+	report := func(rule, file string, line int, message string) {
+		severity := f.config.GetLintSeverity(rule)
+		if severity == models.LintSeverityOff {
+			return
+		}
+		result.Findings = append(result.Findings, models.LintFinding{
+			Rule: rule, Severity: severity, File: file, Line: line, Message: message,
+		})
+		if severity == models.LintSeverityError {
+			result.Errors++
+		}
+	}
+
+	for _, file := range []string{"spec.md", "plan.md", "tasks.md"} {
+		path := filepath.Join(feature.Dir, file)
+		if exists, _ := f.filesystem.FileExists(path); !exists {
+			continue
+		}
+		content, err := f.filesystem.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		lintArtifact(file, content, report)
+	}
+
+	return result, nil
+}
+
+// lintArtifact applies the lint rules to one file of a feature
+func lintArtifact(file, content string, report func(rule, file string, line int, message string)) {
+	headings := map[string]bool{}
+	guidance, fenced := false, false
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		if level := markdownHeadingLevel(line); level > 0 {
+			heading := strings.TrimSpace(line[level:])
+			if level == 2 {
+				headings[heading] = true
+				guidance = false
+				for _, section := range lintGuidanceSections {
+					guidance = guidance || strings.HasPrefix(heading, section)
+				}
+			}
+		}
+		if guidance {
+			continue
+		}
+
+		for _, marker := range clarificationRegex.FindAllString(line, -1) {
+			report("needs-clarification", file, i+1, "unresolved "+marker)
+		}
+		for _, placeholder := range placeholderRegex.FindAllString(line, -1) {
+			report("placeholder-text", file, i+1, "template placeholder "+placeholder+" left in place")
+		}
+		if file == "tasks.md" {
+			if match := taskRegex.FindStringSubmatch(line); match != nil && !lintTaskIDRegex.MatchString(match[2]) {
+				report("task-format", file, i+1, fmt.Sprintf("task %q does not start with a T### ID", strings.TrimSpace(match[2])))
+			}
+		}
+	}
+
+	for _, section := range lintRequiredSections[file] {
+		found := false
+		for heading := range headings {
+			found = found || strings.HasPrefix(heading, section)
+		}
+		if !found {
+			report("required-sections", file, 0, fmt.Sprintf("mandatory section %q is missing", section))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestLintFeature(t *testing.T) {
+	newTestRepo(t)
+
+	writeTestFile(t, "specs/004-user-login/spec.md", "# Feature Specification: [FEATURE NAME]\n"+`
+## Quick Guidelines
+- Mark ambiguities with [NEEDS CLARIFICATION: specific question]
+
+## User Scenarios & Testing *(mandatory)*
+Users log in with email and password.
+
+### Functional Requirements
+- **FR-001**: System MUST lock accounts after [NEEDS CLARIFICATION: how many attempts?]
+`)
+	writeTestFile(t, "specs/004-user-login/tasks.md", `# Tasks: User Login
+
+- [x] T001 Create project structure
+- [ ] Write the login handler
+`)
+
+	f := func(name string, rules map[string]string, expected []string, expectedErrors int) {
+		t.Helper()
+
+		config := models.DefaultProjectConfig()
+		for rule, severity := range rules {
+			if err := config.Set("lint.rules."+rule, severity); err != nil {
+				t.Fatalf("%s: failed to set %s: %v", name, rule, err)
+			}
+		}
+		feature := NewFeatureService(NewFilesystemService(), NewGitService(), config)
+		feature.SelectFeature("4")
+
+		result, err := feature.LintFeature()
+		if err != nil {
+			t.Fatalf("%s: LintFeature: %v", name, err)
+		}
+		findings := []string{}
+		for _, finding := range result.Findings {
+			findings = append(findings, fmt.Sprintf("%s:%d %s %s", finding.File, finding.Line, finding.Severity, finding.Rule))
+		}
+		if !slices.Equal(findings, expected) || result.Errors != expectedErrors {
+			t.Fatalf("%s: got %v with %d errors, expected %v with %d errors", name, findings, result.Errors, expected, expectedErrors)
+		}
+	}
+
+	// Template guidance is not linted, and a missing plan is not required
+	f("defaults", nil, []string{
+		"spec.md:1 warning placeholder-text",
+		"spec.md:10 warning needs-clarification",
+		"spec.md:0 warning required-sections",
+		"tasks.md:4 warning task-format",
+	}, 0)
+	f("severities", map[string]string{"needs-clarification": "error", "placeholder-text": "off", "task-format": "off"}, []string{
+		"spec.md:10 error needs-clarification",
+		"spec.md:0 warning required-sections",
+	}, 1)
+}
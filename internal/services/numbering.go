@@ -0,0 +1,276 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+	"github.com/euforicio/spec-kit/internal/naming"
+)
+
+// maxReservationAttempts bounds how many numbers are tried when reserving
+const maxReservationAttempts = 10
+
+// reservationNumberRegex extracts the number from a reservation ref
+var reservationNumberRegex = regexp.MustCompile(`(\d+)$`)
+
+// knownFeatureNames returns the names of every feature visible from this clone:
+// spec directories in the working tree and on the default branch, and local and
+// remote-tracking branches. Git sources are best-effort; a missing remote or
+// default branch only narrows the search.
+func (f *FeatureService) knownFeatureNames(repoRoot, specsDir string) ([]string, error) {
+	names := []string{}
+
+	// Archived features keep their numbers
+	for _, dir := range []string{specsDir, filepath.Join(specsDir, models.ArchiveDirName)} {
+		if exists, _ := f.filesystem.DirectoryExists(dir); exists {
+			entries, err := f.filesystem.ListDirectory(dir)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, entries...)
+		}
+	}
+
+	if branches, err := f.git.ListBranches(); err == nil {
+		names = append(names, branches...)
+	}
+
+	// Spec directories merged into the default branch, whether or not they
+	// still have a branch
+	if defaultBranch, err := f.git.GetDefaultBranch(f.config.GetNumberingRemote()); err == nil {
+		relSpecs, err := filepath.Rel(repoRoot, specsDir)
+		if err == nil {
+			relSpecs = filepath.ToSlash(relSpecs)
+			remoteRef := f.config.GetNumberingRemote() + "/" + defaultBranch
+			for _, ref := range []string{remoteRef, defaultBranch} {
+				for _, dir := range []string{relSpecs, relSpecs + "/" + models.ArchiveDirName} {
+					if entries, err := f.git.ListTree(ref, dir); err == nil {
+						names = append(names, entries...)
+					}
+				}
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// usedFeatureNumbers maps each feature number in use to the feature directory
+// names using it. Names whose directory is exclude are skipped.
+func (f *FeatureService) usedFeatureNumbers(names []string, exclude string) map[int][]string {
+	used := make(map[int][]string)
+	for _, name := range names {
+		number, ok := f.featureNumber(name)
+		if !ok {
+			continue
+		}
+		dir := f.scheme.DirName(name)
+		if dir == exclude || slices.Contains(used[number], dir) {
+			continue
+		}
+		used[number] = append(used[number], dir)
+	}
+	return used
+}
+
+// reservedFeatureNumbers returns the numbers reserved on the remote
+func (f *FeatureService) reservedFeatureNumbers() ([]int, error) {
+	refs, err := f.git.ListRemoteRefs(f.config.GetNumberingRemote(), models.ReservationRefPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := []int{}
+	for _, ref := range refs {
+		if match := reservationNumberRegex.FindStringSubmatch(ref); match != nil {
+			if number, err := strconv.Atoi(match[1]); err == nil {
+				numbers = append(numbers, number)
+			}
+		}
+	}
+	return numbers, nil
+}
+
+// nextFeatureNumber returns one more than the highest feature number in use,
+// ignoring the feature directory exclude
+func (f *FeatureService) nextFeatureNumber(repoRoot, specsDir, exclude string, reserve bool) (int, error) {
+	names, err := f.knownFeatureNames(repoRoot, specsDir)
+	if err != nil {
+		return 0, err
+	}
+
+	highest := 0
+	for number := range f.usedFeatureNumbers(names, exclude) {
+		highest = max(highest, number)
+	}
+
+	if reserve {
+		reserved, err := f.reservedFeatureNumbers()
+		if err != nil {
+			return 0, fmt.Errorf("failed to list reserved feature numbers: %w", err)
+		}
+		for _, number := range reserved {
+			highest = max(highest, number)
+		}
+	}
+
+	return highest + 1, nil
+}
+
+// reservationRef returns the ref that reserves a feature number
+func (f *FeatureService) reservationRef(number int) string {
+	return models.ReservationRefPrefix + f.scheme.FormatNumber(number)
+}
+
+// reserveFeatureNumber pushes a reservation ref for number. When another clone
+// reserved it first, the following numbers are tried. It returns the reserved
+// number and its ref.
+func (f *FeatureService) reserveFeatureNumber(number int) (int, string, error) {
+	remote := f.config.GetNumberingRemote()
+
+	for range maxReservationAttempts {
+		ref := f.reservationRef(number)
+		pushErr := f.git.PushNewRef(remote, ref, "Reserve feature number "+f.scheme.FormatNumber(number))
+		if pushErr == nil {
+			return number, ref, nil
+		}
+
+		// Only a lost race moves on to the next number
+		reserved, err := f.reservedFeatureNumbers()
+		if err != nil || !slices.Contains(reserved, number) {
+			return 0, "", fmt.Errorf("failed to reserve feature number %d: %w", number, pushErr)
+		}
+		number++
+	}
+
+	return 0, "", fmt.Errorf("failed to reserve a feature number on %s after %d attempts", remote, maxReservationAttempts)
+}
+
+// releaseReservation deletes a reservation ref when the feature it reserved
+// a number for could not be created, so the number can be used again
+func (f *FeatureService) releaseReservation(ref string) error {
+	return f.git.DeleteRemoteRef(f.config.GetNumberingRemote(), ref)
+}
+
+// RenumberFeature gives the current feature a new number, renaming its branch
+// and spec directory. Without an explicit number the feature is only
+// renumbered when another feature already uses its number.
+func (f *FeatureService) RenumberFeature(opts FeatureRenumberOptions) (result *models.FeatureRenumberResult, err error) {
+	if !f.scheme.HasPlaceholder(naming.PlaceholderNum) {
+		return nil, fmt.Errorf("branch pattern %s has no {num} to renumber", f.scheme.Pattern())
+	}
+
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	currentBranch, err := f.git.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	number, ok := f.featureNumber(currentBranch)
+	if !ok {
+		return nil, fmt.Errorf("not on a feature branch. Current branch: %s. Feature branches should match the pattern: %s", currentBranch, f.scheme.Pattern())
+	}
+
+	specsDir := f.specsDir(repoRoot)
+	oldDir := f.scheme.DirName(currentBranch)
+
+	names, err := f.knownFeatureNames(repoRoot, specsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+	used := f.usedFeatureNumbers(names, oldDir)
+
+	result = &models.FeatureRenumberResult{
+		OldBranch:  currentBranch,
+		NewBranch:  currentBranch,
+		OldNumber:  f.scheme.FormatNumber(number),
+		NewNumber:  f.scheme.FormatNumber(number),
+		Collisions: append([]string{}, used[number]...),
+	}
+
+	target := opts.Number
+	switch {
+	case target == 0 && len(result.Collisions) == 0:
+		return result, nil
+	case target == number:
+		return nil, fmt.Errorf("feature is already numbered %s", result.OldNumber)
+	case target != 0 && len(used[target]) > 0:
+		return nil, fmt.Errorf("feature number %s is already used by %s",
+			f.scheme.FormatNumber(target), strings.Join(used[target], ", "))
+	case target == 0:
+		if target, err = f.nextFeatureNumber(repoRoot, specsDir, oldDir, opts.Reserve); err != nil {
+			return nil, fmt.Errorf("failed to get next feature number: %w", err)
+		}
+	}
+
+	newBranch, err := f.renumberedBranch(currentBranch, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reserve the number only once the branch is known to be free, and release
+	// the reservation when the feature cannot be moved
+	if opts.Reserve {
+		var reserved int
+		var reservation string
+		if reserved, reservation, err = f.reserveFeatureNumber(target); err != nil {
+			return nil, err
+		}
+		result.Reservation = reservation
+		defer func() {
+			if err != nil {
+				if releaseErr := f.releaseReservation(reservation); releaseErr != nil {
+					err = fmt.Errorf("%w (the reservation %s was not released: %v)", err, reservation, releaseErr)
+				}
+			}
+		}()
+
+		if reserved != target {
+			target = reserved
+			if newBranch, err = f.renumberedBranch(currentBranch, target); err != nil {
+				return nil, err
+			}
+		}
+	}
+	newDir := f.scheme.DirName(newBranch)
+
+	featureDir, updated, err := f.moveFeature(currentBranch, newBranch,
+		filepath.Join(specsDir, oldDir), filepath.Join(specsDir, newDir))
+	if err != nil {
+		return nil, err
+	}
+	result.FeatureDir = featureDir
+	result.UpdatedFiles = updated
+
+	result.NewBranch = newBranch
+	result.NewNumber = f.scheme.FormatNumber(target)
+	result.Renumbered = true
+	return result, nil
+}
+
+// renumberedBranch returns the name of branch with number and checks the
+// branch does not exist yet
+func (f *FeatureService) renumberedBranch(branch string, number int) (string, error) {
+	newBranch, ok := f.scheme.Renumber(branch, number)
+	if !ok {
+		return "", fmt.Errorf("branch %s does not match the pattern %s", branch, f.scheme.Pattern())
+	}
+
+	exists, err := f.git.BranchExists(newBranch)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", fmt.Errorf("%w: %s", models.ErrBranchExists, newBranch)
+	}
+	return newBranch, nil
+}
@@ -109,9 +109,13 @@ func (p *ProjectService) InitializeProject(options ProjectInitOptions) (*Project
 
 // validatePrerequisites checks if all prerequisites are met for project initialization
 func (p *ProjectService) validatePrerequisites(env *models.Environment, options ProjectInitOptions) error {
-	// Check internet connectivity
-	if !env.HasInternet {
-		return fmt.Errorf("%w: internet connection is required to download templates (check your network connection and try again)", models.ErrInternetNotAvailable)
+	// Check that templates can be downloaded
+	if !env.TemplateSourceAvailable() {
+		failures := "check your network connection and try again"
+		if env.Connectivity != nil && env.Connectivity.Failures() != "" {
+			failures = env.Connectivity.Failures()
+		}
+		return fmt.Errorf("%w: the template source is required to download templates (%s)", models.ErrInternetNotAvailable, failures)
 	}
 
 	// Check AI assistant tools (unless ignored)
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// PullRequestOptions contains options for composing a pull request
+type PullRequestOptions struct {
+	Base  string // branch to merge into; empty uses the default branch
+	Draft bool
+}
+
+// Precompiled regexes for reading feature artifacts
+var (
+	requirementRegex = regexp.MustCompile(`^\s*[-*]\s+\*\*([A-Z]+-\d+)\*\*:?\s*(.+)$`)
+	taskRegex        = regexp.MustCompile(`^\s*[-*]\s+\[([ xX])\]\s+(.+)$`)
+	inputRegex       = regexp.MustCompile(`^\*\*Input\*\*:\s*User description:\s*"(.+)"\s*$`)
+	htmlCommentRegex = regexp.MustCompile(`(?s)<!--.*?-->`) // template guidance
+)
+
+// maxOpenTasks caps the open tasks listed in a pull request body
+const maxOpenTasks = 20
+
+// ComposePullRequest builds a pull request title and body for the current
+// feature from its spec summary and requirements, the plan's technical
+// decisions and the task completion in tasks.md
+func (f *FeatureService) ComposePullRequest(opts PullRequestOptions) (*models.PullRequest, error) {
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	feature, err := f.currentFeature(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	specFile := filepath.Join(feature.Dir, "spec.md")
+	if exists, _ := f.filesystem.FileExists(specFile); !exists {
+		return nil, fmt.Errorf("spec.md not found in %s. Run 'specify feature create' first", feature.Dir)
+	}
+	spec, err := f.filesystem.ReadFile(specFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	// The plan and tasks are optional; missing files leave their sections out
+	plan, _ := f.filesystem.ReadFile(filepath.Join(feature.Dir, "plan.md"))
+	tasks, _ := f.filesystem.ReadFile(filepath.Join(feature.Dir, "tasks.md"))
+
+	base := opts.Base
+	if base == "" {
+		if base, err = f.git.GetDefaultBranch(f.config.GetNumberingRemote()); err != nil {
+			return nil, fmt.Errorf("failed to determine the base branch, use --base: %w", err)
+		}
+	}
+
+	relDir, err := filepath.Rel(repoRoot, feature.Dir)
+	if err != nil {
+		relDir = feature.Dir
+	}
+
+	return &models.PullRequest{
+		Title: f.pullRequestTitle(feature.Dir, specFile),
+		Body:  pullRequestBody(filepath.ToSlash(relDir), spec, plan, tasks),
+		Head:  feature.Branch,
+		Base:  base,
+		Draft: opts.Draft,
+	}, nil
+}
+
+// pullRequestTitle is "feat(<scope>): <spec title>", scoped like automatic
+// commits, falling back to the directory name when the spec has no title
+func (f *FeatureService) pullRequestTitle(featureDir, specFile string) string {
+	title := f.specTitle(specFile)
+	if title == "" {
+		title = filepath.Base(featureDir)
+	}
+	return fmt.Sprintf("feat(%s): %s", f.commitScope(featureDir), title)
+}
+
+// pullRequestBody renders the pull request description in Markdown
+func pullRequestBody(featureDir, spec, plan, tasks string) string {
+	var body strings.Builder
+
+	if summary := featureSummary(spec, plan); summary != "" {
+		fmt.Fprintf(&body, "## Summary\n\n%s\n\n", summary)
+	}
+
+	if requirements := parseRequirements(spec); len(requirements) > 0 {
+		body.WriteString("## Requirements\n\n")
+		for _, requirement := range requirements {
+			fmt.Fprintf(&body, "- %s\n", requirement)
+		}
+		body.WriteString("\n")
+	}
+
+	if decisions := techDecisions(ParseTechStack(plan)); plan != "" && len(decisions) > 0 {
+		body.WriteString("## Technical Decisions\n\n")
+		for _, decision := range decisions {
+			fmt.Fprintf(&body, "- %s\n", decision)
+		}
+		body.WriteString("\n")
+	}
+
+	if progress := parseTaskProgress(tasks); progress.Total > 0 {
+		fmt.Fprintf(&body, "## Tasks\n\n%d of %d tasks complete.\n\n", progress.Done, progress.Total)
+		if len(progress.Open) > 0 {
+			fmt.Fprintf(&body, "<details>\n<summary>Open tasks (%d)</summary>\n\n", len(progress.Open))
+			for i, task := range progress.Open {
+				if i == maxOpenTasks {
+					fmt.Fprintf(&body, "- … and %d more\n", len(progress.Open)-maxOpenTasks)
+					break
+				}
+				fmt.Fprintf(&body, "- [ ] %s\n", task)
+			}
+			body.WriteString("\n</details>\n\n")
+		}
+	}
+
+	artifacts := []string{fmt.Sprintf("Spec: `%s/spec.md`", featureDir)}
+	if plan != "" {
+		artifacts = append(artifacts, fmt.Sprintf("Plan: `%s/plan.md`", featureDir))
+	}
+	if tasks != "" {
+		artifacts = append(artifacts, fmt.Sprintf("Tasks: `%s/tasks.md`", featureDir))
+	}
+	fmt.Fprintf(&body, "---\n%s\n", strings.Join(artifacts, " · "))
+
+	return body.String()
+}
+
+// featureSummary returns the plan summary, falling back to the spec's primary
+// user story and then to the description the spec was created from
+func featureSummary(spec, plan string) string {
+	if summary := markdownSection(plan, "Summary"); summary != "" {
+		return summary
+	}
+	if story := markdownSection(spec, "Primary User Story"); story != "" {
+		return story
+	}
+	for _, line := range strings.Split(spec, "\n") {
+		if match := inputRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil && !strings.Contains(match[1], "$ARGUMENTS") {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// markdownSection returns the text under the first heading starting with
+// heading, up to the next heading of the same or a higher level. Template
+// placeholders and HTML comments count as empty.
+func markdownSection(content, heading string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		level := markdownHeadingLevel(line)
+		if level == 0 || !strings.HasPrefix(strings.TrimSpace(strings.TrimLeft(line, "#")), heading) {
+			continue
+		}
+
+		section := []string{}
+		for _, next := range lines[i+1:] {
+			if nextLevel := markdownHeadingLevel(next); nextLevel > 0 && nextLevel <= level {
+				break
+			}
+			section = append(section, next)
+		}
+
+		text := strings.TrimSpace(htmlCommentRegex.ReplaceAllString(strings.Join(section, "\n"), ""))
+		if techPlaceholderRe.MatchString(text) {
+			return ""
+		}
+		return text
+	}
+	return ""
+}
+
+// markdownHeadingLevel returns the level of an ATX heading, or 0
+func markdownHeadingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || !strings.HasPrefix(line[level:], " ") {
+		return 0
+	}
+	return level
+}
+
+// parseRequirements returns the spec's "- **FR-001**: ..." requirements as
+// "**FR-001**: ..." list items
+func parseRequirements(spec string) []string {
+	requirements := []string{}
+	for _, line := range strings.Split(markdownSection(spec, "Requirements"), "\n") {
+		if match := requirementRegex.FindStringSubmatch(line); match != nil {
+			requirements = append(requirements, fmt.Sprintf("**%s**: %s", match[1], strings.TrimSpace(match[2])))
+		}
+	}
+	return requirements
+}
+
+// techDecisions lists the resolved Technical Context fields of a plan
+func techDecisions(stack models.TechStack) []string {
+	decisions := []string{}
+	add := func(label string, values []string) {
+		if len(values) > 0 {
+			decisions = append(decisions, fmt.Sprintf("**%s**: %s", label, strings.Join(values, ", ")))
+		}
+	}
+
+	languages := []string{}
+	for _, language := range stack.Languages {
+		languages = append(languages, language.String())
+	}
+	add("Language", languages)
+	add("Dependencies", stack.Frameworks)
+	add("Storage", stack.Storage)
+	add("Testing", stack.Testing)
+	add("Target platform", stack.TargetPlatforms)
+	if stack.ProjectType != "" {
+		add("Project type", []string{stack.ProjectType})
+	}
+	add("Performance goals", stack.PerformanceGoals)
+	add("Constraints", stack.Constraints)
+
+	return decisions
+}
+
+// parseTaskProgress counts the checkbox tasks of a tasks.md file
+func parseTaskProgress(tasks string) models.TaskProgress {
+	progress := models.TaskProgress{}
+	for _, line := range strings.Split(tasks, "\n") {
+		match := taskRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		progress.Total++
+		if match[1] == " " {
+			progress.Open = append(progress.Open, strings.TrimSpace(match[2]))
+		} else {
+			progress.Done++
+		}
+	}
+	return progress
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestComposePullRequest(t *testing.T) {
+	newTestRepo(t)
+	feature := NewFeatureService(NewFilesystemService(), NewGitService(), nil)
+
+	writeTestFile(t, "specs/004-user-login/spec.md", `# Feature Specification: User Login
+
+**Feature Branch**: `+"`004-user-login`"+`
+**Input**: User description: "let users log in"
+
+## User Scenarios & Testing *(mandatory)*
+
+### Primary User Story
+[Describe the main user journey in plain language]
+
+## Requirements *(mandatory)*
+
+### Functional Requirements
+- **FR-001**: System MUST allow users to log in with email and password
+- **FR-002**: System MUST lock accounts after 5 failed attempts
+`)
+	writeTestFile(t, "specs/004-user-login/plan.md", `# Implementation Plan: User Login
+
+## Summary
+Add session-based login backed by the existing user store.
+
+## Technical Context
+**Language/Version**: Go 1.25
+**Primary Dependencies**: chi, bcrypt
+**Storage**: PostgreSQL
+**Testing**: go test
+**Target Platform**: [e.g., Linux server or NEEDS CLARIFICATION]
+`)
+	writeTestFile(t, "specs/004-user-login/tasks.md", `# Tasks: User Login
+
+- [x] T001 Create project structure
+- [X] T002 [P] Contract test POST /login
+- [ ] T003 Login handler
+`)
+	feature.SelectFeature("4")
+
+	pr, err := feature.ComposePullRequest(PullRequestOptions{Draft: true})
+	if err != nil {
+		t.Fatalf("ComposePullRequest: %v", err)
+	}
+	if pr.Title != "feat(004): User Login" || pr.Head != "004-user-login" || pr.Base != "main" || !pr.Draft {
+		t.Fatalf("ComposePullRequest() = %+v", pr)
+	}
+
+	expected := `## Summary
+
+Add session-based login backed by the existing user store.
+
+## Requirements
+
+- **FR-001**: System MUST allow users to log in with email and password
+- **FR-002**: System MUST lock accounts after 5 failed attempts
+
+## Technical Decisions
+
+- **Language**: Go 1.25
+- **Dependencies**: chi, bcrypt
+- **Storage**: PostgreSQL
+- **Testing**: go test
+
+## Tasks
+
+2 of 3 tasks complete.
+
+<details>
+<summary>Open tasks (1)</summary>
+
+- [ ] T003 Login handler
+
+</details>
+
+---
+Spec: ` + "`specs/004-user-login/spec.md`" + ` · Plan: ` + "`specs/004-user-login/plan.md`" + ` · Tasks: ` + "`specs/004-user-login/tasks.md`" + `
+`
+	if pr.Body != expected {
+		t.Fatalf("body:\n%s\nexpected:\n%s", pr.Body, expected)
+	}
+
+	// Without a plan the summary falls back to the description
+	writeTestFile(t, "specs/005-search/spec.md", "# Feature Specification: Search\n\n**Input**: User description: \"find things\"\n")
+	feature.SelectFeature("5")
+	if pr, err = feature.ComposePullRequest(PullRequestOptions{Base: "develop"}); err != nil {
+		t.Fatalf("ComposePullRequest: %v", err)
+	}
+	if expected := "## Summary\n\nfind things\n\n---\nSpec: `specs/005-search/spec.md`\n"; pr.Body != expected || pr.Base != "develop" {
+		t.Fatalf("body:\n%s\nexpected:\n%s", pr.Body, expected)
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/widgets/pulls" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		if received["head"] == "missing" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Validation Failed","errors":[{"resource":"PullRequest","field":"head","code":"invalid"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number":42,"html_url":"https://github.com/acme/widgets/pull/42","head":{"ref":"004-user-login"}}`))
+	}))
+	defer server.Close()
+
+	github := NewGitHubServiceWithClient(server.Client(), "acme", "widgets")
+	github.baseURL = server.URL
+	repo := models.GitHubRepository{Owner: "acme", Name: "widgets"}
+	pr := &models.PullRequest{Title: "feat(004): User Login", Body: "body", Head: "004-user-login", Base: "main", Draft: true}
+
+	if _, err := github.CreatePullRequest(repo, pr); !errors.Is(err, models.ErrGitHubAuthRequired) {
+		t.Fatalf("CreatePullRequest without a token: %v", err)
+	}
+
+	github.SetToken("secret")
+	created, err := github.CreatePullRequest(repo, pr)
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if created.Number != 42 || created.URL != "https://github.com/acme/widgets/pull/42" || created.Title != pr.Title {
+		t.Fatalf("CreatePullRequest() = %+v", created)
+	}
+	if received["title"] != pr.Title || received["base"] != "main" || received["draft"] != true {
+		t.Fatalf("request payload = %v", received)
+	}
+
+	pr.Head = "missing"
+	if _, err := github.CreatePullRequest(repo, pr); !errors.Is(err, models.ErrGitHubRequest) ||
+		err.Error() != "GitHub request failed: creating a pull request on acme/widgets returned status 422: Validation Failed (head invalid)" {
+		t.Fatalf("CreatePullRequest with an unknown head: %v", err)
+	}
+}
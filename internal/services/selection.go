@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// FeatureEnvVar selects the feature when --feature is not given
+const FeatureEnvVar = "SPECIFY_FEATURE"
+
+// featureRef identifies the feature a command operates on
+type featureRef struct {
+	Branch string // feature branch, or the directory name when no branch exists
+	Dir    string // absolute feature directory
+}
+
+// SelectFeature makes the service operate on the feature identified by
+// selector instead of the one named by the current branch. A selector is a
+// feature number (4, 004), a ticket, a slug, a directory name or a path to the
+// feature directory; an empty selector restores branch detection.
+func (f *FeatureService) SelectFeature(selector string) {
+	f.selector = strings.TrimSpace(selector)
+}
+
+// currentFeature resolves the feature from the selector, falling back to the
+// current branch
+func (f *FeatureService) currentFeature(repoRoot string) (*featureRef, error) {
+	specsDir := f.specsDir(repoRoot)
+
+	if f.selector != "" {
+		dir, err := f.findFeatureDir(specsDir, f.selector)
+		if err != nil {
+			return nil, err
+		}
+		return &featureRef{Branch: f.featureBranch(filepath.Base(dir)), Dir: dir}, nil
+	}
+
+	currentBranch, err := f.git.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w. Use --feature or %s to select a feature", err, FeatureEnvVar)
+	}
+
+	if !f.isFeatureBranch(currentBranch) {
+		return nil, fmt.Errorf("not on a feature branch. Current branch: %s. Feature branches should match the pattern: %s. Use --feature or %s to select a feature",
+			currentBranch, f.scheme.Pattern(), FeatureEnvVar)
+	}
+
+	return &featureRef{
+		Branch: currentBranch,
+		Dir:    filepath.Join(specsDir, f.scheme.DirName(currentBranch)),
+	}, nil
+}
+
+// findFeatureDir resolves a selector to an existing feature directory
+func (f *FeatureService) findFeatureDir(specsDir, selector string) (string, error) {
+	// Paths must name a feature directory in specsDir or its archive
+	if strings.ContainsRune(selector, os.PathSeparator) || strings.Contains(selector, "/") || selector == "." {
+		dir, err := filepath.Abs(selector)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", selector, err)
+		}
+		if exists, _ := f.filesystem.DirectoryExists(dir); !exists {
+			return "", fmt.Errorf("%w: directory %s does not exist", models.ErrFeatureNotFound, dir)
+		}
+		parent := filepath.Dir(dir)
+		if !sameDirectory(parent, specsDir) && !sameDirectory(parent, filepath.Join(specsDir, models.ArchiveDirName)) {
+			return "", fmt.Errorf("%w: %s is not a feature directory in %s", models.ErrFeatureNotFound, dir, specsDir)
+		}
+		return dir, nil
+	}
+
+	entries := []string{}
+	if exists, _ := f.filesystem.DirectoryExists(specsDir); exists {
+		var err error
+		if entries, err = f.filesystem.ListDirectory(specsDir); err != nil {
+			return "", fmt.Errorf("failed to list features: %w", err)
+		}
+	}
+
+	number, isNumber := f.selectorNumber(selector)
+	matches := []string{}
+	for _, entry := range entries {
+		parsed, ok := f.scheme.Parse(entry)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case entry == selector:
+			// An exact directory name always wins
+			return filepath.Join(specsDir, entry), nil
+		case isNumber && parsed.HasNumber && parsed.Number == number,
+			strings.EqualFold(parsed.Ticket, selector),
+			parsed.Slug == selector:
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: no feature in %s matches %q", models.ErrFeatureNotFound, specsDir, selector)
+	case 1:
+		return filepath.Join(specsDir, matches[0]), nil
+	default:
+		return "", fmt.Errorf("%w: %q matches %s", models.ErrFeatureAmbiguous, selector, strings.Join(matches, ", "))
+	}
+}
+
+// sameDirectory reports whether two paths name the same directory, following
+// symbolic links
+func sameDirectory(a, b string) bool {
+	resolve := func(path string) string {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return resolved
+		}
+		return filepath.Clean(path)
+	}
+	return resolve(a) == resolve(b)
+}
+
+// selectorNumber parses a feature number, with or without the numbering prefix
+func (f *FeatureService) selectorNumber(selector string) (int, bool) {
+	digits := selector
+	if prefix := f.config.Numbering.Prefix; prefix != "" && len(selector) > len(prefix) &&
+		strings.EqualFold(selector[:len(prefix)], prefix) {
+		digits = selector[len(prefix):]
+	}
+
+	number, err := strconv.Atoi(digits)
+	if err != nil || number < 1 {
+		return 0, false
+	}
+	return number, true
+}
+
+// featureBranch returns the branch of a feature directory: the current branch
+// or another local branch named after it, or the directory name when none is
+func (f *FeatureService) featureBranch(dirName string) string {
+	if current, err := f.git.GetCurrentBranch(); err == nil && f.isFeatureBranch(current) && f.scheme.DirName(current) == dirName {
+		return current
+	}
+
+	if branches, err := f.git.ListBranches(); err == nil {
+		for _, branch := range branches {
+			if f.isFeatureBranch(branch) && f.scheme.DirName(branch) == dirName {
+				return branch
+			}
+		}
+	}
+
+	return dirName
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestFindFeatureDir(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	specsDir := filepath.Join(root, "specs")
+	for _, dir := range []string{"001-user-login", "002-search", "003-search", "_archive/004-export", "notes", "../src"} {
+		if err := os.MkdirAll(filepath.Join(specsDir, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(filepath.Join(specsDir, "002-search"))
+
+	newFeature := func(settings ...string) *FeatureService {
+		t.Helper()
+		config := models.DefaultProjectConfig()
+		for i := 0; i < len(settings); i += 2 {
+			if err := config.Set(settings[i], settings[i+1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return NewFeatureService(NewFilesystemService(), NewGitService(), config)
+	}
+
+	f := func(feature *FeatureService, selector, expected string, expectedErr error) {
+		t.Helper()
+		dir, err := feature.findFeatureDir(specsDir, selector)
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("%q: got error %v, expected %v", selector, err, expectedErr)
+		}
+		if err == nil && dir != filepath.Join(specsDir, expected) {
+			t.Fatalf("%q: got %s, expected %s", selector, dir, expected)
+		}
+	}
+
+	feature := newFeature()
+
+	// Numbers, with or without padding, slugs and exact directory names
+	f(feature, "1", "001-user-login", nil)
+	f(feature, "001", "001-user-login", nil)
+	f(feature, "user-login", "001-user-login", nil)
+	f(feature, "003-search", "003-search", nil)
+	f(feature, "search", "", models.ErrFeatureAmbiguous)
+	f(feature, "9", "", models.ErrFeatureNotFound)
+	f(feature, "0", "", models.ErrFeatureNotFound)
+	f(feature, "notes", "", models.ErrFeatureNotFound)
+
+	// Paths inside the specs directory or its archive
+	f(feature, ".", "002-search", nil)
+	f(feature, "../001-user-login", "001-user-login", nil)
+	f(feature, filepath.Join(specsDir, "_archive", "004-export"), "_archive/004-export", nil)
+	f(feature, filepath.Join(specsDir, "005-missing"), "", models.ErrFeatureNotFound)
+	f(feature, filepath.Join(root, "src"), "", models.ErrFeatureNotFound)
+	f(feature, specsDir, "", models.ErrFeatureNotFound)
+	f(feature, "/tmp", "", models.ErrFeatureNotFound)
+
+	// A numbering prefix is optional in the selector
+	prefixed := newFeature("numbering.prefix", "FEAT-")
+	if err := os.MkdirAll(filepath.Join(specsDir, "FEAT-006-billing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f(prefixed, "6", "FEAT-006-billing", nil)
+	f(prefixed, "feat-006", "FEAT-006-billing", nil)
+
+	// Tickets match case-insensitively
+	ticketed := newFeature("branch.pattern", "{ticket}-{slug}")
+	if err := os.MkdirAll(filepath.Join(specsDir, "PROJ-12-export"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f(ticketed, "proj-12", "PROJ-12-export", nil)
+	f(ticketed, "PROJ-13", "", models.ErrFeatureNotFound)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// releaseTemplateKey is the minisign public key of the MINISIGN_SECRET_KEY the
+// release workflow signs template bundles with. Release builds pin it with
+// -ldflags "-X .../internal/services.releaseTemplateKey=<key>"; development
+// builds trust only templates.trusted_keys.
+var releaseTemplateKey string
+
+// PinnedTemplateKeys returns the minisign public keys built into this binary
+// as trusted to sign the template bundles of spec-kit releases
+func PinnedTemplateKeys() []string {
+	if releaseTemplateKey == "" {
+		return nil
+	}
+	return []string{releaseTemplateKey}
+}
+
+// signatureSuffix names the detached signature asset of a release asset
+const signatureSuffix = ".minisig"
+
+// TemplateSignature describes a verified template bundle signature
+type TemplateSignature struct {
+	KeyID          string `json:"key_id"`
+	TrustedComment string `json:"trusted_comment"`
+}
+
+// VerifyReleaseSignature verifies the file at path, downloaded from asset,
+// against the "<asset>.minisig" signature published with release. It fails
+// with ErrTemplateUnsigned when there is no signature or it was made by a key
+// not in keys, and with ErrSignatureInvalid when the signature does not match.
+func (g *GitHubService) VerifyReleaseSignature(release *GitHubRelease, asset *GitHubAsset, path string, keys []string) (*TemplateSignature, error) {
+	for _, candidate := range release.Assets {
+		if candidate.Name != asset.Name+signatureSuffix {
+			continue
+		}
+
+		var signature bytes.Buffer
+		if err := g.DownloadAsset(&candidate, &signature); err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", candidate.Name, err)
+		}
+		return VerifyMinisign(path, signature.Bytes(), keys)
+	}
+	return nil, fmt.Errorf("%w: release %s publishes no %s", models.ErrTemplateUnsigned, release.TagName, asset.Name+signatureSuffix)
+}
+
+// minisignSignature is a parsed minisign signature file
+type minisignSignature struct {
+	algorithm       string // "Ed" signs the file, "ED" its BLAKE2b-512 hash
+	keyID           [8]byte
+	signature       []byte
+	trustedComment  string
+	globalSignature []byte // signs signature and trustedComment
+}
+
+// parseMinisignSignature parses the four lines of a minisign signature file
+func parseMinisignSignature(data []byte) (*minisignSignature, error) {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment:") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return nil, fmt.Errorf("%w: not a minisign signature", models.ErrSignatureInvalid)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(signature) != 2+8+ed25519.SignatureSize {
+		return nil, fmt.Errorf("%w: malformed minisign signature", models.ErrSignatureInvalid)
+	}
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("%w: malformed minisign global signature", models.ErrSignatureInvalid)
+	}
+
+	parsed := &minisignSignature{
+		algorithm:       string(signature[:2]),
+		signature:       signature[10:],
+		trustedComment:  strings.TrimPrefix(lines[2], "trusted comment: "),
+		globalSignature: global,
+	}
+	copy(parsed.keyID[:], signature[2:10])
+	if parsed.algorithm != "Ed" && parsed.algorithm != "ED" {
+		return nil, fmt.Errorf("%w: unsupported signature algorithm %q", models.ErrSignatureInvalid, parsed.algorithm)
+	}
+	return parsed, nil
+}
+
+// VerifyMinisign verifies the file at path against a minisign signature made
+// by one of keys, including the signature of its trusted comment
+func VerifyMinisign(path string, signature []byte, keys []string) (*TemplateSignature, error) {
+	parsed, err := parseMinisignSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	var key *models.MinisignPublicKey
+	for _, value := range keys {
+		if candidate, err := models.ParseMinisignPublicKey(value); err == nil && candidate.ID == parsed.keyID {
+			key = candidate
+			break
+		}
+	}
+	signer := (&models.MinisignPublicKey{ID: parsed.keyID}).KeyID()
+	if key == nil {
+		return nil, fmt.Errorf("%w: signed by unknown key %s", models.ErrTemplateUnsigned, signer)
+	}
+
+	message, err := minisignMessage(path, parsed.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(key.Key, message, parsed.signature) {
+		return nil, fmt.Errorf("%w: %s does not match its signature by key %s", models.ErrSignatureInvalid, path, signer)
+	}
+	if !ed25519.Verify(key.Key, append(parsed.signature, parsed.trustedComment...), parsed.globalSignature) {
+		return nil, fmt.Errorf("%w: the trusted comment of key %s's signature was altered", models.ErrSignatureInvalid, signer)
+	}
+
+	return &TemplateSignature{KeyID: signer, TrustedComment: parsed.trustedComment}, nil
+}
+
+// minisignMessage returns what a signature of algorithm signs: the file itself
+// or its BLAKE2b-512 hash
+func minisignMessage(path, algorithm string) ([]byte, error) {
+	if algorithm == "Ed" {
+		return os.ReadFile(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hasher, err := blake2b.New512(nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(hasher, file); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// testMinisignKey generates a key pair and its minisign public key line
+func testMinisignKey(t *testing.T, id byte) (ed25519.PrivateKey, string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID := []byte{id, 2, 3, 4, 5, 6, 7, 8}
+	return private, base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), public...))
+}
+
+// testMinisign signs data like 'minisign -S', prehashed unless algorithm is "Ed"
+func testMinisign(private ed25519.PrivateKey, id byte, algorithm string, data []byte, comment string) []byte {
+	message := data
+	if algorithm == "ED" {
+		sum := blake2b.Sum512(data)
+		message = sum[:]
+	}
+	signature := ed25519.Sign(private, message)
+	global := ed25519.Sign(private, append(append([]byte{}, signature...), comment...))
+
+	keyID := []byte{id, 2, 3, 4, 5, 6, 7, 8}
+	return fmt.Appendf(nil, "untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), keyID...), signature...)),
+		comment, base64.StdEncoding.EncodeToString(global))
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	private, public := testMinisignKey(t, 1)
+	other, otherPublic := testMinisignKey(t, 9)
+
+	data := []byte("cache template bundle")
+	path := filepath.Join(t.TempDir(), "spec-kit-cache-template.zip")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := func(signature []byte, keys []string, want error) {
+		t.Helper()
+		result, err := VerifyMinisign(path, signature, keys)
+		if !errors.Is(err, want) {
+			t.Fatalf("got error %v, want %v", err, want)
+		}
+		if want == nil && (result.KeyID != "0807060504030201" || result.TrustedComment != "spec-kit v1.0.0") {
+			t.Errorf("got %+v", result)
+		}
+	}
+
+	f(testMinisign(private, 1, "ED", data, "spec-kit v1.0.0"), []string{otherPublic, public}, nil)
+	f(testMinisign(private, 1, "Ed", data, "spec-kit v1.0.0"), []string{"untrusted comment: minisign public key\n" + public}, nil)
+
+	// A key that is not trusted
+	f(testMinisign(other, 9, "ED", data, "spec-kit v1.0.0"), []string{public}, models.ErrTemplateUnsigned)
+	f(testMinisign(private, 1, "ED", data, "spec-kit v1.0.0"), nil, models.ErrTemplateUnsigned)
+
+	// A tampered bundle, a forged signature and an altered trusted comment
+	f(testMinisign(private, 1, "ED", []byte("tampered"), "spec-kit v1.0.0"), []string{public}, models.ErrSignatureInvalid)
+	f(testMinisign(other, 1, "ED", data, "spec-kit v1.0.0"), []string{public}, models.ErrSignatureInvalid)
+	signature, _ := parseMinisignSignature(testMinisign(private, 1, "ED", data, "spec-kit v1.0.0"))
+	f(fmt.Appendf(nil, "untrusted comment: x\n%s\ntrusted comment: spec-kit v6.6.6\n%s\n",
+		base64.StdEncoding.EncodeToString(append(append([]byte("ED"), signature.keyID[:]...), signature.signature...)),
+		base64.StdEncoding.EncodeToString(signature.globalSignature)), []string{public}, models.ErrSignatureInvalid)
+
+	f([]byte("not a signature"), []string{public}, models.ErrSignatureInvalid)
+}
+
+func TestVerifyBundle(t *testing.T) {
+	private, public := testMinisignKey(t, 1)
+	data := []byte("cache template bundle")
+	path := filepath.Join(t.TempDir(), cacheTemplateAssetName)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testMinisign(private, 1, "ED", data, "spec-kit v1.0.0"))
+	}))
+	defer server.Close()
+
+	bundle := GitHubAsset{Name: cacheTemplateAssetName}
+	signed := &GitHubRelease{TagName: "v1.0.0", Assets: []GitHubAsset{bundle,
+		{Name: cacheTemplateAssetName + signatureSuffix, BrowserDownloadURL: server.URL}}}
+	unsigned := &GitHubRelease{TagName: "v1.0.0", Assets: []GitHubAsset{bundle}}
+
+	f := func(release *GitHubRelease, keys []string, allowUnsigned, wantSigned bool, want error) {
+		t.Helper()
+		template := NewTemplateService(NewGitHubServiceWithClient(server.Client(), "acme", "widgets"), NewFilesystemService())
+		template.SetTrustedKeys(keys)
+		template.AllowUnsigned(allowUnsigned)
+		_, verified, err := template.VerifyBundle(release, &bundle, path)
+		if !errors.Is(err, want) || verified != wantSigned {
+			t.Errorf("got %v, %v; want %v, %v", verified, err, wantSigned, want)
+		}
+	}
+
+	// Without a pinned or configured key no signature is trusted
+	f(unsigned, nil, false, false, models.ErrTemplateUnsigned)
+	f(signed, nil, false, false, models.ErrTemplateUnsigned)
+	f(signed, nil, true, false, nil)
+
+	f(signed, []string{public}, false, true, nil)
+	f(unsigned, []string{public}, false, false, models.ErrTemplateUnsigned)
+	f(unsigned, []string{public}, true, false, nil)
+
+	releaseTemplateKey = public
+	defer func() { releaseTemplateKey = "" }()
+	f(signed, nil, false, true, nil)
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// Labels of exported task issues. Every task issue carries taskIssueLabel so
+// exports and imports only list their own issues; the phase label is
+// phaseLabelPrefix followed by the phase name.
+const (
+	taskIssueLabel   = "specify-task"
+	phaseLabelPrefix = "phase: "
+)
+
+// Precompiled regexes for tasks.md and task issues
+var (
+	taskIDRegex     = regexp.MustCompile(`^(T\d+)\s+(\[P\]\s+)?(.+)$`)
+	taskPhaseRegex  = regexp.MustCompile(`^##\s+(Phase\s+[\d.]+)\s*:?\s*(.*)$`)
+	taskRangeRegex  = regexp.MustCompile(`T(\d+)(?:\s*[-–]\s*T(\d+))?`)
+	taskMarkerRegex = regexp.MustCompile(`<!-- specify-task: (\S+)/(T\d+) -->`)
+)
+
+// IssueTracker is the issue API tasks are exported to and imported from;
+// GitHubService implements it
+type IssueTracker interface {
+	ListIssues(repo models.GitHubRepository, label string) ([]models.Issue, error)
+	CreateIssue(repo models.GitHubRepository, issue *models.Issue) (*models.Issue, error)
+	UpdateIssue(repo models.GitHubRepository, issue *models.Issue) (*models.Issue, error)
+}
+
+// ExportTasks creates or updates one issue per task of the current feature.
+// Issues are found again through a marker with the feature and task ID in
+// their body, so exporting twice changes nothing. Done tasks close their
+// issue; issues closed on GitHub are left closed.
+func (f *FeatureService) ExportTasks(tracker IssueTracker, repo models.GitHubRepository) (*models.TaskExportResult, error) {
+	feature, tasksFile, content, err := f.readTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := parseTasks(content)
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no tasks found in %s", tasksFile)
+	}
+
+	key := filepath.Base(feature.Dir)
+	existing, err := f.taskIssues(tracker, repo, key)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := map[string]int{}
+	for id, issue := range existing {
+		numbers[id] = issue.Number
+	}
+
+	// Issues are created in task order; the second pass links dependencies on
+	// tasks whose issues did not exist yet
+	actions := map[string]string{}
+	for pass := 0; pass < 2; pass++ {
+		for _, task := range tasks {
+			current := existing[task.ID]
+			desired := f.taskIssue(key, task, numbers, current)
+
+			switch {
+			case current == nil:
+				created, err := tracker.CreateIssue(repo, desired)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create issue for %s: %w", task.ID, err)
+				}
+				if desired.State == "closed" {
+					desired.Number = created.Number
+					if created, err = tracker.UpdateIssue(repo, desired); err != nil {
+						return nil, fmt.Errorf("failed to close issue for %s: %w", task.ID, err)
+					}
+				}
+				existing[task.ID], numbers[task.ID] = created, created.Number
+				actions[task.ID] = models.TaskIssueCreated
+			case taskIssueChanged(current, desired):
+				updated, err := tracker.UpdateIssue(repo, desired)
+				if err != nil {
+					return nil, fmt.Errorf("failed to update issue #%d for %s: %w", current.Number, task.ID, err)
+				}
+				existing[task.ID] = updated
+				if actions[task.ID] == "" {
+					actions[task.ID] = models.TaskIssueUpdated
+				}
+			}
+		}
+	}
+
+	result := &models.TaskExportResult{Repository: repo.String(), TasksFile: tasksFile, Issues: []models.TaskIssue{}}
+	for _, task := range tasks {
+		issue := existing[task.ID]
+		action := actions[task.ID]
+		if action == "" {
+			action = models.TaskIssueUnchanged
+		}
+		result.Issues = append(result.Issues, models.TaskIssue{
+			Task:   task.ID,
+			Number: issue.Number,
+			URL:    issue.URL,
+			State:  issue.State,
+			Action: action,
+		})
+	}
+	return result, nil
+}
+
+// ImportTasks checks off the tasks of the current feature whose exported issue
+// is closed. Reopened issues do not uncheck tasks.
+func (f *FeatureService) ImportTasks(tracker IssueTracker, repo models.GitHubRepository) (*models.TaskImportResult, error) {
+	feature, tasksFile, content, err := f.readTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := f.taskIssues(tracker, repo, filepath.Base(feature.Dir))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.TaskImportResult{Repository: repo.String(), TasksFile: tasksFile, Checked: []string{}}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		match := taskRegex.FindStringSubmatch(line)
+		if match == nil || match[1] != " " {
+			continue
+		}
+		id := taskIDRegex.FindStringSubmatch(strings.TrimSpace(match[2]))
+		if id == nil || issues[id[1]] == nil || issues[id[1]].State != "closed" {
+			continue
+		}
+		lines[i] = strings.Replace(line, "[ ]", "[x]", 1)
+		result.Checked = append(result.Checked, id[1])
+	}
+
+	if len(result.Checked) > 0 {
+		if err := f.filesystem.WriteFile(tasksFile, strings.Join(lines, "\n")); err != nil {
+			return nil, fmt.Errorf("failed to update tasks: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// readTasks reads tasks.md of the current feature
+func (f *FeatureService) readTasks() (*featureRef, string, string, error) {
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	feature, err := f.currentFeature(repoRoot)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	tasksFile := filepath.Join(feature.Dir, "tasks.md")
+	if exists, _ := f.filesystem.FileExists(tasksFile); !exists {
+		return nil, "", "", fmt.Errorf("tasks.md not found in %s. Generate the tasks first", feature.Dir)
+	}
+	content, err := f.filesystem.ReadFile(tasksFile)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to read tasks: %w", err)
+	}
+	return feature, tasksFile, content, nil
+}
+
+// taskIssues returns the exported issues of a feature by task ID
+func (f *FeatureService) taskIssues(tracker IssueTracker, repo models.GitHubRepository, key string) (map[string]*models.Issue, error) {
+	issues, err := tracker.ListIssues(repo, taskIssueLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task issues: %w", err)
+	}
+
+	byTask := map[string]*models.Issue{}
+	for i := range issues {
+		match := taskMarkerRegex.FindStringSubmatch(issues[i].Body)
+		if match == nil || match[1] != key {
+			continue
+		}
+		// Keep the oldest issue if a task was exported twice
+		if current := byTask[match[2]]; current == nil || issues[i].Number < current.Number {
+			byTask[match[2]] = &issues[i]
+		}
+	}
+	return byTask, nil
+}
+
+// taskIssue renders the issue of a task. Labels added on GitHub are kept and
+// the phase label is replaced; closed issues stay closed.
+func (f *FeatureService) taskIssue(key string, task models.Task, numbers map[string]int, current *models.Issue) *models.Issue {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s\n\n- **Feature**: `%s`\n", task.Description, key)
+	if task.Phase != "" {
+		fmt.Fprintf(&body, "- **Phase**: %s\n", task.Phase)
+	}
+	if task.Parallel {
+		body.WriteString("- **Parallel**: yes\n")
+	}
+	if len(task.DependsOn) > 0 {
+		dependencies := []string{}
+		for _, id := range task.DependsOn {
+			if number, ok := numbers[id]; ok {
+				dependencies = append(dependencies, fmt.Sprintf("#%d (%s)", number, id))
+			} else {
+				dependencies = append(dependencies, id)
+			}
+		}
+		fmt.Fprintf(&body, "- **Depends on**: %s\n", strings.Join(dependencies, ", "))
+	}
+	fmt.Fprintf(&body, "\n<!-- specify-task: %s/%s -->", key, task.ID)
+
+	labels := []string{}
+	state := "open"
+	number := 0
+	if current != nil {
+		for _, label := range current.Labels {
+			if !strings.HasPrefix(label, phaseLabelPrefix) {
+				labels = append(labels, label)
+			}
+		}
+		state, number = current.State, current.Number
+	}
+	if !slices.Contains(labels, taskIssueLabel) {
+		labels = append(labels, taskIssueLabel)
+	}
+	if task.Phase != "" {
+		labels = append(labels, phaseLabelPrefix+task.Phase)
+	}
+	if task.Done {
+		state = "closed"
+	}
+
+	return &models.Issue{
+		Number: number,
+		Title:  fmt.Sprintf("[%s] %s %s", f.commitScope(key), task.ID, task.Description),
+		Body:   body.String(),
+		Labels: labels,
+		State:  state,
+	}
+}
+
+// taskIssueChanged reports whether an issue differs from its rendering
+func taskIssueChanged(current, desired *models.Issue) bool {
+	if current.Title != desired.Title || current.Body != desired.Body || current.State != desired.State ||
+		len(current.Labels) != len(desired.Labels) {
+		return true
+	}
+	for _, label := range desired.Labels {
+		if !slices.Contains(current.Labels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTasks returns the "- [ ] T001 [P] ..." tasks of a tasks.md file with
+// their phase and the dependencies listed under "## Dependencies"
+func parseTasks(content string) []models.Task {
+	tasks := []models.Task{}
+	phase := ""
+	for _, line := range strings.Split(content, "\n") {
+		if markdownHeadingLevel(line) == 2 {
+			phase = ""
+			if match := taskPhaseRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				phase = taskPhaseName(match[1], match[2])
+			}
+			continue
+		}
+
+		match := taskRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		task := taskIDRegex.FindStringSubmatch(strings.TrimSpace(match[2]))
+		if task == nil {
+			continue
+		}
+		tasks = append(tasks, models.Task{
+			ID:          task[1],
+			Description: strings.TrimSpace(task[3]),
+			Phase:       phase,
+			Parallel:    task[2] != "",
+			Done:        match[1] != " ",
+		})
+	}
+
+	dependencies := parseTaskDependencies(markdownSection(content, "Dependencies"), tasks)
+	for i := range tasks {
+		tasks[i].DependsOn = dependencies[tasks[i].ID]
+	}
+	return tasks
+}
+
+// taskPhaseName shortens a phase heading such as "Tests First (TDD) ⚠️ MUST
+// COMPLETE BEFORE 3.3" to "Tests First"
+func taskPhaseName(number, title string) string {
+	if i := strings.IndexAny(title, "(⚠—"); i >= 0 {
+		title = title[:i]
+	}
+	if title = strings.TrimSpace(title); title != "" {
+		return title
+	}
+	return number
+}
+
+// parseTaskDependencies reads lines such as "T008 blocks T009, T015", "Tests
+// (T004-T007) before implementation (T008-T014)" or "T012 depends on T008"
+// and returns the prerequisites of each task
+func parseTaskDependencies(section string, tasks []models.Task) map[string][]string {
+	known := map[string]bool{}
+	for _, task := range tasks {
+		known[task.ID] = true
+	}
+
+	dependencies := map[string][]string{}
+	for _, line := range strings.Split(section, "\n") {
+		for _, keyword := range []string{" blocks ", " before ", " depends on ", " after "} {
+			left, right, ok := strings.Cut(line, keyword)
+			if !ok {
+				continue
+			}
+
+			first, then := taskIDs(left, known), taskIDs(right, known)
+			if keyword == " depends on " || keyword == " after " {
+				first, then = then, first
+			}
+			for _, id := range then {
+				for _, prerequisite := range first {
+					if prerequisite != id && !slices.Contains(dependencies[id], prerequisite) {
+						dependencies[id] = append(dependencies[id], prerequisite)
+					}
+				}
+			}
+			break
+		}
+	}
+	return dependencies
+}
+
+// taskIDs returns the known task IDs mentioned in text, expanding ranges such
+// as T004-T007. Ranges stop at the highest known task number.
+func taskIDs(text string, known map[string]bool) []string {
+	highest := 0
+	for id := range known {
+		if n, err := strconv.Atoi(strings.TrimPrefix(id, "T")); err == nil {
+			highest = max(highest, n)
+		}
+	}
+
+	ids := []string{}
+	for _, match := range taskRangeRegex.FindAllStringSubmatch(text, -1) {
+		from, _ := strconv.Atoi(match[1])
+		to := from
+		if match[2] != "" {
+			to, _ = strconv.Atoi(match[2])
+		}
+		for n := from; n <= min(to, highest); n++ {
+			id := fmt.Sprintf("T%0*d", len(match[1]), n)
+			if known[id] && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// fakeIssues serves the issues API of acme/widgets from memory
+type fakeIssues struct {
+	mu     sync.Mutex
+	issues []map[string]any
+	writes int
+}
+
+func (s *fakeIssues) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var payload map[string]any
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&payload)
+	}
+	labels := func() []map[string]any {
+		names := []map[string]any{}
+		for _, label := range payload["labels"].([]any) {
+			names = append(names, map[string]any{"name": label})
+		}
+		return names
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/widgets/issues":
+		if r.URL.Query().Get("page") != "1" {
+			json.NewEncoder(w).Encode([]any{})
+			return
+		}
+		json.NewEncoder(w).Encode(s.issues)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/issues":
+		s.writes++
+		number := len(s.issues) + 1
+		issue := map[string]any{
+			"number":   number,
+			"title":    payload["title"],
+			"body":     payload["body"],
+			"labels":   labels(),
+			"state":    "open",
+			"html_url": fmt.Sprintf("https://github.com/acme/widgets/issues/%d", number),
+		}
+		s.issues = append(s.issues, issue)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(issue)
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/acme/widgets/issues/"):
+		s.writes++
+		number, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/repos/acme/widgets/issues/"))
+		issue := s.issues[number-1]
+		issue["title"], issue["body"], issue["labels"], issue["state"] = payload["title"], payload["body"], labels(), payload["state"]
+		json.NewEncoder(w).Encode(issue)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestTasksExportImport(t *testing.T) {
+	fake := &fakeIssues{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	github := NewGitHubServiceWithClient(server.Client(), "acme", "widgets")
+	github.baseURL = server.URL
+	github.SetToken("secret")
+	repo := models.GitHubRepository{Owner: "acme", Name: "widgets"}
+
+	newTestRepo(t)
+	feature := NewFeatureService(NewFilesystemService(), NewGitService(), nil)
+	writeTestFile(t, "specs/004-user-login/spec.md", "# Feature Specification: User Login\n")
+	writeTestFile(t, "specs/004-user-login/tasks.md", `# Tasks: User Login
+
+## Phase 3.1: Setup
+- [x] T001 Create project structure
+
+## Phase 3.2: Tests First (TDD) ⚠️ MUST COMPLETE BEFORE 3.3
+- [ ] T002 [P] Contract test POST /login
+- [ ] T003 [P] Contract test POST /logout
+
+## Phase 3.3: Core Implementation
+- [ ] T004 Login handler
+
+## Dependencies
+- Tests (T002-T003) before implementation (T004)
+- T001 blocks T002
+
+## Validation Checklist
+- [ ] All contracts have corresponding tests
+`)
+	feature.SelectFeature("4")
+
+	result, err := feature.ExportTasks(github, repo)
+	if err != nil {
+		t.Fatalf("ExportTasks: %v", err)
+	}
+	if len(result.Issues) != 4 || result.Issues[0].State != "closed" || result.Issues[3].Action != models.TaskIssueCreated {
+		t.Fatalf("ExportTasks() = %+v", result.Issues)
+	}
+
+	issue := fake.issues[3]
+	expected := "Login handler\n\n- **Feature**: `004-user-login`\n- **Phase**: Core Implementation\n" +
+		"- **Depends on**: #2 (T002), #3 (T003)\n\n<!-- specify-task: 004-user-login/T004 -->"
+	if issue["title"] != "[004] T004 Login handler" || issue["body"] != expected {
+		t.Fatalf("issue of T004: %v", issue)
+	}
+	if labels := fmt.Sprint(fake.issues[1]["labels"]); labels != "[map[name:specify-task] map[name:phase: Tests First]]" {
+		t.Fatalf("labels of T002: %s", labels)
+	}
+
+	// Exporting again finds the issues through their markers
+	writes := fake.writes
+	if result, err = feature.ExportTasks(github, repo); err != nil {
+		t.Fatalf("ExportTasks: %v", err)
+	}
+	if fake.writes != writes || len(fake.issues) != 4 || result.Issues[1].Action != models.TaskIssueUnchanged {
+		t.Fatalf("second export made %d changes: %+v", fake.writes-writes, result.Issues)
+	}
+
+	// Closed issues check off their tasks
+	fake.issues[2]["state"] = "closed"
+	imported, err := feature.ImportTasks(github, repo)
+	if err != nil {
+		t.Fatalf("ImportTasks: %v", err)
+	}
+	tasks, _ := os.ReadFile("specs/004-user-login/tasks.md")
+	if !slices.Equal(imported.Checked, []string{"T003"}) || !strings.Contains(string(tasks), "- [x] T003 [P] Contract test POST /logout\n") {
+		t.Fatalf("ImportTasks() = %+v, tasks.md:\n%s", imported, tasks)
+	}
+}
+
+func TestTaskIDs(t *testing.T) {
+	known := map[string]bool{"T001": true, "T002": true, "T003": true, "T005": true}
+
+	f := func(text string, expected ...string) {
+		t.Helper()
+
+		if ids := taskIDs(text, known); !slices.Equal(ids, expected) {
+			t.Fatalf("taskIDs(%q) = %v, expected %v", text, ids, expected)
+		}
+	}
+
+	f("no tasks")
+	f("T002", "T002")
+	f("T001-T003", "T001", "T002", "T003")
+	f("T003 – T009", "T003", "T005")
+	f("T001, T005 and T004", "T001", "T005")
+	f("T002-T9999999999", "T002", "T003", "T005")
+	f("T0900-T0999")
+}
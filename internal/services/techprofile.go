@@ -0,0 +1,464 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// Compiled regexes for Technical Context parsing
+var (
+	techFieldRegex    = regexp.MustCompile(`^\s*(?:[-*]\s+)?\*\*([^*]+)\*\*:\s*(.*?)\s*$`)
+	langVersionRegex  = regexp.MustCompile(`^(.*?)\s+v?(\d[\w.+-]*)$`)
+	techPlaceholderRe = regexp.MustCompile(`^\[.*\]$`)
+)
+
+// techListSeparators split multi-valued Technical Context entries
+var techListSeparators = []string{",", ";", " + ", " and ", " / "}
+
+// ParseTechStack extracts the structured tech stack from the Technical Context
+// section of a plan. Placeholders, "N/A" and "NEEDS CLARIFICATION" values are
+// ignored.
+func ParseTechStack(planContent string) models.TechStack {
+	stack := models.TechStack{}
+
+	for _, line := range technicalContextLines(planContent) {
+		match := techFieldRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(match[1]))
+		value := cleanTechValue(match[2])
+		if value == "" {
+			continue
+		}
+
+		switch key {
+		case "language/version", "language", "languages":
+			for _, item := range splitTechList(value) {
+				stack.Languages = append(stack.Languages, parseLanguage(item))
+			}
+		case "primary dependencies", "dependencies", "frameworks":
+			stack.Frameworks = append(stack.Frameworks, splitTechList(value)...)
+		case "storage":
+			stack.Storage = append(stack.Storage, splitTechList(value)...)
+		case "testing":
+			stack.Testing = append(stack.Testing, splitTechList(value)...)
+		case "target platform", "target platforms":
+			stack.TargetPlatforms = append(stack.TargetPlatforms, splitTechList(value)...)
+		case "project type":
+			stack.ProjectType = value
+		case "performance goals":
+			stack.PerformanceGoals = append(stack.PerformanceGoals, splitTechList(value)...)
+		case "constraints":
+			stack.Constraints = append(stack.Constraints, splitTechList(value)...)
+		}
+	}
+
+	return stack
+}
+
+// technicalContextLines returns the lines of the "## Technical Context" section,
+// or the whole plan when the section heading is missing
+func technicalContextLines(planContent string) []string {
+	lines := strings.Split(planContent, "\n")
+
+	start := slices.IndexFunc(lines, func(line string) bool {
+		return strings.HasPrefix(strings.TrimSpace(line), "## Technical Context")
+	})
+	if start == -1 {
+		return lines
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") || strings.HasPrefix(lines[i], "# ") {
+			end = i
+			break
+		}
+	}
+
+	return lines[start+1 : end]
+}
+
+// cleanTechValue drops unresolved and not-applicable values
+func cleanTechValue(value string) string {
+	value = strings.TrimSpace(value)
+	upper := strings.ToUpper(value)
+
+	switch {
+	case value == "", techPlaceholderRe.MatchString(value):
+		return ""
+	case strings.Contains(upper, "NEEDS CLARIFICATION"):
+		return ""
+	case upper == "N/A", upper == "NA", upper == "NONE", strings.HasPrefix(upper, "N/A "):
+		return ""
+	}
+
+	return value
+}
+
+// splitTechList splits a value on list separators outside of parentheses
+func splitTechList(value string) []string {
+	items := []string{value}
+	for _, sep := range techListSeparators {
+		var next []string
+		for _, item := range items {
+			next = append(next, splitOutsideParens(item, sep)...)
+		}
+		items = next
+	}
+
+	result := []string{}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" && !slices.Contains(result, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// splitOutsideParens splits s on sep, ignoring separators inside parentheses
+func splitOutsideParens(s, sep string) []string {
+	var parts []string
+	depth, last := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		}
+		if depth == 0 && strings.HasPrefix(s[i:], sep) {
+			parts = append(parts, s[last:i])
+			last = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, s[last:])
+}
+
+// parseLanguage splits "Python 3.11" into name and version
+func parseLanguage(value string) models.Language {
+	if match := langVersionRegex.FindStringSubmatch(value); match != nil {
+		return models.Language{Name: strings.TrimSpace(match[1]), Version: strings.TrimSpace(match[2])}
+	}
+	return models.Language{Name: value}
+}
+
+// buildTechProfile parses every feature plan under specsDir into a merged profile
+func (f *FeatureService) buildTechProfile(repoRoot, specsDir string) (*models.TechProfile, error) {
+	entries, err := f.filesystem.ListDirectory(specsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list specs directory: %w", err)
+	}
+	slices.Sort(entries)
+
+	features := []models.FeatureTech{}
+	for _, entry := range entries {
+		planFile := filepath.Join(specsDir, entry, "plan.md")
+		if exists, _ := f.filesystem.FileExists(planFile); !exists {
+			continue
+		}
+
+		content, err := f.filesystem.ReadFile(planFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", planFile, err)
+		}
+
+		features = append(features, models.FeatureTech{
+			Feature: entry,
+			Stack:   ParseTechStack(content),
+		})
+	}
+
+	profile := models.NewTechProfile(features)
+	profile.Commands = f.detectProjectCommands(repoRoot, profile.Stack)
+
+	return profile, nil
+}
+
+// writeTechProfile persists the profile as specs/tech-profile.json
+func (f *FeatureService) writeTechProfile(specsDir string, profile *models.TechProfile) error {
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tech profile: %w", err)
+	}
+
+	profilePath := filepath.Join(specsDir, models.TechProfileFile)
+	if err := f.filesystem.WriteFile(profilePath, string(data)+"\n"); err != nil {
+		return fmt.Errorf("failed to write tech profile: %w", err)
+	}
+
+	return nil
+}
+
+// readTechProfile loads a previously persisted profile; found is false when
+// none exists
+func (f *FeatureService) readTechProfile(specsDir string) (profile models.TechProfile, found bool, err error) {
+	profilePath := filepath.Join(specsDir, models.TechProfileFile)
+	if exists, _ := f.filesystem.FileExists(profilePath); !exists {
+		return models.TechProfile{}, false, nil
+	}
+
+	content, err := f.filesystem.ReadFile(profilePath)
+	if err != nil {
+		return models.TechProfile{}, false, fmt.Errorf("failed to read tech profile: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(content), &profile); err != nil {
+		return models.TechProfile{}, false, fmt.Errorf("failed to parse tech profile: %w", err)
+	}
+
+	return profile, true, nil
+}
+
+// buildChangeHistory returns the size most recent features, current first. The
+// order of earlier updates is kept from the previous history; features never
+// seen before are added newest number first.
+func (f *FeatureService) buildChangeHistory(specsDir string, profile *models.TechProfile, previous []models.ChangeEntry, current string, size int) []models.ChangeEntry {
+	if size <= 0 {
+		size = models.DefaultChangeHistory
+	}
+
+	// Technologies each feature introduced on top of the features before it
+	additions := make(map[string]models.TechStack, len(profile.Features))
+	base := models.TechStack{}
+	for _, feature := range profile.Features {
+		additions[feature.Feature] = feature.Stack.Additions(base)
+		base = base.Merge(feature.Stack)
+	}
+
+	order := []string{current}
+	for _, entry := range previous {
+		order = append(order, entry.Feature)
+	}
+	for i := len(profile.Features) - 1; i >= 0; i-- {
+		order = append(order, profile.Features[i].Feature)
+	}
+
+	history := []models.ChangeEntry{}
+	seen := make(map[string]bool)
+	for _, feature := range order {
+		if len(history) == size {
+			break
+		}
+		if seen[feature] {
+			continue
+		}
+		seen[feature] = true
+
+		// Skip features whose directory was removed since the last update
+		if exists, _ := f.filesystem.DirectoryExists(filepath.Join(specsDir, feature)); !exists {
+			continue
+		}
+
+		history = append(history, models.ChangeEntry{
+			Feature: feature,
+			Title:   f.specTitle(filepath.Join(specsDir, feature, "spec.md")),
+			Added:   additionSummary(additions[feature]),
+		})
+	}
+
+	return history
+}
+
+// specTitle returns the feature name from the first heading of a spec
+func (f *FeatureService) specTitle(specFile string) string {
+	content, err := f.filesystem.ReadFile(specFile)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		title, ok := strings.CutPrefix(strings.TrimSpace(line), "# ")
+		if !ok {
+			continue
+		}
+		title = strings.TrimSpace(strings.TrimPrefix(title, "Feature Specification:"))
+		if techPlaceholderRe.MatchString(title) {
+			return ""
+		}
+		return title
+	}
+
+	return ""
+}
+
+// additionSummary lists the languages, frameworks and storage a feature added
+func additionSummary(stack models.TechStack) []string {
+	added := []string{}
+	for _, lang := range stack.Languages {
+		added = append(added, lang.String())
+	}
+	added = append(added, stack.Frameworks...)
+	return append(added, stack.Storage...)
+}
+
+// detectProjectCommands derives build, test and lint commands from the
+// repository's manifests, falling back to per-language defaults
+func (f *FeatureService) detectProjectCommands(repoRoot string, stack models.TechStack) []string {
+	commands := []string{}
+
+	if exists, _ := f.filesystem.FileExists(filepath.Join(repoRoot, "go.mod")); exists {
+		commands = append(commands, "go build ./...", "go test ./...", "go vet ./...")
+		for _, name := range []string{".golangci.yml", ".golangci.yaml"} {
+			if exists, _ := f.filesystem.FileExists(filepath.Join(repoRoot, name)); exists {
+				commands = append(commands, "golangci-lint run")
+				break
+			}
+		}
+	}
+
+	commands = append(commands, f.packageJSONCommands(repoRoot)...)
+
+	if exists, _ := f.filesystem.FileExists(filepath.Join(repoRoot, "Cargo.toml")); exists {
+		commands = append(commands, "cargo build", "cargo test", "cargo clippy")
+	}
+
+	commands = append(commands, f.pyprojectCommands(repoRoot)...)
+
+	if len(commands) > 0 {
+		return commands
+	}
+
+	// Nothing detected in the repository; use defaults for declared languages
+	for _, lang := range stack.Languages {
+		commands = append(commands, f.getCommandsForLanguage(lang.Name))
+	}
+	return commands
+}
+
+// packageJSONCommands returns commands for well-known package.json scripts
+func (f *FeatureService) packageJSONCommands(repoRoot string) []string {
+	content, err := f.filesystem.ReadFile(filepath.Join(repoRoot, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+		return nil
+	}
+
+	runner := "npm"
+	lockfiles := []struct{ file, runner string }{
+		{"pnpm-lock.yaml", "pnpm"},
+		{"yarn.lock", "yarn"},
+		{"bun.lockb", "bun"},
+	}
+	for _, lock := range lockfiles {
+		if exists, _ := f.filesystem.FileExists(filepath.Join(repoRoot, lock.file)); exists {
+			runner = lock.runner
+			break
+		}
+	}
+
+	commands := []string{}
+	for _, script := range []string{"build", "test", "lint", "typecheck", "format"} {
+		if _, ok := pkg.Scripts[script]; ok {
+			commands = append(commands, fmt.Sprintf("%s run %s", runner, script))
+		}
+	}
+	return commands
+}
+
+// pyprojectCommands returns commands for tools configured in pyproject.toml
+func (f *FeatureService) pyprojectCommands(repoRoot string) []string {
+	content, err := f.filesystem.ReadFile(filepath.Join(repoRoot, "pyproject.toml"))
+	if err != nil {
+		return nil
+	}
+
+	prefix := ""
+	if exists, _ := f.filesystem.FileExists(filepath.Join(repoRoot, "uv.lock")); exists {
+		prefix = "uv run "
+	} else if strings.Contains(content, "[tool.poetry") {
+		prefix = "poetry run "
+	}
+
+	tools := []struct{ marker, command string }{
+		{"pytest", "pytest"},
+		{"[tool.ruff", "ruff check ."},
+		{"[tool.mypy", "mypy ."},
+	}
+
+	commands := []string{}
+	for _, tool := range tools {
+		if strings.Contains(content, tool.marker) {
+			commands = append(commands, prefix+tool.command)
+		}
+	}
+	return commands
+}
+
+// renderTechRegion renders the Active Technologies region body
+func renderTechRegion(stack models.TechStack) string {
+	var b strings.Builder
+	b.WriteString("## Active Technologies")
+
+	languages := make([]string, len(stack.Languages))
+	for i, lang := range stack.Languages {
+		languages[i] = lang.String()
+	}
+
+	fields := []struct {
+		label  string
+		values []string
+	}{
+		{"Languages", languages},
+		{"Frameworks", stack.Frameworks},
+		{"Storage", stack.Storage},
+		{"Testing", stack.Testing},
+		{"Target platforms", stack.TargetPlatforms},
+		{"Performance goals", stack.PerformanceGoals},
+		{"Constraints", stack.Constraints},
+	}
+	for _, field := range fields {
+		if len(field.values) > 0 {
+			fmt.Fprintf(&b, "\n- %s: %s", field.label, strings.Join(field.values, ", "))
+		}
+	}
+
+	return b.String()
+}
+
+// renderStyleRegion renders the Code Style region body
+func renderStyleRegion(stack models.TechStack) string {
+	lines := []string{"## Code Style"}
+	for _, lang := range stack.Languages {
+		lines = append(lines, fmt.Sprintf("- %s: Follow standard conventions", lang.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderStructureRegion renders the Project Structure region body
+func renderStructureRegion(stack models.TechStack) string {
+	structure := "src/\ntests/"
+	if strings.Contains(strings.ToLower(stack.ProjectType), "web") {
+		structure = "backend/\nfrontend/\ntests/"
+	}
+	return "## Project Structure\n```\n" + structure + "\n```"
+}
+
+// renderChangesRegion renders the Recent Changes region body
+func renderChangesRegion(history []models.ChangeEntry) string {
+	lines := []string{"## Recent Changes"}
+	for _, entry := range history {
+		lines = append(lines, "- "+entry.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderCommandsRegion renders the Commands region body
+func renderCommandsRegion(commands []string) string {
+	return "## Commands\n```bash\n" + strings.Join(commands, "\n") + "\n```"
+}
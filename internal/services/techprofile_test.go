@@ -0,0 +1,171 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+func TestParseTechStack(t *testing.T) {
+	f := func(name, plan string, expected models.TechStack) {
+		t.Helper()
+
+		result := ParseTechStack(plan)
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("%s: got %+v, expected %+v", name, result, expected)
+		}
+	}
+
+	f("full technical context", `# Plan
+
+## Technical Context
+**Language/Version**: Python 3.11, TypeScript 5.4
+**Primary Dependencies**: FastAPI, React (with hooks) and Vite
+**Storage**: PostgreSQL
+**Testing**: pytest
+**Target Platform**: Linux server
+**Project Type**: web
+**Performance Goals**: 1000 req/s
+**Constraints**: <200ms p95; offline-capable
+**Scale/Scope**: 10k users
+
+## Constitution Check
+**Storage**: ignored outside the section
+`, models.TechStack{
+		Languages:        []models.Language{{Name: "Python", Version: "3.11"}, {Name: "TypeScript", Version: "5.4"}},
+		Frameworks:       []string{"FastAPI", "React (with hooks)", "Vite"},
+		Storage:          []string{"PostgreSQL"},
+		Testing:          []string{"pytest"},
+		TargetPlatforms:  []string{"Linux server"},
+		ProjectType:      "web",
+		PerformanceGoals: []string{"1000 req/s"},
+		Constraints:      []string{"<200ms p95", "offline-capable"},
+	})
+
+	f("unresolved values are skipped", `## Technical Context
+**Language/Version**: [e.g., Python 3.11 or NEEDS CLARIFICATION]
+**Primary Dependencies**: NEEDS CLARIFICATION
+**Storage**: N/A
+**Testing**: go test
+`, models.TechStack{
+		Testing: []string{"go test"},
+	})
+
+	f("missing section heading", "**Language/Version**: Go 1.25\n", models.TechStack{
+		Languages: []models.Language{{Name: "Go", Version: "1.25"}},
+	})
+}
+
+func TestTechStackMerge(t *testing.T) {
+	a := models.TechStack{
+		Languages:  []models.Language{{Name: "Go"}},
+		Frameworks: []string{"cobra"},
+	}
+	b := models.TechStack{
+		Languages:   []models.Language{{Name: "go", Version: "1.25"}, {Name: "Rust"}},
+		Frameworks:  []string{"Cobra", "viper"},
+		ProjectType: "single",
+	}
+
+	expected := models.TechStack{
+		Languages:   []models.Language{{Name: "Go", Version: "1.25"}, {Name: "Rust"}},
+		Frameworks:  []string{"cobra", "viper"},
+		ProjectType: "single",
+	}
+	if result := a.Merge(b); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("got %+v, expected %+v", result, expected)
+	}
+}
+
+func TestTechStackAdditions(t *testing.T) {
+	base := models.TechStack{
+		Languages:  []models.Language{{Name: "Go", Version: "1.25"}},
+		Frameworks: []string{"cobra"},
+	}
+	stack := models.TechStack{
+		Languages:  []models.Language{{Name: "go", Version: "1.25"}, {Name: "Rust", Version: "1.80"}},
+		Frameworks: []string{"Cobra", "go-git"},
+		Storage:    []string{"SQLite"},
+	}
+
+	entry := models.ChangeEntry{
+		Feature: "002-export",
+		Title:   "Bar export",
+		Added:   additionSummary(stack.Additions(base)),
+	}
+	if expected := "002-export: Bar export (added Rust 1.80, go-git, SQLite)"; entry.String() != expected {
+		t.Fatalf("got %q, expected %q", entry.String(), expected)
+	}
+}
+
+func TestBuildChangeHistory(t *testing.T) {
+	specsDir := filepath.Join(t.TempDir(), "specs")
+	for _, feature := range []string{"001-login", "002-search", "003-export", "004-billing"} {
+		writeTestFile(t, filepath.Join(specsDir, feature, "spec.md"), "# Feature Specification: "+feature[4:]+"\n")
+	}
+	feature := NewFeatureService(NewFilesystemService(), NewGitService(), nil)
+
+	go125 := models.TechStack{Languages: []models.Language{{Name: "Go", Version: "1.25"}}}
+	profile := &models.TechProfile{Features: []models.FeatureTech{
+		{Feature: "001-login", Stack: go125},
+		{Feature: "002-search", Stack: models.TechStack{Languages: go125.Languages, Storage: []string{"SQLite"}}},
+		{Feature: "003-export", Stack: go125},
+		{Feature: "004-billing", Stack: go125},
+	}}
+	entries := func(features ...string) []models.ChangeEntry {
+		history := []models.ChangeEntry{}
+		for _, name := range features {
+			history = append(history, models.ChangeEntry{Feature: name})
+		}
+		return history
+	}
+
+	f := func(name string, previous []models.ChangeEntry, current string, size int, expected ...string) {
+		t.Helper()
+		history := feature.buildChangeHistory(specsDir, profile, previous, current, size)
+		got := []string{}
+		for _, entry := range history {
+			got = append(got, entry.Feature)
+		}
+		if !slices.Equal(got, expected) {
+			t.Fatalf("%s: got %v, expected %v", name, got, expected)
+		}
+	}
+
+	// The current feature, then the previous history, then the newest others
+	f("order", entries("001-login"), "003-export", 4, "003-export", "001-login", "004-billing", "002-search")
+	f("no previous history", nil, "002-search", 3, "002-search", "004-billing", "003-export")
+	f("duplicates", entries("002-search", "001-login", "002-search"), "002-search", 4, "002-search", "001-login", "004-billing", "003-export")
+	f("removed feature", entries("009-gone", "001-login"), "003-export", 3, "003-export", "001-login", "004-billing")
+	f("cap", entries("001-login"), "003-export", 1, "003-export")
+	f("default size", nil, "001-login", 0, "001-login", "004-billing", "003-export")
+
+	// Entries carry the spec title and what the feature introduced
+	history := feature.buildChangeHistory(specsDir, profile, nil, "002-search", 1)
+	if history[0].Title != "search" || !slices.Equal(history[0].Added, []string{"SQLite"}) {
+		t.Fatalf("got %+v", history[0])
+	}
+}
+
+func TestUpdateAgentFileHistoryWithoutStack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CLAUDE.md")
+	feature := NewFeatureService(NewFilesystemService(), NewGitService(), nil)
+	writeTestFile(t, path, feature.getBasicAgentTemplate())
+
+	profile := &models.TechProfile{History: []models.ChangeEntry{{Feature: "001-login", Title: "Login"}}}
+	if _, err := feature.updateAgentFile(path, profile); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "- 001-login") {
+		t.Fatalf("history not rendered:\n%s", content)
+	}
+}
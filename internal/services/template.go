@@ -3,6 +3,7 @@ package services
 import (
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -19,6 +20,10 @@ type TemplateService struct {
 	github     *GitHubService
 	filesystem *FilesystemService
 	processor  *template.Processor
+	cacheRoot  string // overrides ~/.spec-kit/templates when set
+
+	trustedKeys   []string // minisign public keys trusted in addition to PinnedTemplateKeys
+	allowUnsigned bool     // accept bundles without a trusted signature
 }
 
 // NewTemplateService creates a new template service instance
@@ -30,6 +35,46 @@ func NewTemplateService(github *GitHubService, filesystem *FilesystemService) *T
 	}
 }
 
+// NewTemplateServiceWithCacheRoot creates a template service using a custom
+// cache directory; a leading "~/" is expanded to the user home directory
+func NewTemplateServiceWithCacheRoot(github *GitHubService, filesystem *FilesystemService, cacheRoot string) *TemplateService {
+	service := NewTemplateService(github, filesystem)
+	service.cacheRoot = cacheRoot
+	return service
+}
+
+// SetTrustedKeys sets the minisign public keys trusted to sign template
+// bundles in addition to PinnedTemplateKeys
+func (t *TemplateService) SetTrustedKeys(keys []string) {
+	t.trustedKeys = keys
+}
+
+// AllowUnsigned accepts template bundles without a signature by a trusted key.
+// A bundle whose signature does not match is refused regardless.
+func (t *TemplateService) AllowUnsigned(allow bool) {
+	t.allowUnsigned = allow
+}
+
+// VerifyBundle verifies the signature of a template bundle downloaded from
+// asset to path before it is extracted, and reports whether it was verified.
+// A bundle that is unsigned or signed by a key that is neither pinned nor
+// configured is refused unless AllowUnsigned was set, in which case it is
+// accepted unverified. A signature that does not match is refused regardless.
+func (t *TemplateService) VerifyBundle(release *GitHubRelease, asset *GitHubAsset, path string) (TemplateSignature, bool, error) {
+	keys := append(PinnedTemplateKeys(), t.trustedKeys...)
+	signature, err := t.github.VerifyReleaseSignature(release, asset, path, keys)
+	if errors.Is(err, models.ErrTemplateUnsigned) {
+		if t.allowUnsigned {
+			return TemplateSignature{}, false, nil
+		}
+		return TemplateSignature{}, false, fmt.Errorf("%w; add its public key to templates.trusted_keys or use --allow-unsigned to accept it", err)
+	}
+	if err != nil {
+		return TemplateSignature{}, false, err
+	}
+	return *signature, true, nil
+}
+
 // processTemplate processes a template string with the given data
 func (t *TemplateService) processTemplate(content string, data template.Data) (string, error) {
 	return t.processor.Process(content, data)
@@ -132,7 +177,14 @@ func (t *TemplateService) DownloadAndExtract(aiAssistant, targetPath string, isH
 	cacheRoot, err := t.ResolveRoot()
 	if err == nil {
 		if isEmpty, _ := t.isCacheEmpty(cacheRoot); !isEmpty {
-			if err := t.extractFromCache(aiAssistant, targetPath, isHere); err == nil {
+			err := t.extractFromCache(aiAssistant, targetPath, isHere)
+			if err != nil {
+				// Repair damaged files before downloading the whole bundle again
+				if repair, repairErr := t.RepairCache(); repairErr == nil && repair.Changed() {
+					err = t.extractFromCache(aiAssistant, targetPath, isHere)
+				}
+			}
+			if err == nil {
 				// Cache extraction successful
 				template := &models.Template{
 					Version:  t.GetSpecKitVersion(),
@@ -143,7 +195,7 @@ func (t *TemplateService) DownloadAndExtract(aiAssistant, targetPath string, isH
 		}
 	}
 
-	// Cache is empty or invalid - automatically sync templates
+	// Cache is empty or cannot be repaired - automatically sync templates
 	if err := t.autoSyncTemplates(); err != nil {
 		return nil, fmt.Errorf("%w: failed to sync templates automatically. Please run 'specify templates sync' manually: %v", models.ErrTemplateNotFound, err)
 	}
@@ -160,6 +212,17 @@ func (t *TemplateService) DownloadAndExtract(aiAssistant, targetPath string, isH
 	return template, nil
 }
 
+// HasCachedTemplates reports whether the cache holds templates, so projects
+// can be initialized from it without the network
+func (t *TemplateService) HasCachedTemplates() bool {
+	cacheRoot, err := t.ResolveRoot()
+	if err != nil {
+		return false
+	}
+	isEmpty, err := t.isCacheEmpty(cacheRoot)
+	return err == nil && !isEmpty
+}
+
 // isCacheEmpty checks if the cache directory is empty or doesn't exist
 func (t *TemplateService) isCacheEmpty(cacheRoot string) (bool, error) {
 	// Check if cache directory exists
@@ -217,7 +280,7 @@ func (t *TemplateService) autoSyncTemplates() error {
 	// Look for cache template asset
 	var cacheAsset *GitHubAsset
 	for _, asset := range release.Assets {
-		if asset.Name == "spec-kit-cache-template.zip" {
+		if asset.Name == cacheTemplateAssetName {
 			cacheAsset = &asset
 			break
 		}
@@ -227,18 +290,14 @@ func (t *TemplateService) autoSyncTemplates() error {
 		return fmt.Errorf("cache template asset not found in release %s", release.TagName)
 	}
 
-	// Download the asset
+	// Download and verify the asset
 	zipPath := filepath.Join(tempDir, cacheAsset.Name)
-	file, err := os.Create(zipPath)
-	if err != nil {
-		return fmt.Errorf("failed to create download file: %w", err)
-	}
-	defer file.Close()
-
-	if err := t.github.DownloadAsset(cacheAsset, file); err != nil {
+	if _, err := t.github.DownloadReleaseAsset(release, cacheAsset, zipPath, nil); err != nil {
 		return fmt.Errorf("failed to download template asset: %w", err)
 	}
-	file.Close() // Close before extraction
+	if _, _, err := t.VerifyBundle(release, cacheAsset, zipPath); err != nil {
+		return err
+	}
 
 	// Extract ZIP to temporary directory
 	extractDir := filepath.Join(tempDir, "extracted")
@@ -246,20 +305,9 @@ func (t *TemplateService) autoSyncTemplates() error {
 		return fmt.Errorf("failed to extract template ZIP: %w", err)
 	}
 
-	// Get cache root and copy extracted content
-	cacheRoot, err := t.ResolveRoot()
-	if err != nil {
-		return fmt.Errorf("failed to resolve cache root: %w", err)
-	}
-
-	// Ensure cache directory exists
-	if err := t.filesystem.CreateDirectory(cacheRoot); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
-	}
-
-	// Copy extracted templates to cache
-	if err := t.filesystem.MergeDirectories(extractDir, cacheRoot); err != nil {
-		return fmt.Errorf("failed to copy templates to cache: %w", err)
+	// Install the extracted templates, archiving a different cached version
+	if err := t.InstallCache(extractDir); err != nil {
+		return err
 	}
 
 	return nil
@@ -267,31 +315,7 @@ func (t *TemplateService) autoSyncTemplates() error {
 
 // downloadTemplate downloads the template ZIP file
 func (t *TemplateService) downloadTemplate(template *models.Template, zipPath string) error {
-	// Create ZIP file
-	file, err := os.Create(zipPath)
-	if err != nil {
-		return fmt.Errorf("%w: failed to create download file for version %s: %v", models.ErrTemplateDownloadFailed, template.Version, err)
-	}
-	defer file.Close()
-
-	// Get release information again to get asset details
-	release, err := t.github.GetLatestRelease()
-	if err != nil {
-		return err
-	}
-
-	// Find the asset
-	asset, err := t.github.FindTemplateAsset(release)
-	if err != nil {
-		return err
-	}
-
-	// Download the asset
-	if err := t.github.DownloadAsset(asset, file); err != nil {
-		return err
-	}
-
-	return nil
+	return t.downloadTemplateWithProgress(template, zipPath, nil)
 }
 
 // extractTemplate extracts the template to the target path using unified template copying logic
@@ -529,13 +553,6 @@ func (t *TemplateService) DownloadTemplateWithProgress(aiAssistant, targetPath s
 
 // downloadTemplateWithProgress downloads the template with progress tracking
 func (t *TemplateService) downloadTemplateWithProgress(template *models.Template, zipPath string, progressCallback func(downloaded, total int64)) error {
-	// Create ZIP file
-	file, err := os.Create(zipPath)
-	if err != nil {
-		return fmt.Errorf("%w: failed to create download file for version %s: %v", models.ErrTemplateDownloadFailed, template.Version, err)
-	}
-	defer file.Close()
-
 	// Get release information again to get asset details
 	release, err := t.github.GetLatestRelease()
 	if err != nil {
@@ -548,15 +565,11 @@ func (t *TemplateService) downloadTemplateWithProgress(template *models.Template
 		return err
 	}
 
-	// Create a progress writer
-	progressWriter := &progressWriter{
-		writer:   file,
-		total:    asset.Size,
-		callback: progressCallback,
+	// Download the asset with progress tracking, resuming and verifying it
+	if _, err := t.github.DownloadReleaseAsset(release, asset, zipPath, progressCallback); err != nil {
+		return fmt.Errorf("failed to download template version %s: %w", template.Version, err)
 	}
-
-	// Download the asset with progress tracking
-	if err := t.github.DownloadAsset(asset, progressWriter); err != nil {
+	if _, _, err := t.VerifyBundle(release, asset, zipPath); err != nil {
 		return err
 	}
 
@@ -662,7 +675,17 @@ func (t *TemplateService) ResolveRoot() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to get user home directory: %w", err)
 	}
-	return filepath.Join(homeDir, ".spec-kit", "templates"), nil
+
+	switch {
+	case t.cacheRoot == "":
+		return filepath.Join(homeDir, ".spec-kit", "templates"), nil
+	case t.cacheRoot == "~":
+		return homeDir, nil
+	case strings.HasPrefix(t.cacheRoot, "~/"):
+		return filepath.Join(homeDir, t.cacheRoot[2:]), nil
+	default:
+		return filepath.Abs(t.cacheRoot)
+	}
 }
 
 // ReadManifest reads and parses the cache manifest file from default cache location
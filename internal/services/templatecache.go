@@ -0,0 +1,554 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// cacheManifestName is the manifest file at the root of a cached version
+const cacheManifestName = ".manifest.json"
+
+// cacheTemplateAssetName is the release asset holding the cache template bundle
+const cacheTemplateAssetName = "spec-kit-cache-template.zip"
+
+// unsafeVersionChars are replaced in the directory names of archived versions
+var unsafeVersionChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// VersionsRoot returns the directory versions replaced by a sync are archived
+// in, next to the cache root
+func (t *TemplateService) VersionsRoot() (string, error) {
+	cacheRoot, err := t.ResolveRoot()
+	if err != nil {
+		return "", err
+	}
+	return cacheRoot + ".versions", nil
+}
+
+// ListCache returns the active cached version, if any, followed by archived
+// versions from the most recently synced
+func (t *TemplateService) ListCache() ([]models.CacheVersion, error) {
+	cacheRoot, err := t.ResolveRoot()
+	if err != nil {
+		return nil, err
+	}
+	versionsRoot, err := t.VersionsRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []models.CacheVersion{}
+	if version, ok := t.cacheVersion(cacheRoot); ok {
+		version.Active = true
+		versions = append(versions, version)
+	}
+
+	entries, err := os.ReadDir(versionsRoot)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", versionsRoot, err)
+	}
+	var archived []models.CacheVersion
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if version, ok := t.cacheVersion(filepath.Join(versionsRoot, entry.Name())); ok {
+			archived = append(archived, version)
+		}
+	}
+	slices.SortFunc(archived, func(a, b models.CacheVersion) int { return b.LastSync.Compare(a.LastSync) })
+
+	return append(versions, archived...), nil
+}
+
+// cacheVersion describes the cached version in dir, if it has a manifest
+func (t *TemplateService) cacheVersion(dir string) (models.CacheVersion, bool) {
+	manifest, err := t.readManifestFromPath(dir)
+	if err != nil {
+		return models.CacheVersion{}, false
+	}
+	size, _ := t.filesystem.GetDirectorySize(dir)
+	return models.CacheVersion{
+		Version:  manifest.SpecKitVersion,
+		Path:     dir,
+		Files:    manifest.GetTemplateCount(),
+		Size:     size,
+		LastSync: manifest.LastSync,
+	}, true
+}
+
+// VerifyCache checks every file of the active cache against the manifest and
+// reports each one that is missing or modified, and files the manifest does
+// not list
+func (t *TemplateService) VerifyCache() (*models.CacheVerification, error) {
+	cacheRoot, err := t.ResolveRoot()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := t.readManifestFromPath(cacheRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w: no template cache in %s (run 'specify templates sync'): %v",
+			models.ErrTemplateCacheFailed, cacheRoot, err)
+	}
+
+	verification := &models.CacheVerification{
+		Root:     cacheRoot,
+		Version:  manifest.SpecKitVersion,
+		Checked:  manifest.GetTemplateCount(),
+		Problems: []models.CacheFileProblem{},
+		Extra:    []string{},
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(manifest.Templates)) {
+		expected := manifest.Templates[path]
+		actual, err := t.CalculateFileHash(filepath.Join(cacheRoot, filepath.FromSlash(path)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			verification.Problems = append(verification.Problems,
+				models.CacheFileProblem{Path: path, Problem: models.CacheFileMissing, Expected: expected})
+		case err != nil:
+			return nil, err
+		case actual != expected:
+			verification.Problems = append(verification.Problems,
+				models.CacheFileProblem{Path: path, Problem: models.CacheFileModified, Expected: expected, Actual: actual})
+		}
+	}
+
+	err = filepath.WalkDir(cacheRoot, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(cacheRoot, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if _, listed := manifest.Templates[relPath]; !listed && relPath != cacheManifestName {
+			verification.Extra = append(verification.Extra, relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", cacheRoot, err)
+	}
+
+	return verification, nil
+}
+
+// InstallCache replaces the cache with templates extracted from a bundle. The
+// new cache is built next to the old one and swapped in, so an interrupted
+// sync leaves the old cache intact and files the bundle no longer ships do not
+// linger. When the cache holds a different version, it is archived first.
+func (t *TemplateService) InstallCache(sourceDir string) error {
+	cacheRoot, err := t.ResolveRoot()
+	if err != nil {
+		return err
+	}
+	if err := t.checkCacheRoot(cacheRoot); err != nil {
+		return err
+	}
+
+	buildDir, err := newCacheBuild(cacheRoot)
+	if err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	defer os.RemoveAll(buildDir)
+	if err := t.filesystem.MergeDirectories(sourceDir, buildDir); err != nil {
+		return fmt.Errorf("failed to copy templates to cache: %w", err)
+	}
+
+	if bundle, err := t.readManifestFromPath(sourceDir); err == nil {
+		if err := t.archiveCache(cacheRoot, bundle.SpecKitVersion); err != nil {
+			return fmt.Errorf("%w: failed to archive the cached version: %v", models.ErrTemplateCacheFailed, err)
+		}
+	}
+	return swapCache(buildDir, cacheRoot)
+}
+
+// RepairCache restores the files of the active cache that do not match the
+// manifest and removes files it does not list. A damaged file is copied from
+// an archived version with the same content where possible, otherwise it is
+// read from the release bundle of the cached version with Range requests
+// rather than downloading the whole bundle. Either way it must match the hash
+// in the manifest, which came from a verified bundle. The repaired cache is
+// built next to the old one and swapped in.
+func (t *TemplateService) RepairCache() (*models.CacheRepair, error) {
+	verification, err := t.VerifyCache()
+	if err != nil {
+		return nil, err
+	}
+	repair := &models.CacheRepair{
+		Root:     verification.Root,
+		Version:  verification.Version,
+		Restored: []string{},
+		Fetched:  []string{},
+		Removed:  verification.Extra,
+	}
+	if verification.OK() && len(verification.Extra) == 0 {
+		return repair, nil
+	}
+
+	cacheRoot := verification.Root
+	manifest, err := t.readManifestFromPath(cacheRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrTemplateCacheFailed, err)
+	}
+	buildDir, err := newCacheBuild(cacheRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrTemplateCacheFailed, err)
+	}
+	defer os.RemoveAll(buildDir)
+
+	damaged := make(map[string]string)
+	for _, problem := range verification.Problems {
+		damaged[problem.Path] = problem.Expected
+	}
+
+	// Intact files are copied and checked again, in case they changed since
+	// verification
+	for path, expected := range manifest.Templates {
+		if _, ok := damaged[path]; ok {
+			continue
+		}
+		if err := copyCacheFile(filepath.Join(cacheRoot, filepath.FromSlash(path)), filepath.Join(buildDir, filepath.FromSlash(path)), expected); err != nil {
+			damaged[path] = expected
+		}
+	}
+	if err := t.filesystem.CopyFile(filepath.Join(cacheRoot, cacheManifestName), filepath.Join(buildDir, cacheManifestName)); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrTemplateCacheFailed, err)
+	}
+
+	versions, err := t.ListCache()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range slices.Sorted(maps.Keys(damaged)) {
+		for _, version := range versions {
+			if version.Active {
+				continue
+			}
+			if copyCacheFile(filepath.Join(version.Path, filepath.FromSlash(path)), filepath.Join(buildDir, filepath.FromSlash(path)), damaged[path]) == nil {
+				repair.Restored = append(repair.Restored, path)
+				delete(damaged, path)
+				break
+			}
+		}
+	}
+
+	if len(damaged) > 0 {
+		if err := t.fetchCacheFiles(manifest.SpecKitVersion, damaged, buildDir); err != nil {
+			return nil, fmt.Errorf("%w: cannot repair %d files from the %s bundle: %v",
+				models.ErrTemplateCacheFailed, len(damaged), manifest.SpecKitVersion, err)
+		}
+		repair.Fetched = slices.Sorted(maps.Keys(damaged))
+	}
+
+	if err := swapCache(buildDir, cacheRoot); err != nil {
+		return nil, err
+	}
+	return repair, nil
+}
+
+// fetchCacheFiles reads files, mapping paths to their expected hashes, from
+// the cache template bundle of a release into dir
+func (t *TemplateService) fetchCacheFiles(version string, files map[string]string, dir string) error {
+	if t.github == nil {
+		return fmt.Errorf("no GitHub service configured")
+	}
+	release, err := t.github.GetReleaseByTag(version)
+	if err != nil {
+		return err
+	}
+	var asset *GitHubAsset
+	for i := range release.Assets {
+		if release.Assets[i].Name == cacheTemplateAssetName {
+			asset = &release.Assets[i]
+			break
+		}
+	}
+	if asset == nil {
+		return fmt.Errorf("%s not found in release %s", cacheTemplateAssetName, release.TagName)
+	}
+
+	bundle, err := t.github.OpenReleaseZIP(asset)
+	if err != nil {
+		return err
+	}
+	remaining := maps.Clone(files)
+	for _, entry := range bundle.File {
+		path, ok := bundlePath(entry.Name, remaining)
+		if !ok {
+			continue
+		}
+		content, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		err = writeCacheFile(content, filepath.Join(dir, filepath.FromSlash(path)), remaining[path], entry.Mode())
+		content.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		delete(remaining, path)
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("the bundle lacks %s", strings.Join(slices.Sorted(maps.Keys(remaining)), ", "))
+	}
+	return nil
+}
+
+// bundlePath returns the cache path of a bundle entry among wanted paths. The
+// entry may sit under a single root directory, which extraction flattens.
+func bundlePath(name string, wanted map[string]string) (string, bool) {
+	name = strings.TrimPrefix(name, "./")
+	if _, ok := wanted[name]; ok {
+		return name, true
+	}
+	if _, rest, found := strings.Cut(name, "/"); found {
+		if _, ok := wanted[rest]; ok {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+// copyCacheFile copies a cached file and checks the copy has the expected hash
+func copyCacheFile(source, target, expected string) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(file, target, expected, info.Mode())
+}
+
+// writeCacheFile writes content to target and checks it has the SHA256 the
+// manifest lists; a file that does not match is removed
+func writeCacheFile(content io.Reader, target, expected string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if mode.Perm() == 0 {
+		mode = 0o644
+	}
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hasher), content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+		return err
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != expected {
+		os.Remove(target)
+		return fmt.Errorf("%w: SHA256 %s, the manifest lists %s", models.ErrChecksumMismatch, actual, expected)
+	}
+	return nil
+}
+
+// newCacheBuild creates a directory next to cacheRoot to build a new cache in,
+// on the same filesystem so it can be renamed into place
+func newCacheBuild(cacheRoot string) (string, error) {
+	parent := filepath.Dir(cacheRoot)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp(parent, cacheBuildPrefix(cacheRoot))
+	if err != nil {
+		return "", err
+	}
+	return dir, os.Chmod(dir, 0o755)
+}
+
+// cacheBuildPrefix is the name prefix of directories caches are built in
+func cacheBuildPrefix(cacheRoot string) string {
+	return "." + filepath.Base(cacheRoot) + "-build-"
+}
+
+// swapCache replaces cacheRoot with buildDir. The old cache is renamed aside
+// and put back if the new one cannot be renamed into place, so the cache is
+// never left half written.
+func swapCache(buildDir, cacheRoot string) error {
+	old := buildDir + "-old"
+	movedAside := true
+	if err := os.Rename(cacheRoot, old); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: failed to move the old cache aside: %v", models.ErrTemplateCacheFailed, err)
+		}
+		movedAside = false
+	}
+	if err := os.Rename(buildDir, cacheRoot); err != nil {
+		if movedAside {
+			if restoreErr := os.Rename(old, cacheRoot); restoreErr != nil {
+				return fmt.Errorf("%w: failed to move the new cache into place: %v; failed to restore the old cache: %v (new cache left at %s, old cache at %s)",
+					models.ErrTemplateCacheFailed, err, restoreErr, buildDir, old)
+			}
+		}
+		return fmt.Errorf("%w: failed to move the new cache into place: %v", models.ErrTemplateCacheFailed, err)
+	}
+	os.RemoveAll(old) // a leftover is removed by prune
+	return nil
+}
+
+// archiveCache moves the cached version to the versions directory unless it
+// is newVersion
+func (t *TemplateService) archiveCache(cacheRoot, newVersion string) error {
+	manifest, err := t.readManifestFromPath(cacheRoot)
+	if err != nil || manifest.SpecKitVersion == newVersion {
+		return nil
+	}
+
+	versionsRoot, err := t.VersionsRoot()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(versionsRoot, 0o755); err != nil {
+		return err
+	}
+
+	name := unsafeVersionChars.ReplaceAllString(manifest.SpecKitVersion, "_")
+	if name == "" || strings.Trim(name, ".") == "" {
+		name = "unknown"
+	}
+	target := filepath.Join(versionsRoot, name)
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+	return os.Rename(cacheRoot, target)
+}
+
+// PruneCache removes archived versions except the keep most recently synced,
+// interrupted downloads and cache builds
+func (t *TemplateService) PruneCache(keep int) (*models.CacheRemoval, error) {
+	cacheRoot, err := t.ResolveRoot()
+	if err != nil {
+		return nil, err
+	}
+	versions, err := t.ListCache()
+	if err != nil {
+		return nil, err
+	}
+
+	removal := &models.CacheRemoval{Removed: []string{}}
+	kept := 0
+	for _, version := range versions {
+		if version.Active {
+			continue
+		}
+		if kept < keep {
+			kept++
+			continue
+		}
+		if err := os.RemoveAll(version.Path); err != nil {
+			return removal, fmt.Errorf("failed to remove %s: %w", version.Path, err)
+		}
+		removal.Removed = append(removal.Removed, version.Path)
+		removal.Freed += version.Size
+	}
+
+	return removal, t.removeLeftovers(cacheRoot, removal)
+}
+
+// ClearCache removes the active cache, archived versions and interrupted
+// downloads
+func (t *TemplateService) ClearCache() (*models.CacheRemoval, error) {
+	cacheRoot, err := t.ResolveRoot()
+	if err != nil {
+		return nil, err
+	}
+	versionsRoot, err := t.VersionsRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.checkCacheRoot(cacheRoot); err != nil {
+		return nil, err
+	}
+
+	removal := &models.CacheRemoval{Removed: []string{}}
+	for _, dir := range []string{cacheRoot, versionsRoot} {
+		exists, err := t.filesystem.DirectoryExists(dir)
+		if err != nil || !exists {
+			continue
+		}
+		size, _ := t.filesystem.GetDirectorySize(dir)
+		if err := os.RemoveAll(dir); err != nil {
+			return removal, fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+		removal.Removed = append(removal.Removed, dir)
+		removal.Freed += size
+	}
+
+	return removal, t.removeLeftovers(cacheRoot, removal)
+}
+
+// checkCacheRoot refuses to replace or remove a cache root that contains the
+// home directory or does not look like a template cache, in case cache_dir
+// points somewhere else
+func (t *TemplateService) checkCacheRoot(cacheRoot string) error {
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(cacheRoot, homeDir); err == nil && !strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("%w: refusing to replace %s, which contains the home directory", models.ErrTemplateCacheFailed, cacheRoot)
+		}
+	}
+	manifestExists, err := t.filesystem.FileExists(filepath.Join(cacheRoot, cacheManifestName))
+	if err != nil {
+		return err
+	}
+	if !manifestExists {
+		if empty, err := t.filesystem.IsDirectoryEmpty(cacheRoot); err == nil && !empty {
+			return fmt.Errorf("%w: %s has no %s and may not be a template cache; remove it manually",
+				models.ErrTemplateCacheFailed, cacheRoot, cacheManifestName)
+		}
+	}
+	return nil
+}
+
+// removeLeftovers removes interrupted asset downloads kept for resuming and
+// cache builds left by an interrupted sync or repair
+func (t *TemplateService) removeLeftovers(cacheRoot string, removal *models.CacheRemoval) error {
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(cacheRoot), cacheBuildPrefix(cacheRoot)+"*"))
+	if err != nil {
+		return err
+	}
+	if t.github != nil && t.github.downloadDir != "" {
+		partials, err := filepath.Glob(filepath.Join(t.github.downloadDir, "*.part"))
+		if err != nil {
+			return err
+		}
+		leftovers = append(leftovers, partials...)
+	}
+
+	for _, leftover := range leftovers {
+		size, err := t.filesystem.GetDirectorySize(leftover)
+		if err != nil {
+			continue
+		}
+		if err := os.RemoveAll(leftover); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", leftover, err)
+		}
+		removal.Removed = append(removal.Removed, leftover)
+		removal.Freed += size
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// writeTestBundle writes extracted templates with a manifest of their hashes
+func writeTestBundle(t *testing.T, version string, synced time.Time, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := models.NewCacheManifest(version)
+	for path, content := range files {
+		writeTestFile(t, filepath.Join(dir, path), content)
+		hash, err := NewTemplateService(nil, NewFilesystemService()).CalculateFileHash(filepath.Join(dir, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifest.Templates[path] = hash
+	}
+	manifest.LastSync = synced
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, cacheManifestName), string(data))
+	return dir
+}
+
+func TestTemplateCache(t *testing.T) {
+	cacheRoot := filepath.Join(t.TempDir(), "templates")
+	template := NewTemplateServiceWithCacheRoot(&GitHubService{downloadDir: t.TempDir()}, NewFilesystemService(), cacheRoot)
+
+	install := func(version string, synced time.Time, files map[string]string) {
+		t.Helper()
+		if err := template.InstallCache(writeTestBundle(t, version, synced, files)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	versions := func() []string {
+		t.Helper()
+		cached, err := template.ListCache()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, version := range cached {
+			names = append(names, version.Version)
+		}
+		return names
+	}
+
+	// Each new version archives the previous one instead of merging into it
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	install("v1.0.0", start, map[string]string{"templates/spec.template.md": "v1", "templates/old.md": "old"})
+	install("v1.1.0", start.Add(time.Hour), map[string]string{"templates/spec.template.md": "v1.1"})
+	install("v1.2.0", start.Add(2*time.Hour), map[string]string{"templates/spec.template.md": "v1.2"})
+	if got := versions(); len(got) != 3 || got[0] != "v1.2.0" || got[1] != "v1.1.0" || got[2] != "v1.0.0" {
+		t.Fatalf("got versions %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(cacheRoot, "templates", "old.md")); !os.IsNotExist(err) {
+		t.Errorf("file of an old version is still cached: %v", err)
+	}
+
+	// Verification reports each problem
+	writeTestFile(t, filepath.Join(cacheRoot, "templates/spec.template.md"), "edited")
+	writeTestFile(t, filepath.Join(cacheRoot, "notes.md"), "extra")
+	verification, err := template.VerifyCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.OK() || len(verification.Problems) != 1 || verification.Problems[0].Problem != models.CacheFileModified ||
+		len(verification.Extra) != 1 || verification.Extra[0] != "notes.md" {
+		t.Errorf("got %+v", verification)
+	}
+	os.Remove(filepath.Join(cacheRoot, "templates", "spec.template.md"))
+	if verification, err = template.VerifyCache(); err != nil || verification.Problems[0].Problem != models.CacheFileMissing {
+		t.Errorf("got %+v, %v", verification, err)
+	}
+
+	// Prune keeps the newest archived versions and removes partial downloads
+	writeTestFile(t, filepath.Join(template.github.downloadDir, "7-spec-kit-cache-template.zip.part"), "partial")
+	removal, err := template.PruneCache(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removal.Removed) != 2 || removal.Freed == 0 {
+		t.Errorf("got %+v", removal)
+	}
+	if got := versions(); len(got) != 2 || got[1] != "v1.1.0" {
+		t.Errorf("got versions %v", got)
+	}
+
+	if _, err := template.ClearCache(); err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(); len(got) != 0 {
+		t.Errorf("got versions %v after clear", got)
+	}
+
+	// A directory that is not a template cache is left alone
+	writeTestFile(t, filepath.Join(cacheRoot, "important.txt"), "keep")
+	if _, err := template.ClearCache(); err == nil {
+		t.Error("cleared a directory without a manifest")
+	}
+}
+
+func TestRepairCache(t *testing.T) {
+	// The release bundle, under a single root directory like a GitHub archive
+	var bundle bytes.Buffer
+	archive := zip.NewWriter(&bundle)
+	for path, content := range map[string]string{"bundle/templates/plan.md": "plan v2", "bundle/content/AGENTS.md": "agents"} {
+		writer, err := archive.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var fullDownloads int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/widgets/releases/tags/v2.0.0":
+			json.NewEncoder(w).Encode(GitHubRelease{TagName: "v2.0.0", Assets: []GitHubAsset{{
+				Name: cacheTemplateAssetName, Size: int64(bundle.Len()), BrowserDownloadURL: server.URL + "/bundle.zip",
+			}}})
+		case "/bundle.zip":
+			if r.Header.Get("Range") == "" {
+				fullDownloads++
+			}
+			http.ServeContent(w, r, "bundle.zip", time.Time{}, bytes.NewReader(bundle.Bytes()))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	github := NewGitHubServiceWithClient(server.Client(), "acme", "widgets")
+	github.baseURL = server.URL
+	cacheRoot := filepath.Join(t.TempDir(), "templates")
+	template := NewTemplateServiceWithCacheRoot(github, NewFilesystemService(), cacheRoot)
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	install := func(version, plan string) {
+		t.Helper()
+		files := map[string]string{"templates/spec.md": "spec", "templates/plan.md": plan, "content/AGENTS.md": "agents"}
+		if err := template.InstallCache(writeTestBundle(t, version, start, files)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	install("v1.0.0", "plan v1")
+	install("v2.0.0", "plan v2")
+
+	// spec.md and AGENTS.md are restored from v1.0.0; plan.md differs there
+	// and is read from the bundle
+	writeTestFile(t, filepath.Join(cacheRoot, "templates/spec.md"), "edited")
+	writeTestFile(t, filepath.Join(cacheRoot, "templates/plan.md"), "edited")
+	os.Remove(filepath.Join(cacheRoot, "content/AGENTS.md"))
+	writeTestFile(t, filepath.Join(cacheRoot, "templates/stale.md"), "stale")
+
+	repair, err := template.RepairCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repair.Restored) != 2 || repair.Restored[1] != "templates/spec.md" ||
+		len(repair.Fetched) != 1 || repair.Fetched[0] != "templates/plan.md" ||
+		len(repair.Removed) != 1 || repair.Removed[0] != "templates/stale.md" {
+		t.Errorf("got %+v", repair)
+	}
+	if fullDownloads != 0 {
+		t.Errorf("downloaded the whole bundle %d times", fullDownloads)
+	}
+	if verification, err := template.VerifyCache(); err != nil || !verification.OK() || len(verification.Extra) != 0 {
+		t.Errorf("cache not repaired: %+v, %v", verification, err)
+	}
+
+	// A file the bundle cannot supply leaves the cache untouched
+	writeTestFile(t, filepath.Join(cacheRoot, "templates/spec.md"), "edited")
+	if err := os.RemoveAll(cacheRoot + ".versions"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := template.RepairCache(); err == nil {
+		t.Error("repaired a file missing from the bundle")
+	}
+	if content, _ := os.ReadFile(filepath.Join(cacheRoot, "templates/spec.md")); string(content) != "edited" {
+		t.Errorf("failed repair changed the cache: %q", content)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(cacheRoot), ".templates-build-*")); len(leftovers) != 0 {
+		t.Errorf("build directories left behind: %v", leftovers)
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/euforicio/spec-kit/internal/models"
+)
+
+// FeatureWorktreeRemoveOptions contains options for removing a feature worktree
+type FeatureWorktreeRemoveOptions struct {
+	Force        bool // remove even with uncommitted changes
+	DeleteBranch bool // delete the feature branch after removing the worktree
+}
+
+// worktreePath returns where the worktree of a feature branch is created
+func (f *FeatureService) worktreePath(repoRoot, branchName string) (string, error) {
+	dir := f.projectPath(repoRoot, f.config.GetWorktreeDir(filepath.Base(repoRoot)))
+	path := filepath.Join(dir, f.scheme.DirName(branchName))
+
+	if exists, _ := f.filesystem.DirectoryExists(path); exists {
+		return "", fmt.Errorf("worktree directory already exists: %s", path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// ListWorktrees returns the worktrees checked out on feature branches
+func (f *FeatureService) ListWorktrees() ([]models.FeatureWorktree, error) {
+	repoRoot, err := f.git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	relSpecs, err := filepath.Rel(repoRoot, f.specsDir(repoRoot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve specs directory: %w", err)
+	}
+
+	worktrees, err := f.git.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+
+	features := []models.FeatureWorktree{}
+	for _, worktree := range worktrees {
+		if !f.isFeatureBranch(worktree.Branch) {
+			continue
+		}
+
+		featureDir := filepath.Join(worktree.Path, relSpecs, f.scheme.DirName(worktree.Branch))
+		hasSpec, _ := f.filesystem.FileExists(filepath.Join(featureDir, "spec.md"))
+		features = append(features, models.FeatureWorktree{
+			Worktree:   worktree,
+			FeatureDir: featureDir,
+			HasSpec:    hasSpec,
+		})
+	}
+
+	return features, nil
+}
+
+// RemoveWorktree removes the worktree of a feature. The selector is the
+// worktree path, its branch, or a feature number or slug.
+func (f *FeatureService) RemoveWorktree(selector string, opts FeatureWorktreeRemoveOptions) (*models.FeatureWorktree, error) {
+	worktrees, err := f.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := f.findWorktree(worktrees, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.git.RemoveWorktree(worktree.Path, opts.Force); err != nil {
+		return nil, err
+	}
+
+	if opts.DeleteBranch {
+		if err := f.git.DeleteBranch(worktree.Branch, opts.Force); err != nil {
+			return nil, err
+		}
+	}
+
+	return &worktree, nil
+}
+
+// findWorktree picks the feature worktree matching a selector. Paths, branch
+// and directory names match exactly; numbers, tickets and slugs must be unique.
+func (f *FeatureService) findWorktree(worktrees []models.FeatureWorktree, selector string) (models.FeatureWorktree, error) {
+	absSelector, _ := filepath.Abs(selector)
+	for _, worktree := range worktrees {
+		if worktree.Path == absSelector || worktree.Branch == selector || f.scheme.DirName(worktree.Branch) == selector {
+			return worktree, nil
+		}
+	}
+
+	number, isNumber := f.selectorNumber(selector)
+	matches := []models.FeatureWorktree{}
+	branches := []string{}
+	for _, worktree := range worktrees {
+		parsed, _ := f.scheme.Parse(worktree.Branch)
+		if (isNumber && parsed.HasNumber && parsed.Number == number) ||
+			strings.EqualFold(parsed.Ticket, selector) || parsed.Slug == selector {
+			matches = append(matches, worktree)
+			branches = append(branches, worktree.Branch)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return models.FeatureWorktree{}, fmt.Errorf("%w: no feature worktree matches %q", models.ErrFeatureNotFound, selector)
+	case 1:
+		return matches[0], nil
+	default:
+		return models.FeatureWorktree{}, fmt.Errorf("%w: %q matches %s", models.ErrFeatureAmbiguous, selector, strings.Join(branches, ", "))
+	}
+}